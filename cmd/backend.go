@@ -2,17 +2,37 @@ package cmd
 
 import (
 	"context"
+	"crypto/rand"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"survival/internal/adapters/handler/websocket"
+	"survival/internal/adapters/repository/userstore"
+	"survival/internal/engine"
+	"survival/internal/engine/system"
+	"survival/internal/services"
 
 	"github.com/spf13/cobra"
 )
 
-var port string
+var (
+	port         string
+	maxPlayers   int
+	botCount     int
+	gameMode     string
+	waves        int
+	friendlyFire string
+	sessionGrace time.Duration
+	usersFile    string
+	authSecret   string
+	tokenTTL     time.Duration
+)
+
+// authSecretEnv holds the secret login tokens are signed with, when not given by flag.
+const authSecretEnv = "SURVIVAL_AUTH_SECRET"
 
 var backendCmd = &cobra.Command{
 	Use:   "backend",
@@ -22,7 +42,40 @@ var backendCmd = &cobra.Command{
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		srv := websocket.NewServer(ctx, port)
+		roomConfig := services.RoomConfig{
+			MaxPlayers: maxPlayers,
+			BotCount:   botCount,
+			Game: engine.GameConfig{
+				Mode:          engine.GameModeDeathmatch,
+				SurvivalWaves: waves,
+				FriendlyFire:  system.FriendlyFire(friendlyFire),
+			},
+			Mode:               services.ModeName(gameMode),
+			SessionGracePeriod: sessionGrace,
+		}
+		// survival replaces the rounds with its own waves
+		if engine.GameMode(gameMode) == engine.GameModeSurvival {
+			roomConfig.Game.Mode = engine.GameModeSurvival
+			roomConfig.Mode = ""
+		}
+
+		users, err := userstore.NewJSONUserStore(usersFile)
+		if err != nil {
+			log.Fatalf("Failed to load users: %v", err)
+		}
+		if users.Len() == 0 {
+			log.Printf("No users in %s, nobody can log in until one is added with `survival user add`", usersFile)
+		}
+		secret, err := tokenSecret()
+		if err != nil {
+			log.Fatalf("Failed to make up a login token secret: %v", err)
+		}
+		tokens, err := services.NewTokenAuthority(secret, tokenTTL)
+		if err != nil {
+			log.Fatalf("Failed to set up login tokens: %v", err)
+		}
+
+		srv := websocket.NewServer(ctx, port, roomConfig, users, tokens)
 
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -44,7 +97,35 @@ var backendCmd = &cobra.Command{
 	},
 }
 
+// tokenSecret returns the secret of the login tokens from the flag or the
+// environment. Without one a random secret is made up, so the tokens issued
+// are only good until the server restarts.
+func tokenSecret() ([]byte, error) {
+	if authSecret == "" {
+		authSecret = os.Getenv(authSecretEnv)
+	}
+	if authSecret != "" {
+		return []byte(authSecret), nil
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	log.Printf("No %s set, login tokens are signed with a random secret until the server restarts", authSecretEnv)
+	return secret, nil
+}
+
 func init() {
 	rootCmd.AddCommand(backendCmd)
 	backendCmd.Flags().StringVarP(&port, "port", "p", "3033", "Port to run the server on")
+	backendCmd.Flags().IntVar(&maxPlayers, "max-players", services.DefaultMaxPlayers, "Maximum number of players and bots per room, 0 for unlimited")
+	backendCmd.Flags().IntVar(&botCount, "bots", services.DefaultBotCount, "Number of players bots fill each room up to")
+	backendCmd.Flags().StringVar(&gameMode, "mode", string(engine.GameModeDeathmatch), "Game mode of the rooms: deathmatch, team_deathmatch, last_man_standing, battle_royale or survival")
+	backendCmd.Flags().StringVar(&friendlyFire, "friendly-fire", string(system.FriendlyFireOff), "Damage between teammates: off, on or reflected")
+	backendCmd.Flags().StringVar(&usersFile, "users", "users.json", "File of the users allowed to log in")
+	backendCmd.Flags().StringVar(&authSecret, "auth-secret", "", "Secret login tokens are signed with, defaults to $"+authSecretEnv)
+	backendCmd.Flags().DurationVar(&tokenTTL, "token-ttl", services.DefaultTokenTTL, "How long login tokens are valid")
+	backendCmd.Flags().DurationVar(&sessionGrace, "session-grace", services.DefaultSessionGracePeriod, "How long the player of a disconnected session waits to be resumed before it is removed")
+	backendCmd.Flags().IntVar(&waves, "waves", engine.DefaultGameConfig().SurvivalWaves, "Number of waves to survive in survival mode")
 }
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -11,8 +15,13 @@ import (
 	"survival/internal/terminal/state"
 )
 
+// passwordEnv holds the password to log in with, when not given by flag.
+const passwordEnv = "SURVIVAL_PASSWORD"
+
 func init() {
 	rootCmd.AddCommand(termCmd)
+	termCmd.Flags().StringVar(&terminal.AppDefaultConfig.Username, "user", "", "User to log in to the server as, asked for when left out")
+	termCmd.Flags().StringVar(&terminal.AppDefaultConfig.Password, "password", "", "Password to log in with, defaults to $"+passwordEnv+" and is asked for when left out")
 }
 
 // termCmd represents the term command
@@ -24,6 +33,11 @@ var termCmd = &cobra.Command{
 }
 
 func RunTermKai(cmd *cobra.Command, args []string) {
+	if err := askCredentials(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	logger, logFile, err := newLogger("term.log")
 	if err != nil {
 		panic(err)
@@ -89,6 +103,36 @@ func RunTermKai(cmd *cobra.Command, args []string) {
 	gameManager.Run()
 }
 
+// askCredentials asks for the username and password to log in with, unless
+// given by flag or environment. It is done before the terminal goes raw.
+func askCredentials() error {
+	config := &terminal.AppDefaultConfig
+	if config.Password == "" {
+		config.Password = os.Getenv(passwordEnv)
+	}
+
+	if config.Username == "" {
+		fmt.Fprint(os.Stderr, "Username: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read username: %w", err)
+		}
+		config.Username = strings.TrimSpace(line)
+	}
+	if config.Password == "" {
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+		config.Password = password
+	}
+
+	if config.Username == "" || config.Password == "" {
+		return errors.New("a username and password are needed to log in")
+	}
+	return nil
+}
+
 func newLogger(filePath string) (logger *slog.Logger, file *os.File, err error) {
 	file, err = os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
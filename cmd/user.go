@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"survival/internal/adapters/repository/userstore"
+)
+
+var (
+	userName     string
+	userPassword string
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage the users allowed to log in to the server",
+}
+
+var userAddCmd = &cobra.Command{
+	Use:   "add USERNAME",
+	Short: "Add a user or change its name and password",
+	Long:  `Add a user to the user file of the server, or change the name and password of an existing one. The password is asked for unless given by flag.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := userstore.NewJSONUserStore(usersFile)
+		if err != nil {
+			return err
+		}
+
+		password := userPassword
+		if password == "" {
+			if password, err = readPassword(); err != nil {
+				return err
+			}
+		}
+
+		if err := store.Add(args[0], userName, password); err != nil {
+			return err
+		}
+		fmt.Printf("User %s saved to %s\n", args[0], usersFile)
+		return nil
+	},
+}
+
+// readPassword asks for a password on the terminal without echoing it.
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return string(password), nil
+}
+
+func init() {
+	rootCmd.AddCommand(userCmd)
+	userCmd.AddCommand(userAddCmd)
+	userCmd.PersistentFlags().StringVar(&usersFile, "users", "users.json", "File of the users allowed to log in")
+	userAddCmd.Flags().StringVar(&userName, "name", "", "Name shown in game, defaults to the username")
+	userAddCmd.Flags().StringVar(&userPassword, "password", "", "Password of the user, asked for when left out")
+}
@@ -1,30 +1,99 @@
 package websocket
 
 import (
+	"sync"
+	"time"
+
 	"github.com/gorilla/websocket"
 
 	"survival/internal/engine/ports"
+	"survival/internal/utils"
 )
 
-// websocketConnection wraps gorilla/websocket.Conn to implement protocol.RawConnection
+// writeWait is how long a ping or pong may take to be written.
+const writeWait = time.Second
+
+// websocketConnection wraps gorilla/websocket.Conn to implement protocol.RawConnection.
+// It pings the client every ports.PingInterval, measuring the round trip from
+// the pongs, and fails reads once the client was silent for ports.IdleTimeout.
 type websocketConnection struct {
-	conn *websocket.Conn
+	conn        *websocket.Conn
+	messageType int
+	rtt         utils.RTTStats
+
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
-// NewWebSocketConnection creates a new websocket connection wrapper
-func NewWebSocketConnection(conn *websocket.Conn) ports.RawConnection {
-	return &websocketConnection{conn: conn}
+// NewWebSocketConnection creates a new websocket connection wrapper, writing
+// binary messages for binary codecs and text messages otherwise
+func NewWebSocketConnection(conn *websocket.Conn, binary bool) ports.RawConnection {
+	messageType := websocket.TextMessage
+	if binary {
+		messageType = websocket.BinaryMessage
+	}
+	wc := &websocketConnection{conn: conn, messageType: messageType, done: make(chan struct{})}
+
+	conn.SetPongHandler(func(data string) error {
+		if sent, _, ok := utils.ParsePong([]byte(data)); ok {
+			wc.rtt.Add(time.Since(sent))
+		}
+		return wc.extendDeadline()
+	})
+	// clients ping too, to measure their round trip and the server clock
+	conn.SetPingHandler(func(data string) error {
+		err := conn.WriteControl(websocket.PongMessage, utils.PongPayload([]byte(data), time.Now()), time.Now().Add(writeWait))
+		if err != nil && err != websocket.ErrCloseSent {
+			return err
+		}
+		return wc.extendDeadline()
+	})
+	wc.extendDeadline()
+
+	go wc.pingLoop()
+
+	return wc
 }
 
 func (wc *websocketConnection) ReadMessage() ([]byte, error) {
 	_, data, err := wc.conn.ReadMessage()
+	if err == nil {
+		err = wc.extendDeadline()
+	}
 	return data, err
 }
 
 func (wc *websocketConnection) WriteMessage(data []byte) error {
-	return wc.conn.WriteMessage(websocket.TextMessage, data)
+	return wc.conn.WriteMessage(wc.messageType, data)
 }
 
 func (wc *websocketConnection) Close() error {
+	wc.closeOnce.Do(func() {
+		close(wc.done)
+	})
 	return wc.conn.Close()
 }
+
+func (wc *websocketConnection) RTT() ports.RTT {
+	return wc.rtt.Stats()
+}
+
+// extendDeadline gives the client another ports.IdleTimeout to be heard from.
+func (wc *websocketConnection) extendDeadline() error {
+	return wc.conn.SetReadDeadline(time.Now().Add(ports.IdleTimeout))
+}
+
+func (wc *websocketConnection) pingLoop() {
+	ticker := time.NewTicker(ports.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// a failed ping is left to the read deadline
+			wc.conn.WriteControl(websocket.PingMessage, utils.PingPayload(time.Now()), time.Now().Add(writeWait))
+		case <-wc.done:
+			return
+		}
+	}
+}
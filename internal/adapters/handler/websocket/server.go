@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -20,6 +22,8 @@ type server struct {
 	hub      *services2.Hub
 	http     *http.Server
 	upgrader websocket.Upgrader
+	users    services2.UserStore
+	tokens   *services2.TokenAuthority
 }
 
 func (s *server) Start() error {
@@ -51,6 +55,8 @@ func (s *server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// ConnectionRequest is the connection a client asks for. ClientID and Name
+// are taken from its login token, never from the request itself.
 type ConnectionRequest struct {
 	GameName  string `json:"game_name"`
 	ClientID  string `json:"client_id"`
@@ -58,11 +64,59 @@ type ConnectionRequest struct {
 	SessionID string `json:"session_id,omitempty"`
 }
 
+// handleLogin checks the credentials posted and answers with a login token.
+func (s *server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var login ports.LoginRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&login); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	identity, err := s.users.Authenticate(login.Username, login.Password)
+	if err != nil {
+		log.Printf("Login failed for user %q: %v", login.Username, err)
+		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
+		return
+	}
+
+	token, expiresAt := s.tokens.Issue(identity, time.Now())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(ports.LoginResponse{
+		Token:     token,
+		ClientID:  identity.UserID,
+		Name:      identity.Name,
+		ExpiresAt: expiresAt.Unix(),
+	}); err != nil {
+		log.Printf("Failed to write login response: %v", err)
+	}
+}
+
+// authenticate verifies the login token of a request, sent as a bearer token
+// or, for clients that cannot set headers on the upgrade, as the token query
+// parameter.
+func (s *server) authenticate(r *http.Request) (services2.Identity, error) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		return services2.Identity{}, services2.ErrInvalidToken
+	}
+	return s.tokens.Verify(token, time.Now())
+}
+
 func (s *server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Add CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-EnvelopeType, Connection, Upgrade, Sec-WebSocket-Key, Sec-WebSocket-Version, Sec-WebSocket-Protocol")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-EnvelopeType, Connection, Upgrade, Sec-WebSocket-Key, Sec-WebSocket-Version, Sec-WebSocket-Protocol, Authorization")
 
 	// Handle preflight OPTIONS request
 	if r.Method == "OPTIONS" {
@@ -70,14 +124,19 @@ func (s *server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	identity, err := s.authenticate(r)
+	if err != nil {
+		log.Printf("Rejected WebSocket connection from %s: %v", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
 	// For WebSocket upgrade, get connection data from query parameters
 	var connReq ConnectionRequest
 	if r.Header.Get("Upgrade") == "websocket" {
 		// WebSocket connection - use query parameters
 		connReq = ConnectionRequest{
-			ClientID:  r.URL.Query().Get("client_id"),
 			GameName:  r.URL.Query().Get("game_name"),
-			Name:      r.URL.Query().Get("name"),
 			SessionID: r.URL.Query().Get("session_id"),
 		}
 	} else {
@@ -88,11 +147,9 @@ func (s *server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Validate required fields
-	if connReq.ClientID == "" {
-		http.Error(w, "client_id is required", http.StatusBadRequest)
-		return
-	}
+	// the verified identity is bound to the session, whatever the client claims
+	connReq.ClientID = identity.UserID
+	connReq.Name = identity.Name
 
 	// Set defaults
 	if connReq.GameName == "" {
@@ -102,7 +159,8 @@ func (s *server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		connReq.Name = connReq.ClientID
 	}
 
-	// Upgrade HTTP connection to WebSocket
+	// Upgrade HTTP connection to WebSocket, the upgrader picks the first of
+	// its subprotocols the client offers
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
@@ -110,25 +168,35 @@ func (s *server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create connection wrapper that implements protocol.RawConnection
-	wsConn := NewWebSocketConnection(conn)
+	codec, binary := negotiatedCodec(conn.Subprotocol())
+	wsConn := NewWebSocketConnection(conn, binary)
 
 	// Dispatch the connection to the hub
-	if err := s.hub.DispatchConnection(r.Context(), wsConn, connReq.GameName, connReq.ClientID, connReq.Name, connReq.SessionID); err != nil {
+	if err := s.hub.DispatchConnection(r.Context(), wsConn, codec, connReq.GameName, connReq.ClientID, connReq.Name, connReq.SessionID); err != nil {
 		log.Printf("Failed to dispatch connection: %v", err)
 
 		// Handle specific error types
 		if errors.Is(err, services2.ErrClientSessionValidationFailed) {
 			// Send session invalidation message before closing
-			s.sendSessionInvalidMessage(conn, "Session validation failed")
+			s.sendSessionInvalidMessage(wsConn, codec, "Session validation failed")
 		}
 
-		conn.Close()
+		wsConn.Close()
 		return
 	}
 }
 
+// negotiatedCodec returns the codec of a subprotocol and whether it writes
+// binary messages. Clients that negotiated none get JSON, kept for debugging.
+func negotiatedCodec(subprotocol string) (ports.Codec, bool) {
+	if subprotocol == ports.SubprotocolBinary {
+		return utils.NewBinaryCodec(), true
+	}
+	return utils.NewJsonCodec(), false
+}
+
 // sendSessionInvalidMessage sends an error_invalid_session message to the client
-func (s *server) sendSessionInvalidMessage(conn *websocket.Conn, message string) {
+func (s *server) sendSessionInvalidMessage(conn ports.RawConnection, codec ports.Codec, message string) {
 	errorEnvelope := ports.ResponseEnvelope{
 		EnvelopeType: ports.ErrInvalidSession,
 	}
@@ -140,7 +208,7 @@ func (s *server) sendSessionInvalidMessage(conn *websocket.Conn, message string)
 		Message: message,
 	}
 
-	payloadBytes, err := json.Marshal(errorPayload)
+	payloadBytes, err := codec.Encode(errorPayload)
 	if err != nil {
 		log.Printf("Failed to marshal error payload: %v", err)
 		return
@@ -149,7 +217,12 @@ func (s *server) sendSessionInvalidMessage(conn *websocket.Conn, message string)
 	errorEnvelope.Payload = payloadBytes
 
 	// Send the error message
-	if err := conn.WriteJSON(errorEnvelope); err != nil {
+	data, err := codec.Encode(errorEnvelope)
+	if err != nil {
+		log.Printf("Failed to encode session invalid message: %v", err)
+		return
+	}
+	if err := conn.WriteMessage(data); err != nil {
 		log.Printf("Failed to send session invalid message: %v", err)
 	} else {
 		log.Printf("Sent session invalid message to client: %s", message)
@@ -159,13 +232,62 @@ func (s *server) sendSessionInvalidMessage(conn *websocket.Conn, message string)
 	time.Sleep(100 * time.Millisecond)
 }
 
+// sessionStatus is a session as listed by /admin/sessions.
+type sessionStatus struct {
+	SessionID string    `json:"session_id"`
+	ClientID  string    `json:"client_id"`
+	Name      string    `json:"name"`
+	Connected bool      `json:"connected"`
+	LastSeen  time.Time `json:"last_seen"`
+	RTTMs     float64   `json:"rtt_ms"`
+	JitterMs  float64   `json:"jitter_ms"`
+	Pings     int       `json:"pings"`
+}
+
+// handleSessions lists the sessions with their round trip times. It only
+// answers requests from the server host itself.
+func (s *server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err != nil || !net.ParseIP(host).IsLoopback() {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	sessions := s.hub.Sessions()
+	statuses := make([]sessionStatus, 0, len(sessions))
+	for _, session := range sessions {
+		rtt := session.Client.RTT()
+		statuses = append(statuses, sessionStatus{
+			SessionID: session.SessionID,
+			ClientID:  session.ClientID,
+			Name:      session.Client.Name(),
+			Connected: !session.Client.IsClosed(),
+			LastSeen:  session.LastSeen,
+			RTTMs:     milliseconds(rtt.Smoothed),
+			JitterMs:  milliseconds(rtt.Jitter),
+			Pings:     rtt.Samples,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		log.Printf("Failed to write sessions: %v", err)
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
 func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
 
-func NewServer(ctx context.Context, port string) ports.Server {
+// NewServer creates the game server. Clients log in against the user store
+// for a token of the token authority, which their connections must present.
+func NewServer(ctx context.Context, port string, roomConfig services2.RoomConfig, users services2.UserStore, tokens *services2.TokenAuthority) ports.Server {
 	upgrader := websocket.Upgrader{
+		Subprotocols: []string{ports.SubprotocolBinary, ports.SubprotocolJSON},
 		CheckOrigin: func(r *http.Request) bool {
 			return true // TODO: Add proper origin validation
 		},
@@ -174,13 +296,17 @@ func NewServer(ctx context.Context, port string) ports.Server {
 	idGen := utils.NewSequentialIDGenerator("session")
 
 	s := &server{
-		hub:      services2.NewHub(ctx, idGen),
+		hub:      services2.NewHub(ctx, idGen, roomConfig),
 		upgrader: upgrader,
+		users:    users,
+		tokens:   tokens,
 	}
 
 	mux := http.NewServeMux()
+	mux.HandleFunc("/login", s.handleLogin)
 	mux.HandleFunc("/ws", s.handleWebSocket)
 	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/admin/sessions", s.handleSessions)
 
 	s.http = &http.Server{
 		Addr:    ":" + port,
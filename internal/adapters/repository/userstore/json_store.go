@@ -0,0 +1,134 @@
+package userstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"survival/internal/services"
+)
+
+// dummyHash is compared against for unknown users, so that they take as long
+// to reject as wrong passwords and do not give away which users exist.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// User is a user as stored in the file, with the bcrypt hash of its password.
+type User struct {
+	Username     string `json:"username"`
+	Name         string `json:"name,omitempty"`
+	PasswordHash string `json:"password_hash"`
+}
+
+type userFile struct {
+	Users []User `json:"users"`
+}
+
+// JSONUserStore keeps the users of the server in a local JSON file:
+//
+//	{"users": [{"username": "player1", "name": "Player 1", "password_hash": "$2a$10$..."}]}
+//
+// It is safe for concurrent use.
+type JSONUserStore struct {
+	path  string
+	mu    sync.RWMutex
+	users map[string]User
+}
+
+// NewJSONUserStore loads the users of the file at path. A missing file is an
+// empty store, created on the first Add.
+func NewJSONUserStore(path string) (*JSONUserStore, error) {
+	store := &JSONUserStore{path: path, users: make(map[string]User)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user file %s: %w", path, err)
+	}
+
+	var file userFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse user file %s: %w", path, err)
+	}
+	for _, user := range file.Users {
+		if user.Username == "" || user.PasswordHash == "" {
+			return nil, fmt.Errorf("user file %s has a user without username or password hash", path)
+		}
+		store.users[user.Username] = user
+	}
+
+	return store, nil
+}
+
+// Len returns the number of users.
+func (s *JSONUserStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.users)
+}
+
+// Authenticate checks the password of a user, its username being its user ID.
+func (s *JSONUserStore) Authenticate(username, password string) (services.Identity, error) {
+	s.mu.RLock()
+	user, exist := s.users[username]
+	s.mu.RUnlock()
+
+	hash := dummyHash
+	if exist {
+		hash = []byte(user.PasswordHash)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil || !exist {
+		return services.Identity{}, services.ErrInvalidCredentials
+	}
+
+	name := user.Name
+	if name == "" {
+		name = user.Username
+	}
+	return services.Identity{UserID: user.Username, Name: name}, nil
+}
+
+// Add adds a user or changes the name and password of an existing one, and
+// writes the file.
+func (s *JSONUserStore) Add(username, name, password string) error {
+	if username == "" || password == "" {
+		return errors.New("username and password cannot be empty")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password of %s: %w", username, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[username] = User{Username: username, Name: name, PasswordHash: string(hash)}
+	return s.save()
+}
+
+// save writes the users sorted by username. Must be called with mu held.
+func (s *JSONUserStore) save() error {
+	file := userFile{Users: make([]User, 0, len(s.users))}
+	for _, user := range s.users {
+		file.Users = append(file.Users, user)
+	}
+	slices.SortFunc(file.Users, func(a, b User) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode users: %w", err)
+	}
+	if err := os.WriteFile(s.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write user file %s: %w", s.path, err)
+	}
+	return nil
+}
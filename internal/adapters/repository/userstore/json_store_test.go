@@ -0,0 +1,52 @@
+package userstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"survival/internal/services"
+)
+
+func TestJSONUserStore_AddAndAuthenticate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+
+	store, err := NewJSONUserStore(path)
+	if err != nil {
+		t.Fatalf("NewJSONUserStore() on a missing file failed: %v", err)
+	}
+	if err := store.Add("alice", "Alice", "hunter2"); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	// the user is written to the file
+	reloaded, err := NewJSONUserStore(path)
+	if err != nil {
+		t.Fatalf("NewJSONUserStore() failed: %v", err)
+	}
+	if reloaded.Len() != 1 {
+		t.Errorf("Expected one user, got %d", reloaded.Len())
+	}
+	identity, err := reloaded.Authenticate("alice", "hunter2")
+	if err != nil || identity.UserID != "alice" || identity.Name != "Alice" {
+		t.Errorf("Authenticate() = %+v, %v, want alice", identity, err)
+	}
+
+	for _, credentials := range [][2]string{{"alice", "wrong"}, {"bob", "hunter2"}} {
+		if _, err := reloaded.Authenticate(credentials[0], credentials[1]); !errors.Is(err, services.ErrInvalidCredentials) {
+			t.Errorf("Authenticate(%q, %q) = %v, want ErrInvalidCredentials", credentials[0], credentials[1], err)
+		}
+	}
+}
+
+func TestNewJSONUserStore_RejectsUsersWithoutHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	if err := os.WriteFile(path, []byte(`{"users": [{"username": "alice"}]}`), 0600); err != nil {
+		t.Fatalf("Failed to write user file: %v", err)
+	}
+
+	if _, err := NewJSONUserStore(path); err == nil {
+		t.Error("Expected a user without password hash rejected")
+	}
+}
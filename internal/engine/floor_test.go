@@ -0,0 +1,123 @@
+package engine_test
+
+import (
+	"testing"
+
+	"survival/internal/engine"
+	"survival/internal/engine/ports"
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+func newFloorGame(t *testing.T, kind string) *engine.Game {
+	t.Helper()
+	game, err := engine.NewGame(&engine.MapConfig{
+		Dimensions: vector.Vector2D{X: 100, Y: 100},
+		GridSize:   10,
+		Floors: []engine.FloorConfig{
+			// only upstairs, a wall runs across the whole map
+			{ID: "upstairs", Walls: []engine.WallConfig{
+				{ID: "hallway", Center: vector.Vector2D{X: 50, Y: 30}, HalfSize: vector.Vector2D{X: 50, Y: 1}},
+			}},
+		},
+		Stairs: []engine.StairConfig{
+			{ID: "stairwell", Kind: kind, Center: vector.Vector2D{X: 50, Y: 60}, HalfSize: vector.Vector2D{X: 5, Y: 2}, From: 0, To: 1},
+		},
+		SpawnPoints: []engine.SpawnPoint{
+			{Position: vector.Vector2D{X: 50, Y: 50}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	return game
+}
+
+// walk moves the player for the given number of ticks and reports whether it
+// changed floor on the way.
+func walk(game *engine.Game, id state.EntityID, input ports.PlayerInput, ticks int) bool {
+	changed := false
+	for range ticks {
+		game.SetPlayerInput(id, input)
+		game.Update(1.0 / 60.0)
+		for _, change := range game.FloorChanges() {
+			changed = changed || change.ID == id
+		}
+	}
+	return changed
+}
+
+func TestFloors_StairsScopeWallsAndViews(t *testing.T) {
+	game := newFloorGame(t, engine.StairKindStairs)
+	alice := joinPlayer(t, game)
+	bob := joinPlayer(t, game)
+
+	if game.FloorCount() != 2 {
+		t.Fatalf("Expected 2 floors, got %d", game.FloorCount())
+	}
+	if len(game.FloorStatics(state.GroundFloor)) != 0 || len(game.FloorStatics(1)) != 1 {
+		t.Fatalf("Expected the wall upstairs only, got %d downstairs and %d upstairs",
+			len(game.FloorStatics(state.GroundFloor)), len(game.FloorStatics(1)))
+	}
+
+	game.Update(1.0 / 60.0)
+	if snapshot, _ := game.PlayerSnapshotWithLocation(alice); len(snapshot.Views) != 1 {
+		t.Fatalf("Expected alice to see bob next to her, got %+v", snapshot.Views)
+	}
+
+	// alice walks south onto the stairs
+	if !walk(game, alice, ports.PlayerInput{MoveVertical: 1}, 120) {
+		t.Fatal("Expected alice to take the stairs")
+	}
+	if game.Floor(alice) != 1 || game.Floor(bob) != state.GroundFloor {
+		t.Fatalf("Expected alice upstairs and bob downstairs, got %d and %d", game.Floor(alice), game.Floor(bob))
+	}
+	if snapshot, _ := game.PlayerSnapshotWithLocation(alice); len(snapshot.Views) != 0 {
+		t.Errorf("Expected alice not to see bob on another floor, got %+v", snapshot.Views)
+	}
+
+	// both walk north, only alice runs into the wall upstairs
+	if walk(game, alice, ports.PlayerInput{MoveVertical: -1}, 600) {
+		t.Error("Expected leaving the stairs not to change floor")
+	}
+	walk(game, bob, ports.PlayerInput{MoveVertical: -1}, 480)
+
+	alicePos, _ := game.PlayerSnapshotWithLocation(alice)
+	bobPos, _ := game.PlayerSnapshotWithLocation(bob)
+	if alicePos.Player.Position.Y < 31 {
+		t.Errorf("Expected the wall upstairs to stop alice, got y %.2f", alicePos.Player.Position.Y)
+	}
+	if bobPos.Player.Position.Y > 29 {
+		t.Errorf("Expected bob to walk past where the wall is upstairs, got y %.2f", bobPos.Player.Position.Y)
+	}
+}
+
+func TestFloors_LadderHoldsClimber(t *testing.T) {
+	game := newFloorGame(t, engine.StairKindLadder)
+	id := joinPlayer(t, game)
+
+	if !walk(game, id, ports.PlayerInput{MoveVertical: 1}, 120) {
+		t.Fatal("Expected the player to climb the ladder")
+	}
+	game.Update(1.0 / 60.0)
+	snapshot, _ := game.PlayerSnapshotWithLocation(id)
+	if !snapshot.Player.Effects.Has(state.EffectStun) {
+		t.Errorf("Expected the climber held in place, got %+v", snapshot.Player.Effects)
+	}
+}
+
+func TestFloors_RejectsStairToMissingFloor(t *testing.T) {
+	_, err := engine.NewGame(&engine.MapConfig{
+		Dimensions: vector.Vector2D{X: 100, Y: 100},
+		GridSize:   10,
+		Stairs: []engine.StairConfig{
+			{ID: "nowhere", Center: vector.Vector2D{X: 50, Y: 60}, HalfSize: vector.Vector2D{X: 5, Y: 2}, From: 0, To: 1},
+		},
+		SpawnPoints: []engine.SpawnPoint{
+			{Position: vector.Vector2D{X: 50, Y: 50}},
+		},
+	})
+	if err == nil {
+		t.Error("Expected a stair to a missing floor to be rejected")
+	}
+}
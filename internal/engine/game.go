@@ -2,43 +2,165 @@ package engine
 
 import (
 	"fmt"
+	"math"
+	"math/rand/v2"
 
+	"survival/internal/engine/pathfinding"
 	"survival/internal/engine/ports"
 	"survival/internal/engine/state"
 	"survival/internal/engine/system"
+	"survival/internal/utils"
 )
 
+type GameMode string
+
+const (
+	// GameModeDeathmatch is a free for all where dead players respawn.
+	GameModeDeathmatch GameMode = "deathmatch"
+	// GameModeSurvival pits the players against waves of hostile NPCs.
+	GameModeSurvival GameMode = "survival"
+)
+
+// GameConfig selects the rules a game is played with.
+type GameConfig struct {
+	Mode GameMode
+	// SurvivalWaves is the number of waves to survive in GameModeSurvival.
+	SurvivalWaves int
+	// FriendlyFire decides what damage between teammates does, empty means off.
+	FriendlyFire system.FriendlyFire
+	// SafeZone adds a battle royale safe zone, idle until StartZone is called.
+	SafeZone bool
+}
+
+const defaultSurvivalWaves = 5
+
+func DefaultGameConfig() GameConfig {
+	return GameConfig{
+		Mode:          GameModeDeathmatch,
+		SurvivalWaves: defaultSurvivalWaves,
+		FriendlyFire:  system.FriendlyFireOff,
+	}
+}
+
 type Game struct {
-	world     *state.World
-	mapConfig *MapConfig
-	systems   *state.SystemManager
+	world          *state.World
+	mapConfig      *MapConfig
+	config         GameConfig
+	systems        *state.SystemManager
+	pathfinder     *pathfinding.Pathfinder
+	respawn        *system.RespawnSystem // nil in survival games
+	zone           *system.ZoneSystem    // nil without a safe zone
+	spawnPositions []state.Position
+	itemIDs        *utils.SequentialIDGenerator
 }
 
 func NewGame(mapConfig *MapConfig) (*Game, error) {
+	return NewGameWithConfig(mapConfig, DefaultGameConfig())
+}
+
+// NewGameWithConfig creates a game with the given rules. An empty mode means GameModeDeathmatch.
+func NewGameWithConfig(mapConfig *MapConfig, config GameConfig) (*Game, error) {
+	if config.Mode == "" {
+		config.Mode = GameModeDeathmatch
+	}
+	if config.Mode != GameModeDeathmatch && config.Mode != GameModeSurvival {
+		return nil, fmt.Errorf("unknown game mode %q", config.Mode)
+	}
+	switch config.FriendlyFire {
+	case "":
+		config.FriendlyFire = system.FriendlyFireOff
+	case system.FriendlyFireOff, system.FriendlyFireOn, system.FriendlyFireReflected:
+	default:
+		return nil, fmt.Errorf("unknown friendly fire rule %q", config.FriendlyFire)
+	}
+
 	gridWidth := int(mapConfig.Dimensions.X / mapConfig.GridSize)
 	gridHeight := int(mapConfig.Dimensions.Y / mapConfig.GridSize)
 
 	world := state.NewWorld(mapConfig.GridSize, gridWidth, gridHeight)
 	world.Width = mapConfig.Dimensions.X
 	world.Height = mapConfig.Dimensions.Y
+	world.AmbientLight = 1 - mapConfig.Darkness
+	for range mapConfig.Floors {
+		world.AddFloor()
+	}
+	stairs, err := mapStairs(mapConfig)
+	if err != nil {
+		return nil, err
+	}
+	world.Stairs = stairs
+
+	spawnPositions := make([]state.Position, len(mapConfig.SpawnPoints))
+	spawnPoints := make([]system.SpawnPoint, len(mapConfig.SpawnPoints))
+	for i, sp := range mapConfig.SpawnPoints {
+		spawnPositions[i] = state.Position{X: sp.Position.X, Y: sp.Position.Y}
+		spawnPoints[i] = system.SpawnPoint{Position: spawnPositions[i], Team: state.Team(sp.Team)}
+	}
+
+	pathfinder := pathfinding.NewPathfinder(world)
 
 	systems := state.NewSystemManager(world)
+	systems.Register(system.NewBotSystem(world, pathfinder))
+	systems.Register(system.NewEnemySystem(world, pathfinder))
+	systems.Register(system.NewEffectSystem(world))
+	var zone *system.ZoneSystem
+	if config.SafeZone {
+		zone = system.NewZoneSystem(world, defaultZoneStages())
+		systems.Register(zone)
+	}
 	systems.Register(system.NewBasicMovementSystem(world))
+	systems.Register(system.NewPhysicsSystem(world))
+	systems.Register(system.NewFloorSystem(world))
+	combat := system.NewCombatSystem(world)
+	combat.SetFriendlyFire(config.FriendlyFire)
+	systems.Register(combat)
+	var respawn *system.RespawnSystem
+	switch config.Mode {
+	case GameModeSurvival:
+		systems.Register(system.NewWaveSystem(world, survivalWaveConfig(config.SurvivalWaves), enemySpawnPositions(mapConfig), spawnPositions))
+	default:
+		respawn = system.NewRespawnSystem(world, spawnPoints, defaultRespawnDelay, state.Health(defaultPlayerHealth))
+		systems.Register(respawn)
+	}
+	systems.Register(system.NewLightingSystem(world))
+	systems.Register(system.NewVisibilitySystem(world))
+	systems.Register(system.NewNoiseSystem(world))
 
 	g := &Game{
-		world:     world,
-		mapConfig: mapConfig,
-		systems:   systems,
+		world:          world,
+		mapConfig:      mapConfig,
+		config:         config,
+		systems:        systems,
+		pathfinder:     pathfinder,
+		respawn:        respawn,
+		zone:           zone,
+		spawnPositions: spawnPositions,
+		itemIDs:        utils.NewSequentialIDGenerator("item"),
 	}
 
 	if err := g.loadMapEntities(mapConfig); err != nil {
 		return nil, err
 	}
+	if err := g.loadMapLights(mapConfig); err != nil {
+		return nil, err
+	}
 	return g, nil
 }
 
 func (g *Game) loadMapEntities(mapConfig *MapConfig) error {
-	for i, wallCfg := range mapConfig.Walls {
+	if err := g.loadWalls(state.GroundFloor, mapConfig.Walls); err != nil {
+		return err
+	}
+	for i, floorCfg := range mapConfig.Floors {
+		if err := g.loadWalls(state.Floor(i+1), floorCfg.Walls); err != nil {
+			return fmt.Errorf("floor %s: %w", floorCfg.ID, err)
+		}
+	}
+	return nil
+}
+
+func (g *Game) loadWalls(floor state.Floor, walls []WallConfig) error {
+	for i, wallCfg := range walls {
 		id, ok := g.world.Entity.Alloc()
 		if !ok {
 			return fmt.Errorf("failed to allocate entity for wall %d", i)
@@ -61,13 +183,55 @@ func (g *Game) loadMapEntities(mapConfig *MapConfig) error {
 		}
 		g.world.VerticalBody.Upsert(id, vertBody)
 
-		g.world.EntityMeta.Upsert(id, state.WallMeta)
+		if wallCfg.Destructible {
+			health := state.Health(wallCfg.Health)
+			if health == 0 {
+				health = DefaultWallHealth
+			}
+			g.world.Destructible.Upsert(id, state.Destructible{Health: health, MaxHealth: health})
+			g.world.EntityMeta.Upsert(id, state.DestructibleWallMeta)
+		} else {
+			g.world.EntityMeta.Upsert(id, state.WallMeta)
+		}
+		g.world.Floor.Upsert(id, floor)
+
+		g.world.AddStatic(id)
+	}
+	return nil
+}
+
+const (
+	defaultLightRadius    float64 = 150
+	defaultLightIntensity float64 = 1
+)
+
+func (g *Game) loadMapLights(mapConfig *MapConfig) error {
+	for _, objCfg := range mapConfig.Objects {
+		if objCfg.Type != ObjectTypeLight {
+			continue
+		}
+
+		id, ok := g.world.Entity.Alloc()
+		if !ok {
+			return fmt.Errorf("failed to allocate entity for light %s", objCfg.ID)
+		}
+
+		light := state.Light{
+			Radius:    objCfg.LightRadius,
+			Intensity: objCfg.LightIntensity,
+			Enabled:   true,
+		}
+		if light.Radius == 0 {
+			light.Radius = defaultLightRadius
+		}
+		if light.Intensity == 0 {
+			light.Intensity = defaultLightIntensity
+		}
 
-		min, max := collider.BoundingBox()
-		g.world.Grid.Add(id, state.Bounds{
-			MinX: min.X, MinY: min.Y,
-			MaxX: max.X, MaxY: max.Y,
-		}, state.LayerStatic)
+		g.world.Position.Upsert(id, state.Position{X: objCfg.Center.X, Y: objCfg.Center.Y})
+		g.world.Direction.Upsert(id, state.Direction(objCfg.Rotation))
+		g.world.Light.Upsert(id, light)
+		g.world.EntityMeta.Upsert(id, state.LightMeta)
 	}
 	return nil
 }
@@ -77,38 +241,295 @@ const (
 	defaultPlayerRotationSpeed float64 = 2
 	defaultPlayerRadius        float64 = 0.5
 	defaultPlayerHealth        int     = 100
+	defaultPlayerStamina       float64 = 100
+	defaultRespawnDelay        float64 = 3
+
+	// players reach walking speed within a couple of ticks and stop within a
+	// fifth of a unit, knockback can push them up to five times as fast
+	defaultPlayerAcceleration float64 = 200
+	defaultPlayerFriction     float64 = 60
+	defaultPlayerMaxSpeed     float64 = 25
+
+	defaultFlashlightRadius    float64 = 60
+	defaultFlashlightIntensity float64 = 0.8
+	defaultFlashlightCone      float64 = math.Pi / 6
+
+	defaultWeaponDamage       int     = 20
+	defaultWeaponRange        float64 = 40
+	defaultWeaponFireInterval float64 = 0.5
+	defaultWeaponKnockback    float64 = 8
+	defaultWeaponRecoil       float64 = 3
 )
 
+func defaultPlayerConfig(position state.Position) state.CreatePlayer {
+	return state.CreatePlayer{
+		Position:      position,
+		Direction:     0,
+		MovementSpeed: state.MovementSpeed(defaultPlayerMovementSpeed),
+		RotationSpeed: state.RotationSpeed(defaultPlayerRotationSpeed),
+		Radius:        defaultPlayerRadius,
+		Health:        state.Health(defaultPlayerHealth),
+		Flashlight: state.Light{
+			Radius:    defaultFlashlightRadius,
+			Intensity: defaultFlashlightIntensity,
+			Cone:      defaultFlashlightCone,
+		},
+		Weapon: state.Weapon{
+			Damage:       defaultWeaponDamage,
+			Range:        defaultWeaponRange,
+			FireInterval: defaultWeaponFireInterval,
+			Knockback:    defaultWeaponKnockback,
+			Recoil:       defaultWeaponRecoil,
+		},
+		Stamina:  defaultPlayerStamina,
+		Velocity: defaultPlayerVelocity(),
+	}
+}
+
+func defaultPlayerVelocity() state.Velocity {
+	return state.Velocity{
+		Acceleration: defaultPlayerAcceleration,
+		Friction:     defaultPlayerFriction,
+		MaxSpeed:     defaultPlayerMaxSpeed,
+	}
+}
+
 func (g *Game) JoinPlayer() (state.EntityID, error) {
 	spawnPoint := g.mapConfig.GetRandomSpawnPoint()
 	if spawnPoint == nil {
 		return 0, fmt.Errorf("no spawn point available")
 	}
 
-	id, ok := g.world.CreatePlayer(state.CreatePlayer{
-		Position:      state.Position{X: spawnPoint.Position.X, Y: spawnPoint.Position.Y},
-		Direction:     0,
-		MovementSpeed: state.MovementSpeed(defaultPlayerMovementSpeed),
-		RotationSpeed: state.RotationSpeed(defaultPlayerRotationSpeed),
-		Radius:        defaultPlayerRadius,
-		Health:        state.Health(defaultPlayerHealth),
-	})
+	cfg := defaultPlayerConfig(state.Position{X: spawnPoint.Position.X, Y: spawnPoint.Position.Y})
+	cfg.Inventory = g.startingInventory()
+	id, ok := g.world.CreatePlayer(cfg)
 	if !ok {
 		return 0, fmt.Errorf("failed to create player entity")
 	}
 
 	g.world.ApplyCommands()
+	system.ProtectSpawn(g.world, id)
+
+	return id, nil
+}
+
+// JoinBot adds a server controlled player at a random spawn point.
+func (g *Game) JoinBot() (state.EntityID, error) {
+	if len(g.spawnPositions) == 0 {
+		return 0, fmt.Errorf("no spawn point available")
+	}
+
+	spawn := g.spawnPositions[rand.IntN(len(g.spawnPositions))]
+	cfg := defaultPlayerConfig(spawn)
+	cfg.Inventory = g.startingInventory()
+	id, ok := g.world.CreateBot(cfg)
+	if !ok {
+		return 0, fmt.Errorf("failed to create bot entity")
+	}
+
+	g.world.ApplyCommands()
+	system.ProtectSpawn(g.world, id)
 
 	return id, nil
 }
 
+// RemoveEntity queues the entity for removal at the end of the next update.
+func (g *Game) RemoveEntity(id state.EntityID) {
+	g.world.QueueDestroy(id)
+}
+
 func (g *Game) Update(dt float64) {
+	g.world.ClearCombatEvents()
+	g.world.ClearStaticChanges()
+	g.world.ClearFloorChanges()
 	g.world.SyncInputBuffer()
 	g.systems.Update(dt)
 	g.world.ApplyCommands()
+	g.world.RecordHitboxes()
+
+	// destroyed walls open up paths. Navigation only covers the ground floor,
+	// which bots and enemies never leave, see system.FloorSystem.
+	for _, change := range g.world.StaticChanges() {
+		if change.Floor == state.GroundFloor {
+			g.pathfinder.Invalidate(change.Bounds)
+		}
+	}
+}
+
+// SetRespawnEnabled turns respawning of dead players on or off. Survival games
+// bring players back with each attempt and ignore it.
+func (g *Game) SetRespawnEnabled(enabled bool) {
+	if g.respawn != nil {
+		g.respawn.SetEnabled(enabled)
+	}
+}
+
+// RespawnAll puts every player back at a spawn point with full health, as at
+// the start of a round.
+func (g *Game) RespawnAll() {
+	if g.respawn != nil {
+		g.respawn.RespawnAll()
+		g.world.ApplyCommands()
+	}
+}
+
+// JoinTeam puts a player on a team and moves it to one of the team's spawn points.
+func (g *Game) JoinTeam(id state.EntityID, team state.Team) {
+	meta, exist := g.world.EntityMeta.Get(id)
+	if !exist {
+		return
+	}
+
+	g.world.UpdatePlayer(id, state.UpdatePlayer{
+		UpdateMeta: state.ComponentMeta | state.ComponentTeam,
+		Meta:       meta.Set(state.ComponentTeam),
+		Team:       team,
+	})
+	g.world.ApplyCommands()
+
+	if g.respawn != nil {
+		g.respawn.Respawn(id)
+		g.world.ApplyCommands()
+	}
+}
+
+// Team returns the team of a player, state.NoTeam outside of team modes.
+func (g *Game) Team(id state.EntityID) state.Team {
+	team, _ := g.world.Team.Get(id)
+	return team
+}
+
+// IsAlive reports whether the entity exists and has health left.
+func (g *Game) IsAlive(id state.EntityID) bool {
+	return g.world.Entity.IsAlive(id) && !g.world.IsDead(id)
+}
+
+// Mode returns the game mode the game was created with.
+func (g *Game) Mode() GameMode {
+	return g.config.Mode
+}
+
+// Survival returns the wave progress, only meaningful in GameModeSurvival.
+func (g *Game) Survival() (state.SurvivalState, bool) {
+	return g.world.Survival, g.config.Mode == GameModeSurvival
+}
+
+// StartZone resets the safe zone to cover the map and starts shrinking it.
+func (g *Game) StartZone() {
+	if g.zone != nil {
+		g.zone.Start()
+	}
+}
+
+// StopZone removes the safe zone until it is started again.
+func (g *Game) StopZone() {
+	if g.zone != nil {
+		g.zone.Stop()
+	}
+}
+
+// Zone returns the safe zone, only meaningful in games with a safe zone.
+func (g *Game) Zone() (state.ZoneState, bool) {
+	return g.world.Zone, g.config.SafeZone
+}
+
+// Pathfinder returns the path queries against the map's static collision data.
+// Changes to static colliders must be reported through its Invalidate.
+func (g *Game) Pathfinder() *pathfinding.Pathfinder {
+	return g.pathfinder
+}
+
+// StaticChanges returns the static colliders added or removed during the last update.
+func (g *Game) StaticChanges() []state.StaticChange {
+	return g.world.StaticChanges()
+}
+
+// Static returns a static collider of the map.
+func (g *Game) Static(id state.EntityID) (state.StaticEntity, bool) {
+	collider, exist := g.world.Collider.Get(id)
+	if !exist {
+		return state.StaticEntity{}, false
+	}
+	entity := state.StaticEntity{ID: id, Collider: collider, Floor: g.world.FloorOf(id)}
+	entity.VerticalBody, entity.HasVerticalBody = g.world.VerticalBody.Get(id)
+	return entity, true
+}
+
+// FloorCount returns the number of floors of the map, the ground floor included.
+func (g *Game) FloorCount() int {
+	return g.world.FloorCount()
+}
+
+// Floor returns the floor an entity is on.
+func (g *Game) Floor(id state.EntityID) state.Floor {
+	return g.world.FloorOf(id)
+}
+
+// FloorChanges returns the entities that moved to another floor during the last update.
+func (g *Game) FloorChanges() []state.FloorChange {
+	return g.world.FloorChanges()
+}
+
+// FloorStatics returns the static colliders of one floor.
+func (g *Game) FloorStatics(floor state.Floor) []state.StaticEntity {
+	var statics []state.StaticEntity
+	for _, entity := range g.world.StaticEntities() {
+		if entity.Floor == floor {
+			statics = append(statics, entity)
+		}
+	}
+	return statics
+}
+
+// FloorStairs returns the stairs leading away from a floor.
+func (g *Game) FloorStairs(floor state.Floor) []state.Stair {
+	var stairs []state.Stair
+	for _, stair := range g.world.Stairs {
+		if _, connected := stair.Destination(floor); connected {
+			stairs = append(stairs, stair)
+		}
+	}
+	return stairs
+}
+
+// CombatEvents returns the shots, hits and kills of the last update.
+func (g *Game) CombatEvents() []state.CombatEvent {
+	return g.world.CombatEvents()
 }
 
 func (g *Game) SetPlayerInput(entityID state.EntityID, input ports.PlayerInput) {
+	g.SetPlayerInputAt(entityID, input, 0)
+}
+
+// SetPlayerInputAt queues the input of a player who saw the world as of
+// viewTick, see Tick. Shots are resolved against the players where they were
+// then, up to a limit. The resent previous inputs are queued first, the ones
+// queued before are dropped. Only the last ports.InputRedundancy of them are
+// taken, so a client cannot flood the queue with one message.
+func (g *Game) SetPlayerInputAt(entityID state.EntityID, input ports.PlayerInput, viewTick uint64) {
+	if len(input.Previous) > ports.InputRedundancy {
+		input.Previous = input.Previous[len(input.Previous)-ports.InputRedundancy:]
+	}
+	for _, previous := range input.Previous {
+		previous.Previous = nil
+		g.setPlayerInput(entityID, previous, viewTick)
+	}
+	g.setPlayerInput(entityID, input, viewTick)
+}
+
+// setPlayerInput queues an input as the client sent it. Axes that are not
+// finite are rejected with the input, as they would carry NaN into the
+// positions every player is sent, and the others are clamped so that no
+// client moves or turns faster than its speed.
+func (g *Game) setPlayerInput(entityID state.EntityID, input ports.PlayerInput, viewTick uint64) {
+	axes := []*float64{&input.MoveVertical, &input.MoveHorizontal, &input.LookHorizontal}
+	for _, axis := range axes {
+		if math.IsNaN(*axis) || math.IsInf(*axis, 0) {
+			return
+		}
+		*axis = max(-1, min(1, *axis))
+	}
+
 	var mt state.MovementType
 	if input.MovementType == ports.MovementTypeRelative {
 		mt = state.MovementTypeRelative
@@ -119,14 +540,32 @@ func (g *Game) SetPlayerInput(entityID state.EntityID, input ports.PlayerInput)
 		MoveHorizontal: input.MoveHorizontal,
 		LookHorizontal: input.LookHorizontal,
 		MovementType:   mt,
+		Sprint:         input.Sprint,
 		Fire:           input.Fire,
 		SwitchWeapon:   input.SwitchWeapon,
 		Reload:         input.Reload,
 		FastReload:     input.FastReload,
-		Timestamp:      input.Timestamp,
+
+		ToggleFlashlight: input.ToggleFlashlight,
+
+		Timestamp: input.Timestamp,
+		Sequence:  input.Sequence,
+		ViewTick:  viewTick,
 	})
 }
 
+// Tick returns the number of the last update, which game updates sent
+// after it show.
+func (g *Game) Tick() uint64 {
+	return g.world.Tick()
+}
+
+// Movement returns how the player moves as of the last update, for clients
+// predicting its moves.
+func (g *Game) Movement(id state.EntityID) (state.MovementSnapshot, bool) {
+	return g.world.MovementSnapshot(id)
+}
+
 func (g *Game) Statics() []state.StaticEntity {
 	return g.world.StaticEntities()
 }
@@ -135,6 +574,113 @@ func (g *Game) PlayerSnapshotWithLocation(playerID state.EntityID) (state.Player
 	return g.world.PlayerSnapshotWithView(playerID)
 }
 
+// LightMap returns the light levels computed during the last update.
+func (g *Game) LightMap() state.LightMapSnapshot {
+	return g.world.LightMap.Snapshot()
+}
+
+// LightMapVersion changes whenever the light levels returned by LightMap change.
+func (g *Game) LightMapVersion() uint64 {
+	return g.world.LightMap.Version()
+}
+
 func (g *Game) MapInfo() state.MapInfo {
 	return g.world.MapInfo()
 }
+
+const (
+	defaultWaveCountdown      float64 = 10
+	defaultWaveEndDelay       float64 = 5
+	defaultWaveBaseEnemies    int     = 3
+	defaultWaveEnemiesPerWave int     = 2
+	defaultWaveHealthPerWave  float64 = 0.25
+
+	defaultEnemyMovementSpeed  float64 = 3.5
+	defaultEnemyRotationSpeed  float64 = 3
+	defaultEnemyHealth         int     = 40
+	defaultEnemyDamage         int     = 10
+	defaultEnemyAttackRange    float64 = 1.5
+	defaultEnemyAttackInterval float64 = 1
+	defaultEnemyBleedDamage    float64 = 2
+	defaultEnemyBleedDuration  float64 = 3
+	defaultEnemyKnockback      float64 = 10
+)
+
+func survivalWaveConfig(totalWaves int) system.WaveConfig {
+	if totalWaves <= 0 {
+		totalWaves = defaultSurvivalWaves
+	}
+
+	return system.WaveConfig{
+		TotalWaves:     totalWaves,
+		Countdown:      defaultWaveCountdown,
+		EndDelay:       defaultWaveEndDelay,
+		BaseEnemies:    defaultWaveBaseEnemies,
+		EnemiesPerWave: defaultWaveEnemiesPerWave,
+		HealthPerWave:  defaultWaveHealthPerWave,
+		PlayerHealth:   state.Health(defaultPlayerHealth),
+		Enemy: state.CreatePlayer{
+			MovementSpeed: state.MovementSpeed(defaultEnemyMovementSpeed),
+			RotationSpeed: state.RotationSpeed(defaultEnemyRotationSpeed),
+			Radius:        defaultPlayerRadius,
+			Health:        state.Health(defaultEnemyHealth),
+			Velocity:      defaultPlayerVelocity(),
+			Weapon: state.Weapon{
+				Damage:       defaultEnemyDamage,
+				Range:        defaultEnemyAttackRange,
+				FireInterval: defaultEnemyAttackInterval,
+				Knockback:    defaultEnemyKnockback,
+				// claws leave bleeding wounds
+				HitEffect: state.Effect{
+					Kind:      state.EffectBleed,
+					Remaining: defaultEnemyBleedDuration,
+					Magnitude: defaultEnemyBleedDamage,
+				},
+			},
+		},
+	}
+}
+
+// mapStairs converts the stairs of the map, rejecting stairs to floors the map does not have.
+func mapStairs(mapConfig *MapConfig) ([]state.Stair, error) {
+	stairs := make([]state.Stair, 0, len(mapConfig.Stairs))
+	for _, cfg := range mapConfig.Stairs {
+		if cfg.From == cfg.To || cfg.From > len(mapConfig.Floors) || cfg.To > len(mapConfig.Floors) {
+			return nil, fmt.Errorf("stair %s connects floor %d to floor %d of a map with %d floors", cfg.ID, cfg.From, cfg.To, len(mapConfig.Floors)+1)
+		}
+
+		kind := state.StairsKind
+		if cfg.Kind == StairKindLadder {
+			kind = state.LadderKind
+		}
+		stairs = append(stairs, state.Stair{
+			Kind: kind,
+			Bounds: state.Bounds{
+				MinX: cfg.Center.X - cfg.HalfSize.X, MinY: cfg.Center.Y - cfg.HalfSize.Y,
+				MaxX: cfg.Center.X + cfg.HalfSize.X, MaxY: cfg.Center.Y + cfg.HalfSize.Y,
+			},
+			From: state.Floor(cfg.From),
+			To:   state.Floor(cfg.To),
+		})
+	}
+	return stairs, nil
+}
+
+func enemySpawnPositions(mapConfig *MapConfig) []state.Position {
+	positions := make([]state.Position, len(mapConfig.EnemySpawnPoints))
+	for i, sp := range mapConfig.EnemySpawnPoints {
+		positions[i] = state.Position{X: sp.Position.X, Y: sp.Position.Y}
+	}
+	return positions
+}
+
+// defaultZoneStages shrink the safe zone in four stages of growing damage,
+// leaving a small circle after about four minutes.
+func defaultZoneStages() []system.ZoneStage {
+	return []system.ZoneStage{
+		{Wait: 60, Shrink: 30, Radius: 0.6, Damage: 1},
+		{Wait: 45, Shrink: 25, Radius: 0.35, Damage: 2},
+		{Wait: 30, Shrink: 20, Radius: 0.15, Damage: 5},
+		{Wait: 20, Shrink: 15, Radius: 0.05, Damage: 10},
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"survival/internal/engine"
+	"survival/internal/engine/movement"
 	"survival/internal/engine/ports"
 	"survival/internal/engine/vector"
 )
@@ -95,3 +96,217 @@ func TestWallCollision(t *testing.T) {
 		t.Errorf("Collision failed! Player walked through the wall to %.2f", snap.Player.Position.X)
 	}
 }
+
+// TestMovementPrediction verifies a client stepping the movement package with
+// the reported movement predicts the very moves of the server
+func TestMovementPrediction(t *testing.T) {
+	wall := engine.WallConfig{Center: vector.Vector2D{X: 20, Y: 14}, HalfSize: vector.Vector2D{X: 5, Y: 2}}
+	mapConfig := &engine.MapConfig{
+		Dimensions:  vector.Vector2D{X: 100, Y: 100},
+		GridSize:    10,
+		Walls:       []engine.WallConfig{wall},
+		SpawnPoints: []engine.SpawnPoint{{Position: vector.Vector2D{X: 10, Y: 10}}},
+	}
+	game, _ := engine.NewGame(mapConfig)
+	pid, _ := game.JoinPlayer()
+	game.Update(ports.DeltaTime)
+
+	snap, _ := game.PlayerSnapshotWithLocation(pid)
+	moved, _ := game.Movement(pid)
+	body := movement.Body{Position: vector.Vector2D(snap.Player.Position), Direction: float64(snap.Player.Direction)}
+	stats := movement.Stats{
+		Speed:         float64(moved.Speed),
+		RotationSpeed: float64(moved.RotationSpeed),
+		Radius:        moved.Radius,
+		Inertia: movement.Inertia{
+			Acceleration: moved.Velocity.Acceleration,
+			Friction:     moved.Velocity.Friction,
+			MaxSpeed:     moved.Velocity.MaxSpeed,
+		},
+	}
+	walls := movement.BoxWalls([]movement.Box{{Min: wall.Center.Sub(wall.HalfSize), Max: wall.Center.Add(wall.HalfSize)}})
+
+	for i := range 120 {
+		// along the wall, turning into it half way
+		input := ports.PlayerInput{MoveVertical: 1, MovementType: ports.MovementTypeRelative, Sequence: uint32(i + 1)}
+		if i < 30 {
+			input.LookHorizontal = 1
+		} else if i >= 60 && i < 75 {
+			input.LookHorizontal = 1
+		}
+		game.SetPlayerInput(pid, input)
+		game.Update(ports.DeltaTime)
+		body = movement.Step(body, movement.Input{MoveVertical: 1, LookHorizontal: input.LookHorizontal, Relative: true}, stats, walls, ports.DeltaTime)
+	}
+
+	snap, _ = game.PlayerSnapshotWithLocation(pid)
+	moved, _ = game.Movement(pid)
+	if moved.LastInput != 120 {
+		t.Errorf("Expected the last input applied reported, got %d", moved.LastInput)
+	}
+	if body.Position.DistanceTo(vector.Vector2D(snap.Player.Position)) > 1e-9 ||
+		body.Velocity.DistanceTo(moved.Velocity.Linear) > 1e-9 {
+		t.Errorf("Expected the prediction at %+v moving %+v, the server has %+v moving %+v",
+			body.Position, body.Velocity, snap.Player.Position, moved.Velocity.Linear)
+	}
+	if snap.Player.Position.Y > 11.5 {
+		t.Errorf("Expected the wall to stop the player, got %+v", snap.Player.Position)
+	}
+}
+
+// TestInputRedundancy verifies resent inputs make up for lost ones and are
+// applied once
+func TestInputRedundancy(t *testing.T) {
+	mapConfig := &engine.MapConfig{
+		Dimensions:  vector.Vector2D{X: 100, Y: 100},
+		GridSize:    10,
+		SpawnPoints: []engine.SpawnPoint{{Position: vector.Vector2D{X: 10, Y: 10}}},
+	}
+	game, _ := engine.NewGame(mapConfig)
+	pid, _ := game.JoinPlayer()
+
+	first := ports.PlayerInput{LookHorizontal: 1, Sequence: 1}
+	second := ports.PlayerInput{LookHorizontal: 1, Sequence: 2, Previous: []ports.PlayerInput{first}}
+	// the first input got lost, the second resends it and arrives twice
+	game.SetPlayerInput(pid, second)
+	game.SetPlayerInput(pid, second)
+	for range 4 {
+		game.SetPlayerInput(pid, ports.PlayerInput{})
+	}
+
+	game.Update(ports.DeltaTime)
+	if moved, _ := game.Movement(pid); moved.LastInput != 1 {
+		t.Fatalf("Expected the resent input applied first, got %d", moved.LastInput)
+	}
+	game.Update(ports.DeltaTime)
+	if moved, _ := game.Movement(pid); moved.LastInput != 2 {
+		t.Fatalf("Expected the second input on the next tick, got %d", moved.LastInput)
+	}
+	for range 4 {
+		game.Update(ports.DeltaTime)
+	}
+
+	snap, _ := game.PlayerSnapshotWithLocation(pid)
+	moved, _ := game.Movement(pid)
+	if want := 2 * float64(moved.RotationSpeed) * ports.DeltaTime; math.Abs(float64(snap.Player.Direction)-want) > 1e-9 {
+		t.Errorf("Expected two ticks of turning, got direction %.4f want %.4f", snap.Player.Direction, want)
+	}
+}
+
+func TestInputRedundancy_TakesOnlyTheLastPreviousInputs(t *testing.T) {
+	mapConfig := &engine.MapConfig{
+		Dimensions:  vector.Vector2D{X: 100, Y: 100},
+		GridSize:    10,
+		SpawnPoints: []engine.SpawnPoint{{Position: vector.Vector2D{X: 10, Y: 10}}},
+	}
+	game, _ := engine.NewGame(mapConfig)
+	pid, _ := game.JoinPlayer()
+
+	// a client resending far more inputs than it should
+	input := ports.PlayerInput{Sequence: 100}
+	for sequence := range uint32(99) {
+		input.Previous = append(input.Previous, ports.PlayerInput{Sequence: sequence + 1})
+	}
+	game.SetPlayerInput(pid, input)
+
+	game.Update(ports.DeltaTime)
+	moved, _ := game.Movement(pid)
+	if want := uint32(100 - ports.InputRedundancy); moved.LastInput != want {
+		t.Fatalf("Expected only the last %d previous inputs queued, first applied %d want %d", ports.InputRedundancy, moved.LastInput, want)
+	}
+	for range ports.InputRedundancy {
+		game.Update(ports.DeltaTime)
+	}
+	if moved, _ := game.Movement(pid); moved.LastInput != 100 {
+		t.Errorf("Expected the input itself after the previous ones, got %d", moved.LastInput)
+	}
+}
+
+func TestInputValidation(t *testing.T) {
+	mapConfig := &engine.MapConfig{
+		Dimensions:  vector.Vector2D{X: 100, Y: 100},
+		GridSize:    10,
+		SpawnPoints: []engine.SpawnPoint{{Position: vector.Vector2D{X: 50, Y: 50}}},
+	}
+
+	t.Run("non-finite axes are rejected", func(t *testing.T) {
+		game, _ := engine.NewGame(mapConfig)
+		pid, _ := game.JoinPlayer()
+
+		game.SetPlayerInput(pid, ports.PlayerInput{MoveVertical: math.NaN(), Sequence: 1})
+		game.SetPlayerInput(pid, ports.PlayerInput{MoveHorizontal: math.Inf(1), Sequence: 2})
+		game.SetPlayerInput(pid, ports.PlayerInput{LookHorizontal: math.Inf(-1), Sequence: 3})
+		for range 3 {
+			game.Update(ports.DeltaTime)
+		}
+
+		snap, _ := game.PlayerSnapshotWithLocation(pid)
+		if snap.Player.Position.X != 50 || snap.Player.Position.Y != 50 || snap.Player.Direction != 0 {
+			t.Errorf("Expected the player unmoved, got %+v facing %v", snap.Player.Position, snap.Player.Direction)
+		}
+		if moved, _ := game.Movement(pid); moved.LastInput != 0 {
+			t.Errorf("Expected no input applied, got %d", moved.LastInput)
+		}
+	})
+
+	t.Run("axes are clamped to one", func(t *testing.T) {
+		clamped, _ := engine.NewGame(mapConfig)
+		clampedID, _ := clamped.JoinPlayer()
+		full, _ := engine.NewGame(mapConfig)
+		fullID, _ := full.JoinPlayer()
+
+		for range 30 {
+			clamped.SetPlayerInput(clampedID, ports.PlayerInput{MoveVertical: 100, MoveHorizontal: -1e9, LookHorizontal: 50})
+			clamped.Update(ports.DeltaTime)
+			full.SetPlayerInput(fullID, ports.PlayerInput{MoveVertical: 1, MoveHorizontal: -1, LookHorizontal: 1})
+			full.Update(ports.DeltaTime)
+		}
+
+		got, _ := clamped.PlayerSnapshotWithLocation(clampedID)
+		want, _ := full.PlayerSnapshotWithLocation(fullID)
+		if got.Player.Position != want.Player.Position || got.Player.Direction != want.Player.Direction {
+			t.Errorf("Expected the moves of full input %+v facing %v, got %+v facing %v",
+				want.Player.Position, want.Player.Direction, got.Player.Position, got.Player.Direction)
+		}
+	})
+}
+
+func TestSurvivalMode(t *testing.T) {
+	mapConfig := &engine.MapConfig{
+		Dimensions: vector.Vector2D{X: 100, Y: 100},
+		GridSize:   10,
+		SpawnPoints: []engine.SpawnPoint{
+			{Position: vector.Vector2D{X: 10, Y: 10}},
+		},
+		EnemySpawnPoints: []engine.SpawnPoint{
+			{Position: vector.Vector2D{X: 90, Y: 90}},
+		},
+	}
+
+	if _, err := engine.NewGameWithConfig(mapConfig, engine.GameConfig{Mode: "capture"}); err == nil {
+		t.Fatal("Expected an unknown mode to be rejected")
+	}
+
+	game, err := engine.NewGameWithConfig(mapConfig, engine.GameConfig{Mode: engine.GameModeSurvival, SurvivalWaves: 3})
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	if _, err := game.JoinPlayer(); err != nil {
+		t.Fatalf("Failed to join player: %v", err)
+	}
+
+	game.Update(1.0 / 60.0)
+
+	survival, ok := game.Survival()
+	if !ok {
+		t.Fatal("Survival game should report its survival state")
+	}
+	if survival.Wave != 1 || survival.TotalWaves != 3 {
+		t.Errorf("Expected countdown to wave 1 of 3, got %+v", survival)
+	}
+
+	deathmatch, _ := engine.NewGame(mapConfig)
+	if _, ok := deathmatch.Survival(); ok {
+		t.Error("Deathmatch game should not report a survival state")
+	}
+}
@@ -0,0 +1,200 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/weapons"
+)
+
+const (
+	defaultInventorySlots = 6
+	// defaultPickupRange is how close a player has to stand to pick up an item.
+	defaultPickupRange float64 = 1.5
+
+	defaultMagazineCapacity = 12
+	defaultSpareMagazines   = 2
+
+	defaultKnifeDamage       int     = 35
+	defaultKnifeRange        float64 = 1.5
+	defaultKnifeFireInterval float64 = 0.4
+	defaultKnifeKnockback    float64 = 12
+
+	unarmedDamage       int     = 5
+	unarmedRange        float64 = 1
+	unarmedFireInterval float64 = 0.5
+	// bare hands shove more than they hurt
+	unarmedKnockback float64 = 14
+)
+
+// startingInventory is what every player spawns with: a knife, a loaded
+// pistol in hand and spare magazines. Item IDs are unique within the game so
+// items keep their identity when they change hands.
+func (g *Game) startingInventory() state.Inventory {
+	inventory := state.Inventory{MaxSlots: defaultInventorySlots}
+	inventory.MeleeWeapons = append(inventory.MeleeWeapons, weapons.Knife{
+		ID:    g.itemIDs.GenerateID(),
+		Range: defaultKnifeRange,
+	})
+	inventory.RangedWeapons = append(inventory.RangedWeapons, weapons.Pistol{
+		ID:              g.itemIDs.GenerateID(),
+		CurrentMagazine: g.newMagazine(),
+		Range:           defaultWeaponRange,
+	})
+	for range defaultSpareMagazines {
+		inventory.Magazines = append(inventory.Magazines, *g.newMagazine())
+	}
+	inventory.Equipped = inventory.RangedWeapons[0].ID
+	return inventory
+}
+
+func (g *Game) newMagazine() *weapons.Magazine {
+	return &weapons.Magazine{
+		ID:          g.itemIDs.GenerateID(),
+		CurrentAmmo: defaultMagazineCapacity,
+		MaxCapacity: defaultMagazineCapacity,
+	}
+}
+
+// weaponStats returns the weapon with the stats of the item equipped in the
+// inventory, bare hands when nothing is. The cooldown and hit effect are kept.
+// Melee attacks shove the victim and have no recoil.
+func weaponStats(weapon state.Weapon, inventory state.Inventory) state.Weapon {
+	item, equipped := inventory.EquippedItem()
+	switch {
+	case !equipped:
+		weapon.Damage, weapon.Range, weapon.FireInterval = unarmedDamage, unarmedRange, unarmedFireInterval
+		weapon.Knockback, weapon.Recoil = unarmedKnockback, 0
+	case item.Kind == weapons.ItemMelee:
+		weapon.Damage, weapon.Range, weapon.FireInterval = defaultKnifeDamage, item.Knife.Range, defaultKnifeFireInterval
+		weapon.Knockback, weapon.Recoil = defaultKnifeKnockback, 0
+	case item.Kind == weapons.ItemRanged:
+		weapon.Damage, weapon.Range, weapon.FireInterval = defaultWeaponDamage, item.Pistol.Range, defaultWeaponFireInterval
+		weapon.Knockback, weapon.Recoil = defaultWeaponKnockback, defaultWeaponRecoil
+	}
+	return weapon
+}
+
+// Inventory returns what the player carries.
+func (g *Game) Inventory(id state.EntityID) (state.Inventory, bool) {
+	return g.world.Inventory.Get(id)
+}
+
+// NearbyPickups returns the items lying close enough for the player to pick up.
+func (g *Game) NearbyPickups(id state.EntityID) []state.GroundItem {
+	pos, exist := g.world.Position.Get(id)
+	if !exist {
+		return nil
+	}
+	return g.world.PickupsNear(pos, g.world.FloorOf(id), defaultPickupRange)
+}
+
+// EquipItem puts the weapon in the player's hand.
+func (g *Game) EquipItem(id state.EntityID, itemID string) error {
+	inventory, err := g.editInventory(id)
+	if err != nil {
+		return err
+	}
+	if err := inventory.Equip(itemID); err != nil {
+		return fmt.Errorf("failed to equip %s: %w", itemID, err)
+	}
+	g.setInventory(id, inventory)
+	return nil
+}
+
+// DropItem puts the item on the ground at the player's position. Dropping
+// the weapon in hand switches to the first weapon left.
+func (g *Game) DropItem(id state.EntityID, itemID string) error {
+	inventory, err := g.editInventory(id)
+	if err != nil {
+		return err
+	}
+	item, removed := inventory.Remove(itemID)
+	if !removed {
+		return fmt.Errorf("failed to drop %s: %w", itemID, weapons.ErrItemNotFound)
+	}
+	if inventory.Equipped == "" {
+		for _, left := range inventory.Items() {
+			if left.Kind != weapons.ItemMagazine {
+				inventory.Equipped = left.ID()
+				break
+			}
+		}
+	}
+
+	pos, _ := g.world.Position.Get(id)
+	if _, ok := g.world.CreatePickup(pos, g.world.FloorOf(id), item); !ok {
+		return fmt.Errorf("failed to allocate entity for dropped item %s", itemID)
+	}
+	g.setInventory(id, inventory)
+	return nil
+}
+
+// PickUpItem moves an item lying within reach into the player's inventory.
+// An empty handed player equips a picked up weapon right away.
+func (g *Game) PickUpItem(id state.EntityID, pickupID state.EntityID) error {
+	inventory, err := g.editInventory(id)
+	if err != nil {
+		return err
+	}
+
+	meta, exist := g.world.EntityMeta.Get(pickupID)
+	if !exist || !meta.Has(state.PickupMeta) || !g.world.SameFloor(id, pickupID) {
+		return fmt.Errorf("no item %d on the ground", pickupID)
+	}
+	pos, _ := g.world.Position.Get(id)
+	itemPos, _ := g.world.Position.Get(pickupID)
+	if math.Hypot(itemPos.X-pos.X, itemPos.Y-pos.Y) > defaultPickupRange {
+		return fmt.Errorf("item %d is out of reach", pickupID)
+	}
+
+	pickup, _ := g.world.Pickup.Get(pickupID)
+	if err := inventory.Add(pickup.Item); err != nil {
+		return fmt.Errorf("failed to pick up %s: %w", pickup.Item.ID(), err)
+	}
+	if inventory.Equipped == "" && pickup.Item.Kind != weapons.ItemMagazine {
+		inventory.Equipped = pickup.Item.ID()
+	}
+
+	g.world.QueueDestroy(pickupID)
+	g.setInventory(id, inventory)
+	return nil
+}
+
+// MoveItem reorders the inventory, slot counts within the item's category.
+func (g *Game) MoveItem(id state.EntityID, itemID string, slot int) error {
+	inventory, err := g.editInventory(id)
+	if err != nil {
+		return err
+	}
+	if err := inventory.Move(itemID, slot); err != nil {
+		return fmt.Errorf("failed to move %s to slot %d: %w", itemID, slot, err)
+	}
+	g.setInventory(id, inventory)
+	return nil
+}
+
+// editInventory returns a copy of the inventory of a living player, safe to change.
+func (g *Game) editInventory(id state.EntityID) (state.Inventory, error) {
+	meta, exist := g.world.EntityMeta.Get(id)
+	if !exist || !meta.Has(state.ComponentInventory) {
+		return state.Inventory{}, fmt.Errorf("entity %d has no inventory", id)
+	}
+	if g.world.IsDead(id) {
+		return state.Inventory{}, fmt.Errorf("entity %d is dead", id)
+	}
+	inventory, _ := g.world.Inventory.Get(id)
+	return inventory.Clone(), nil
+}
+
+// setInventory stores the inventory and arms the player with the equipped weapon.
+func (g *Game) setInventory(id state.EntityID, inventory state.Inventory) {
+	weapon, _ := g.world.Weapon.Get(id)
+	g.world.UpdatePlayer(id, state.UpdatePlayer{
+		UpdateMeta: state.ComponentInventory | state.ComponentWeapon,
+		Inventory:  inventory,
+		Weapon:     weaponStats(weapon, inventory),
+	})
+	g.world.ApplyCommands()
+}
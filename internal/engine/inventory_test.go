@@ -0,0 +1,154 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"survival/internal/engine"
+	"survival/internal/engine/ports"
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+	"survival/internal/engine/weapons"
+)
+
+func newInventoryGame(t *testing.T) *engine.Game {
+	t.Helper()
+	game, err := engine.NewGame(&engine.MapConfig{
+		Dimensions: vector.Vector2D{X: 100, Y: 100},
+		GridSize:   10,
+		SpawnPoints: []engine.SpawnPoint{
+			{Position: vector.Vector2D{X: 10, Y: 10}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	return game
+}
+
+func joinPlayer(t *testing.T, game *engine.Game) state.EntityID {
+	t.Helper()
+	id, err := game.JoinPlayer()
+	if err != nil {
+		t.Fatalf("Failed to join player: %v", err)
+	}
+	return id
+}
+
+func TestInventory_StartingLoadoutAndEquip(t *testing.T) {
+	game := newInventoryGame(t)
+	id := joinPlayer(t, game)
+
+	inventory, ok := game.Inventory(id)
+	if !ok {
+		t.Fatal("Expected the player to carry an inventory")
+	}
+	if len(inventory.MeleeWeapons) != 1 || len(inventory.RangedWeapons) != 1 || len(inventory.Magazines) != 2 {
+		t.Fatalf("Expected a knife, a pistol and two magazines, got %+v", inventory)
+	}
+	if inventory.Equipped != inventory.RangedWeapons[0].ID {
+		t.Errorf("Expected the pistol in hand, got %q", inventory.Equipped)
+	}
+
+	if err := game.EquipItem(id, inventory.Magazines[0].ID); !errors.Is(err, weapons.ErrNotEquippable) {
+		t.Errorf("Expected a magazine not to be equippable, got %v", err)
+	}
+	if err := game.EquipItem(id, "item-999"); !errors.Is(err, weapons.ErrItemNotFound) {
+		t.Errorf("Expected an unknown item to be rejected, got %v", err)
+	}
+	if err := game.EquipItem(id, inventory.MeleeWeapons[0].ID); err != nil {
+		t.Fatalf("Failed to equip the knife: %v", err)
+	}
+	if equipped, _ := game.Inventory(id); equipped.Equipped != inventory.MeleeWeapons[0].ID {
+		t.Errorf("Expected the knife in hand, got %q", equipped.Equipped)
+	}
+}
+
+func TestInventory_DropAndPickUp(t *testing.T) {
+	game := newInventoryGame(t)
+	alice := joinPlayer(t, game)
+	bob := joinPlayer(t, game)
+
+	aliceInventory, _ := game.Inventory(alice)
+	pistol := aliceInventory.RangedWeapons[0]
+	if err := game.DropItem(alice, pistol.ID); err != nil {
+		t.Fatalf("Failed to drop the pistol: %v", err)
+	}
+	if inventory, _ := game.Inventory(alice); inventory.Equipped != aliceInventory.MeleeWeapons[0].ID || inventory.Count() != 3 {
+		t.Errorf("Expected the knife in hand after dropping the pistol, got %+v", inventory)
+	}
+
+	pickups := game.NearbyPickups(bob)
+	if len(pickups) != 1 || pickups[0].Item.ID() != pistol.ID || pickups[0].Item.Pistol.GetAmmoCount() != pistol.GetAmmoCount() {
+		t.Fatalf("Expected the loaded pistol on the ground, got %+v", pickups)
+	}
+	if err := game.PickUpItem(bob, pickups[0].ID); err != nil {
+		t.Fatalf("Failed to pick up the pistol: %v", err)
+	}
+	if err := game.PickUpItem(alice, pickups[0].ID); err == nil {
+		t.Error("Expected an item to be picked up only once")
+	}
+	if len(game.NearbyPickups(bob)) != 0 {
+		t.Error("Expected the pistol gone from the ground")
+	}
+	if inventory, _ := game.Inventory(bob); inventory.Count() != 5 {
+		t.Errorf("Expected bob to carry 5 items, got %d", inventory.Count())
+	}
+
+	// bob fills his last slot with one of alice's magazines, the next one does not fit
+	for i, magazine := range aliceInventory.Magazines {
+		if err := game.DropItem(alice, magazine.ID); err != nil {
+			t.Fatalf("Failed to drop magazine %d: %v", i, err)
+		}
+		err := game.PickUpItem(bob, game.NearbyPickups(bob)[0].ID)
+		if i == 0 && err != nil {
+			t.Fatalf("Failed to pick up the first magazine: %v", err)
+		}
+		if i == 1 && !errors.Is(err, weapons.ErrInventoryFull) {
+			t.Errorf("Expected a full inventory, got %v", err)
+		}
+	}
+}
+
+func TestInventory_PickUpOutOfReach(t *testing.T) {
+	game := newInventoryGame(t)
+	alice := joinPlayer(t, game)
+	bob := joinPlayer(t, game)
+
+	inventory, _ := game.Inventory(alice)
+	if err := game.DropItem(alice, inventory.MeleeWeapons[0].ID); err != nil {
+		t.Fatalf("Failed to drop the knife: %v", err)
+	}
+	pickupID := game.NearbyPickups(alice)[0].ID
+
+	for range 60 {
+		game.SetPlayerInput(bob, ports.PlayerInput{MoveHorizontal: 1})
+		game.Update(1.0 / 60.0)
+	}
+	if len(game.NearbyPickups(bob)) != 0 {
+		t.Fatal("Expected the knife out of sight once bob walked away")
+	}
+	if err := game.PickUpItem(bob, pickupID); err == nil {
+		t.Error("Expected an item out of reach not to be picked up")
+	}
+}
+
+func TestInventory_Move(t *testing.T) {
+	game := newInventoryGame(t)
+	id := joinPlayer(t, game)
+
+	inventory, _ := game.Inventory(id)
+	last := inventory.Magazines[1].ID
+	if err := game.MoveItem(id, last, 0); err != nil {
+		t.Fatalf("Failed to move the magazine: %v", err)
+	}
+	if moved, _ := game.Inventory(id); moved.Magazines[0].ID != last {
+		t.Errorf("Expected %s first, got %+v", last, moved.Magazines)
+	}
+	if err := game.MoveItem(id, last, 2); !errors.Is(err, weapons.ErrInvalidSlot) {
+		t.Errorf("Expected a slot past the magazines to be rejected, got %v", err)
+	}
+	if original := inventory.Magazines[0].ID; original == last {
+		t.Error("Expected the inventory returned earlier to stay untouched")
+	}
+}
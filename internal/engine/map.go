@@ -17,11 +17,42 @@ type MapConfig struct {
 	SpawnPoints []SpawnPoint    `json:"spawn_points" validate:"required,min=1,dive"`
 	Walls       []WallConfig    `json:"walls" validate:"dive"`
 	Objects     []ObjectConfig  `json:"objects,omitempty" validate:"dive"`
+	// EnemySpawnPoints are where survival mode enemies appear, player spawn points are used when empty.
+	EnemySpawnPoints []SpawnPoint `json:"enemy_spawn_points,omitempty" validate:"dive"`
+	// Darkness lowers the ambient light of the whole map, 0 is fully lit and 1 is pitch dark.
+	Darkness float64 `json:"darkness,omitempty" validate:"gte=0,lte=1"`
+	// Floors are the storeys above the ground floor, whose walls are Walls.
+	// Floor 1 is Floors[0] and so on. Spawn points are on the ground floor.
+	Floors []FloorConfig `json:"floors,omitempty" validate:"dive"`
+	// Stairs connect the floors, walking into one moves a player to the other floor it connects.
+	Stairs []StairConfig `json:"stairs,omitempty" validate:"dive"`
+}
+
+type FloorConfig struct {
+	ID    string       `json:"id" validate:"required,min=1"`
+	Walls []WallConfig `json:"walls" validate:"dive"`
+}
+
+const (
+	StairKindStairs = "stairs"
+	StairKindLadder = "ladder"
+)
+
+type StairConfig struct {
+	ID       string          `json:"id" validate:"required,min=1"`
+	Kind     string          `json:"kind,omitempty" validate:"omitempty,oneof=stairs ladder"`
+	Center   vector.Vector2D `json:"center" validate:"required"`
+	HalfSize vector.Vector2D `json:"half_size" validate:"required"`
+	// From and To are the floors connected, 0 is the ground floor.
+	From int `json:"from" validate:"gte=0"`
+	To   int `json:"to" validate:"gte=0"`
 }
 
 type SpawnPoint struct {
 	ID       string          `json:"id" validate:"required,min=1"`
 	Position vector.Vector2D `json:"position" validate:"required"`
+	// Team reserves the spawn point for one team in team modes, 0 is open to everyone.
+	Team int `json:"team,omitempty" validate:"gte=0"`
 }
 
 type WallConfig struct {
@@ -31,14 +62,28 @@ type WallConfig struct {
 	Rotation      float64         `json:"rotation"`
 	Height        float64         `json:"height"`
 	BaseElevation float64         `json:"base_elevation"`
+	// Destructible walls break once Health damage was dealt to them, 0 means DefaultWallHealth.
+	Destructible bool `json:"destructible,omitempty"`
+	Health       int  `json:"health,omitempty" validate:"gte=0"`
 }
 
+// DefaultWallHealth is the health of destructible walls that do not set their own.
+const DefaultWallHealth = 200
+
+const (
+	ObjectTypeLight = "light"
+)
+
 type ObjectConfig struct {
 	ID       string          `json:"id" validate:"required,min=1"`
 	Type     string          `json:"type" validate:"required,min=1"`
 	Center   vector.Vector2D `json:"center" validate:"required"`
 	HalfSize vector.Vector2D `json:"half_size" validate:"required"`
 	Rotation float64         `json:"rotation"`
+
+	// Light settings, only used by ObjectTypeLight objects.
+	LightRadius    float64 `json:"light_radius,omitempty"`
+	LightIntensity float64 `json:"light_intensity,omitempty"`
 }
 
 func (mc *MapConfig) GetRandomSpawnPoint() *SpawnPoint {
@@ -0,0 +1,68 @@
+package movement
+
+import (
+	"math"
+
+	"survival/internal/engine/vector"
+)
+
+// ResolveWalls pushes a circular hitbox out of the walls it overlaps, one
+// after the other. Without walls the position is returned as is.
+func ResolveWalls(pos vector.Vector2D, radius float64, walls Walls) vector.Vector2D {
+	if walls == nil {
+		return pos
+	}
+
+	result := pos
+	for wall := range walls(pos, radius) {
+		collides, pushOut := CircleAABBCollision(result, radius, wall.Min, wall.Max)
+		if collides {
+			result = result.Add(pushOut)
+		}
+	}
+	return result
+}
+
+// CircleAABBCollision detects collision between a circle and an AABB.
+// Returns whether collision occurred and the push-out vector to resolve it.
+func CircleAABBCollision(center vector.Vector2D, radius float64, wallMin, wallMax vector.Vector2D) (collides bool, pushOut vector.Vector2D) {
+	closest := vector.Vector2D{
+		X: math.Max(wallMin.X, math.Min(center.X, wallMax.X)),
+		Y: math.Max(wallMin.Y, math.Min(center.Y, wallMax.Y)),
+	}
+
+	diff := center.Sub(closest)
+	dist := diff.Magnitude()
+
+	if dist >= radius {
+		return false, vector.Vector2D{}
+	}
+
+	if dist > 0 {
+		penetration := radius - dist
+		return true, diff.Normalize().Scale(penetration)
+	}
+
+	// Circle center inside AABB - find nearest edge and push out
+	distToLeft := center.X - wallMin.X
+	distToRight := wallMax.X - center.X
+	distToTop := center.Y - wallMin.Y
+	distToBottom := wallMax.Y - center.Y
+
+	minDist := distToLeft
+	pushOut = vector.Vector2D{X: -(distToLeft + radius), Y: 0}
+
+	if distToRight < minDist {
+		minDist = distToRight
+		pushOut = vector.Vector2D{X: distToRight + radius, Y: 0}
+	}
+	if distToTop < minDist {
+		minDist = distToTop
+		pushOut = vector.Vector2D{X: 0, Y: -(distToTop + radius)}
+	}
+	if distToBottom < minDist {
+		pushOut = vector.Vector2D{X: 0, Y: distToBottom + radius}
+	}
+
+	return true, pushOut
+}
@@ -0,0 +1,175 @@
+// Package movement moves players by their input and pushes them out of walls.
+// The server systems and the client-side prediction of the terminal share it,
+// so the client predicts the very moves the server makes.
+package movement
+
+import (
+	"iter"
+	"math"
+
+	"survival/internal/engine/vector"
+)
+
+// SprintSpeedMultiplier is the movement speed factor while sprinting.
+const SprintSpeedMultiplier = 1.6
+
+// Input is the movement part of a player input. Relative movement is along
+// the direction the player faces, absolute movement along the map axes.
+type Input struct {
+	MoveHorizontal float64
+	MoveVertical   float64
+	LookHorizontal float64
+	Relative       bool
+}
+
+// Moving reports whether the input asks to move.
+func (in Input) Moving() bool {
+	return in.MoveHorizontal != 0 || in.MoveVertical != 0
+}
+
+// Body is the moving state of a player.
+type Body struct {
+	Position  vector.Vector2D
+	Velocity  vector.Vector2D
+	Direction float64
+}
+
+// Inertia makes a player accelerate to its speed and slow down to a stop
+// instead of moving at its speed right away, see state.Velocity.
+type Inertia struct {
+	Acceleration float64
+	Friction     float64
+	MaxSpeed     float64
+}
+
+// Stats are how fast a player moves. Speed already includes effects and
+// sprinting. Players with a zero Inertia move at Speed right away.
+type Stats struct {
+	Speed         float64
+	RotationSpeed float64
+	Radius        float64
+	Inertia       Inertia
+}
+
+// Box is the axis aligned bounding box of a wall.
+type Box struct {
+	Min vector.Vector2D
+	Max vector.Vector2D
+}
+
+// Walls yields the walls a circle at center with the radius may touch.
+type Walls func(center vector.Vector2D, radius float64) iter.Seq[Box]
+
+// BoxWalls returns Walls yielding all the boxes, for a few static colliders.
+func BoxWalls(boxes []Box) Walls {
+	return func(vector.Vector2D, float64) iter.Seq[Box] {
+		return func(yield func(Box) bool) {
+			for _, box := range boxes {
+				if !yield(box) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Step moves and turns the body by the input for one tick, the way the server
+// moves the players: along the direction before turning.
+func Step(body Body, in Input, stats Stats, walls Walls, dt float64) Body {
+	body = Move(body, in, stats, walls, dt)
+	body.Direction = Turn(body.Direction, stats.RotationSpeed, in, dt)
+	return body
+}
+
+// Move moves the body by the input and pushes it out of the walls. With
+// inertia the velocity is steered towards the input, integrated and loses the
+// part pushing into a wall, so the body slides along.
+func Move(body Body, in Input, stats Stats, walls Walls, dt float64) Body {
+	target := InputDirection(body.Direction, in).Scale(stats.Speed)
+	if stats.Inertia == (Inertia{}) {
+		body.Position = ResolveWalls(body.Position.Add(target.Scale(dt)), stats.Radius, walls)
+		return body
+	}
+
+	body.Velocity = Steer(body.Velocity, target, in.Moving(), stats.Inertia, dt)
+	moved := body.Position.Add(body.Velocity.Scale(dt))
+	body.Position = ResolveWalls(moved, stats.Radius, walls)
+	body.Velocity = Slide(body.Velocity, body.Position.Sub(moved))
+	return body
+}
+
+// Turn computes the new direction from the rotation input.
+// LookHorizontal: Positive = clockwise (right), Negative = counter-clockwise (left).
+func Turn(dir, rotationSpeed float64, in Input, dt float64) float64 {
+	return dir + in.LookHorizontal*rotationSpeed*dt
+}
+
+// InputDirection returns the unit vector input moves a player facing dir
+// along, zero without movement input.
+// Uses screen coordinates: Y increases downward.
+// MoveVertical: Positive = down, Negative = up (forward when relative)
+// MoveHorizontal: Positive = right, Negative = left
+func InputDirection(dir float64, in Input) vector.Vector2D {
+	var moveX, moveY float64
+
+	if in.Relative {
+		forward := in.MoveVertical
+		strafe := in.MoveHorizontal
+
+		cosDir := math.Cos(dir)
+		sinDir := math.Sin(dir)
+
+		fwdX := sinDir
+		fwdY := -cosDir
+		rightX := cosDir
+		rightY := sinDir
+
+		moveX = (forward * fwdX) + (strafe * rightX)
+		moveY = (forward * fwdY) + (strafe * rightY)
+	} else {
+		moveX = in.MoveHorizontal
+		moveY = in.MoveVertical
+	}
+
+	movement := vector.Vector2D{X: moveX, Y: moveY}
+	if movement.X != 0 || movement.Y != 0 {
+		movement = movement.Normalize()
+	}
+	return movement
+}
+
+// Steer accelerates the velocity towards target while moving and lets
+// friction slow it down otherwise, capped at MaxSpeed.
+func Steer(velocity, target vector.Vector2D, moving bool, inertia Inertia, dt float64) vector.Vector2D {
+	if moving {
+		velocity = MoveTowards(velocity, target, inertia.Acceleration*dt)
+	} else {
+		velocity = MoveTowards(velocity, vector.Vector2D{}, inertia.Friction*dt)
+	}
+
+	if inertia.MaxSpeed > 0 && velocity.Magnitude() > inertia.MaxSpeed {
+		velocity = velocity.Normalize().Scale(inertia.MaxSpeed)
+	}
+	return velocity
+}
+
+// MoveTowards changes a vector towards target by at most maxDelta.
+func MoveTowards(from, target vector.Vector2D, maxDelta float64) vector.Vector2D {
+	diff := target.Sub(from)
+	if diff.Magnitude() <= maxDelta {
+		return target
+	}
+	return from.Add(diff.Normalize().Scale(maxDelta))
+}
+
+// Slide removes the part of the velocity going against the push out of a wall.
+func Slide(velocity, pushOut vector.Vector2D) vector.Vector2D {
+	if pushOut.Magnitude() == 0 {
+		return velocity
+	}
+	normal := pushOut.Normalize()
+	if into := velocity.Dot(normal); into < 0 {
+		velocity = velocity.Sub(normal.Scale(into))
+	}
+	return velocity
+}
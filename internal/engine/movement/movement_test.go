@@ -0,0 +1,74 @@
+package movement
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/vector"
+)
+
+const dt = 1.0 / 60.0
+
+var testInertia = Inertia{Acceleration: 60, Friction: 30, MaxSpeed: 20}
+
+func TestStep_MovesAlongDirectionBeforeTurning(t *testing.T) {
+	body := Body{Direction: math.Pi / 2}
+	stats := Stats{Speed: 6, RotationSpeed: 3, Radius: 0.5}
+
+	body = Step(body, Input{MoveVertical: 1, LookHorizontal: 1, Relative: true}, stats, nil, dt)
+
+	if math.Abs(body.Position.X-0.1) > 1e-9 || math.Abs(body.Position.Y) > 1e-9 {
+		t.Errorf("Expected a move facing +X, got %+v", body.Position)
+	}
+	if math.Abs(body.Direction-(math.Pi/2+0.05)) > 1e-9 {
+		t.Errorf("Expected the turn after the move, got %.3f", body.Direction)
+	}
+}
+
+func TestStep_InertiaAcceleratesAndSlidesAlongWalls(t *testing.T) {
+	// a wall right of the body, which moves diagonally into it
+	walls := BoxWalls([]Box{{Min: vector.Vector2D{X: 1, Y: -10}, Max: vector.Vector2D{X: 2, Y: 10}}})
+	stats := Stats{Speed: 5, Radius: 0.5, Inertia: testInertia}
+	body := Body{Position: vector.Vector2D{X: 0.4}}
+
+	body = Step(body, Input{MoveHorizontal: 1, MoveVertical: 1}, stats, walls, dt)
+	if math.Abs(body.Velocity.Magnitude()-1) > 1e-9 {
+		t.Fatalf("Expected one tick of acceleration, got %+v", body.Velocity)
+	}
+
+	for range 60 {
+		body = Step(body, Input{MoveHorizontal: 1, MoveVertical: 1}, stats, walls, dt)
+	}
+	if body.Position.X > 0.5+1e-9 {
+		t.Errorf("Expected the wall to stop the body, got %+v", body.Position)
+	}
+	if body.Velocity.X > 1e-9 || body.Velocity.Y <= 0 {
+		t.Errorf("Expected the body to slide along the wall, got velocity %+v", body.Velocity)
+	}
+	if body.Position.Y < 2 {
+		t.Errorf("Expected the body to keep moving down, got %+v", body.Position)
+	}
+}
+
+func TestCircleAABBCollision(t *testing.T) {
+	wallMin, wallMax := vector.Vector2D{X: 0, Y: 0}, vector.Vector2D{X: 2, Y: 2}
+
+	tests := []struct {
+		name    string
+		center  vector.Vector2D
+		collide bool
+		pushOut vector.Vector2D
+	}{
+		{name: "apart", center: vector.Vector2D{X: 3, Y: 1}},
+		{name: "overlapping an edge", center: vector.Vector2D{X: 2.25, Y: 1}, collide: true, pushOut: vector.Vector2D{X: 0.25}},
+		{name: "center inside", center: vector.Vector2D{X: 1, Y: 1.75}, collide: true, pushOut: vector.Vector2D{Y: 0.75}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collide, pushOut := CircleAABBCollision(tt.center, 0.5, wallMin, wallMax)
+			if collide != tt.collide || pushOut.DistanceTo(tt.pushOut) > 1e-9 {
+				t.Errorf("CircleAABBCollision() = %v %+v, want %v %+v", collide, pushOut, tt.collide, tt.pushOut)
+			}
+		})
+	}
+}
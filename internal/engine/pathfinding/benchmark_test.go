@@ -0,0 +1,88 @@
+package pathfinding_test
+
+import (
+	"testing"
+
+	"survival/internal/adapters/repository/maploader"
+	"survival/internal/engine"
+	"survival/internal/engine/state"
+)
+
+const benchRadius = 0.5
+
+// office positions on opposite sides of the pillar rows
+var (
+	officeWest = state.Position{X: 545, Y: 775}
+	officeEast = state.Position{X: 1195, Y: 585}
+)
+
+func loadOffice(b *testing.B) *engine.Game {
+	b.Helper()
+
+	mapConfig, err := maploader.NewJSONMapLoader("../../../maps").LoadMap("office_floor_01")
+	if err != nil {
+		b.Fatalf("Failed to load map: %v", err)
+	}
+	game, err := engine.NewGame(mapConfig)
+	if err != nil {
+		b.Fatalf("Failed to create game: %v", err)
+	}
+	return game
+}
+
+func BenchmarkOffice_BuildNavGrid(b *testing.B) {
+	game := loadOffice(b)
+	grid := game.Pathfinder().Grid(benchRadius)
+
+	b.ResetTimer()
+	for range b.N {
+		grid.Rebuild(state.Bounds{MaxX: 1200, MaxY: 800})
+	}
+}
+
+func BenchmarkOffice_FindPath(b *testing.B) {
+	pathfinder := loadOffice(b).Pathfinder()
+	if _, ok := pathfinder.FindPath(officeWest, officeEast, benchRadius); !ok {
+		b.Fatalf("Expected a path across the office")
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		pathfinder.FindPath(officeWest, officeEast, benchRadius)
+	}
+}
+
+func BenchmarkOffice_FlowField(b *testing.B) {
+	grid := loadOffice(b).Pathfinder().Grid(benchRadius)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, ok := grid.FlowField(officeEast); !ok {
+			b.Fatalf("Expected a flow field")
+		}
+	}
+}
+
+func BenchmarkOffice_FlowFieldNext(b *testing.B) {
+	field, ok := loadOffice(b).Pathfinder().FlowField(officeEast, benchRadius)
+	if !ok {
+		b.Fatalf("Expected a flow field")
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		field.Next(officeWest)
+	}
+}
+
+func BenchmarkOffice_InvalidateWall(b *testing.B) {
+	pathfinder := loadOffice(b).Pathfinder()
+	pathfinder.Grid(benchRadius)
+	// the pillar wall_10_2 standing in for a door changing state
+	wall := state.Bounds{MinX: 840, MinY: 680, MaxX: 860, MaxY: 720}
+
+	b.ResetTimer()
+	for range b.N {
+		pathfinder.Invalidate(wall)
+	}
+}
@@ -0,0 +1,194 @@
+package pathfinding
+
+import (
+	"math"
+
+	"survival/internal/engine/state"
+)
+
+const (
+	// flowLookahead is how many cells down the field Next looks for a waypoint in a straight line.
+	flowLookahead = 8
+
+	// Flow fields measure distance in integer steps so they can be built with a
+	// bucket queue; a diagonal step costs 1.5 straight ones.
+	flowStraight = 2
+	flowDiagonal = 3
+
+	unreachable = math.MaxInt32
+)
+
+// FlowField holds the walking distance from every cell to one target, so any
+// number of agents chasing that target can each find their way with a lookup
+// instead of a search of their own.
+type FlowField struct {
+	grid    *NavGrid
+	target  state.Position
+	goal    int
+	version int
+	cost    []int32
+}
+
+// FlowField computes the distance field towards a target. It fails when the
+// target is outside the grid or inside a wall.
+func (ng *NavGrid) FlowField(target state.Position) (*FlowField, bool) {
+	goal := ng.index(target)
+	if goal == -1 {
+		return nil, false
+	}
+	if !ng.walkable(goal) {
+		if !ng.fits(target) {
+			return nil, false
+		}
+		var ok bool
+		if goal, ok = ng.nearestWalkable(goal); !ok {
+			return nil, false
+		}
+	}
+
+	cost := make([]int32, len(ng.blocked))
+	for i := range cost {
+		cost[i] = unreachable
+	}
+	cost[goal] = 0
+
+	// Dial's algorithm: every step adds at most flowDiagonal, so a ring of
+	// buckets one larger holds all distances still to be expanded. The
+	// neighbour relation is symmetric, so expanding from the goal gives the
+	// distance from each cell to it.
+	var buckets [flowDiagonal + 1][]int32
+	buckets[0] = append(buckets[0], int32(goal))
+	pending := 1
+
+	for dist := int32(0); pending > 0; dist++ {
+		bucket := &buckets[dist%int32(len(buckets))]
+		for len(*bucket) > 0 {
+			last := len(*bucket) - 1
+			current := int((*bucket)[last])
+			*bucket = (*bucket)[:last]
+			pending--
+
+			if cost[current] != dist {
+				continue
+			}
+
+			x, y := current%ng.width, current/ng.width
+			for _, s := range neighbourSteps {
+				next := ng.step(x, y, s.dx, s.dy)
+				if next == -1 {
+					continue
+				}
+
+				nextCost := dist + flowStepCost(s.dx, s.dy)
+				if nextCost >= cost[next] {
+					continue
+				}
+				cost[next] = nextCost
+				ring := &buckets[nextCost%int32(len(buckets))]
+				*ring = append(*ring, int32(next))
+				pending++
+			}
+		}
+	}
+
+	return &FlowField{
+		grid:    ng,
+		target:  target,
+		goal:    goal,
+		version: ng.version,
+		cost:    cost,
+	}, true
+}
+
+func flowStepCost(dx, dy int) int32 {
+	if dx != 0 && dy != 0 {
+		return flowDiagonal
+	}
+	return flowStraight
+}
+
+func (ff *FlowField) Target() state.Position { return ff.target }
+
+// Stale reports whether the navigation grid was rebuilt after the field was computed.
+func (ff *FlowField) Stale() bool {
+	return ff.version != ff.grid.version
+}
+
+// Distance returns the walking distance from a position to the target.
+func (ff *FlowField) Distance(pos state.Position) (float64, bool) {
+	index := ff.grid.index(pos)
+	if index == -1 || ff.cost[index] == unreachable {
+		return 0, false
+	}
+	return float64(ff.cost[index]) / flowStraight * ff.grid.cellSize, true
+}
+
+// Next returns the waypoint an agent at the position should walk to. It
+// follows the field downhill and returns the farthest cell within
+// flowLookahead that can be reached in a straight line, or the target itself
+// once the agent is in the goal cell or can walk straight to it.
+func (ff *FlowField) Next(pos state.Position) (state.Position, bool) {
+	ng := ff.grid
+	current := ng.index(pos)
+	if current == -1 {
+		return state.Position{}, false
+	}
+	if current == ff.goal || ng.index(ff.target) == current {
+		return ff.target, true
+	}
+
+	waypoint, found := state.Position{}, false
+	for range flowLookahead {
+		next := ff.downhill(current)
+		if next == -1 {
+			break
+		}
+		if next == ff.goal {
+			if ng.clearLine(pos, ff.target) {
+				return ff.target, true
+			}
+			if !found || ng.clearLine(pos, ng.center(next)) {
+				waypoint, found = ng.center(next), true
+			}
+			break
+		}
+
+		center := ng.center(next)
+		if found && !ng.clearLine(pos, center) {
+			break
+		}
+		waypoint, found = center, true
+		current = next
+	}
+	return waypoint, found
+}
+
+// downhill returns the neighbour closest to the target, or -1 at a dead end.
+// Blocked cells, where agents pushed against a wall may stand, step into any
+// walkable neighbour.
+func (ff *FlowField) downhill(index int) int {
+	ng := ff.grid
+	best, bestCost := -1, int32(unreachable)
+	x, y := index%ng.width, index/ng.width
+
+	for _, s := range neighbourSteps {
+		var next int
+		if ng.walkable(index) {
+			next = ng.step(x, y, s.dx, s.dy)
+		} else {
+			nx, ny := x+s.dx, y+s.dy
+			if nx < 0 || ny < 0 || nx >= ng.width || ny >= ng.height {
+				continue
+			}
+			next = ny*ng.width + nx
+		}
+		if next == -1 || ff.cost[next] >= ff.cost[index] {
+			continue
+		}
+
+		if cost := ff.cost[next] + flowStepCost(s.dx, s.dy); cost < bestCost {
+			best, bestCost = next, cost
+		}
+	}
+	return best
+}
@@ -0,0 +1,296 @@
+package pathfinding
+
+import (
+	"math"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+const (
+	// maxCellSize bounds the size of nav cells. World grid cells are split until
+	// they are at most this large, so narrow gaps between walls stay walkable.
+	maxCellSize = 2.5
+	// snapRange is how many cells away a blocked goal may be moved to reach a walkable cell.
+	snapRange = 4
+)
+
+// NavGrid is the walkable view of the static collision data for agents of one
+// radius. A cell is blocked when any part of it comes closer to a static
+// collider than the radius, so an agent anywhere inside a walkable cell never
+// touches a wall and a straight move between neighbouring walkable cells is
+// always clear. A grid is not safe for concurrent use.
+type NavGrid struct {
+	world         *state.World
+	cellSize      float64
+	width, height int
+	radius        float64
+	blocked       []bool
+	version       int
+	search        *search
+}
+
+// NewNavGrid builds the navigation grid of the world for agents of the given radius.
+func NewNavGrid(world *state.World, radius float64) *NavGrid {
+	gridWidth, gridHeight := world.Grid.Size()
+	split := max(1, int(math.Ceil(world.Grid.CellSize()/maxCellSize)))
+
+	ng := &NavGrid{
+		world:    world,
+		cellSize: world.Grid.CellSize() / float64(split),
+		width:    gridWidth * split,
+		height:   gridHeight * split,
+		radius:   radius,
+		blocked:  make([]bool, gridWidth*split*gridHeight*split),
+	}
+	ng.Rebuild(state.Bounds{
+		MaxX: float64(ng.width) * ng.cellSize,
+		MaxY: float64(ng.height) * ng.cellSize,
+	})
+	return ng
+}
+
+func (ng *NavGrid) CellSize() float64 { return ng.cellSize }
+
+func (ng *NavGrid) Radius() float64 { return ng.radius }
+
+// Version changes every time the grid is rebuilt, so derived data can tell it is stale.
+func (ng *NavGrid) Version() int { return ng.version }
+
+// Rebuild recomputes the cells an agent could touch within the given bounds,
+// typically the collider of a door or destructible wall that changed.
+func (ng *NavGrid) Rebuild(bounds state.Bounds) {
+	region := state.Bounds{
+		MinX: bounds.MinX - ng.radius, MinY: bounds.MinY - ng.radius,
+		MaxX: bounds.MaxX + ng.radius, MaxY: bounds.MaxY + ng.radius,
+	}
+	minX, minY, maxX, maxY, ok := ng.cellRange(region)
+	if !ok {
+		return
+	}
+
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			ng.blocked[y*ng.width+x] = false
+		}
+	}
+
+	// a collider spans several world cells, stamp it once
+	stamped := make(map[state.EntityID]struct{})
+	for _, cell := range ng.world.Grid.CellsInBounds(region) {
+		for _, entry := range cell.Entries {
+			if !entry.Layer.Has(state.LayerStatic) {
+				continue
+			}
+			if _, done := stamped[entry.EntityID]; done {
+				continue
+			}
+			stamped[entry.EntityID] = struct{}{}
+
+			collider, exist := ng.world.Collider.Get(entry.EntityID)
+			if !exist {
+				continue
+			}
+
+			boxMin, boxMax := collider.BoundingBox()
+			ng.stamp(boxMin, boxMax, minX, minY, maxX, maxY)
+		}
+	}
+
+	ng.version++
+}
+
+// stamp blocks the cells overlapping a box inflated by the agent radius, clipped to a cell range.
+func (ng *NavGrid) stamp(boxMin, boxMax vector.Vector2D, minX, minY, maxX, maxY int) {
+	fromX := max(int(math.Floor((boxMin.X-ng.radius)/ng.cellSize)), minX)
+	fromY := max(int(math.Floor((boxMin.Y-ng.radius)/ng.cellSize)), minY)
+	toX := min(int(math.Ceil((boxMax.X+ng.radius)/ng.cellSize))-1, maxX)
+	toY := min(int(math.Ceil((boxMax.Y+ng.radius)/ng.cellSize))-1, maxY)
+
+	for y := fromY; y <= toY; y++ {
+		for x := fromX; x <= toX; x++ {
+			ng.blocked[y*ng.width+x] = true
+		}
+	}
+}
+
+// cellRange returns the cells overlapping the bounds, clamped to the grid.
+func (ng *NavGrid) cellRange(bounds state.Bounds) (minX, minY, maxX, maxY int, ok bool) {
+	minX = max(int(math.Floor(bounds.MinX/ng.cellSize)), 0)
+	minY = max(int(math.Floor(bounds.MinY/ng.cellSize)), 0)
+	maxX = min(int(math.Floor(bounds.MaxX/ng.cellSize)), ng.width-1)
+	maxY = min(int(math.Floor(bounds.MaxY/ng.cellSize)), ng.height-1)
+	return minX, minY, maxX, maxY, minX <= maxX && minY <= maxY
+}
+
+// Walkable reports whether an agent standing at the position is clear of every static collider.
+func (ng *NavGrid) Walkable(pos state.Position) bool {
+	index := ng.index(pos)
+	return index != -1 && ng.walkable(index)
+}
+
+func (ng *NavGrid) walkable(index int) bool {
+	return !ng.blocked[index]
+}
+
+func (ng *NavGrid) index(pos state.Position) int {
+	x := int(math.Floor(pos.X / ng.cellSize))
+	y := int(math.Floor(pos.Y / ng.cellSize))
+	if x < 0 || y < 0 || x >= ng.width || y >= ng.height {
+		return -1
+	}
+	return y*ng.width + x
+}
+
+func (ng *NavGrid) center(index int) state.Position {
+	return state.Position{
+		X: (float64(index%ng.width) + 0.5) * ng.cellSize,
+		Y: (float64(index/ng.width) + 0.5) * ng.cellSize,
+	}
+}
+
+// step returns the neighbour of the cell at x, y in a direction, or -1 when the
+// move leaves the grid, enters a blocked cell or cuts the corner of one.
+func (ng *NavGrid) step(x, y, dx, dy int) int {
+	nx, ny := x+dx, y+dy
+	if nx < 0 || ny < 0 || nx >= ng.width || ny >= ng.height {
+		return -1
+	}
+
+	next := ny*ng.width + nx
+	if ng.blocked[next] {
+		return -1
+	}
+	if dx != 0 && dy != 0 && (ng.blocked[y*ng.width+nx] || ng.blocked[ny*ng.width+x]) {
+		return -1
+	}
+	return next
+}
+
+// clearLine reports whether the segment only crosses walkable cells. The cell
+// of from is not checked, so agents pushed into a blocked cell can leave it.
+func (ng *NavGrid) clearLine(from, to state.Position) bool {
+	x := int(math.Floor(from.X / ng.cellSize))
+	y := int(math.Floor(from.Y / ng.cellSize))
+	endX := int(math.Floor(to.X / ng.cellSize))
+	endY := int(math.Floor(to.Y / ng.cellSize))
+
+	stepX, tMaxX, tDeltaX := traversalAxis(from.X, to.X, x, ng.cellSize)
+	stepY, tMaxY, tDeltaY := traversalAxis(from.Y, to.Y, y, ng.cellSize)
+
+	open := func(x, y int) bool {
+		return x >= 0 && y >= 0 && x < ng.width && y < ng.height && !ng.blocked[y*ng.width+x]
+	}
+
+	for steps := abs(endX-x) + abs(endY-y); steps > 0 && (x != endX || y != endY); steps-- {
+		switch {
+		case tMaxX < tMaxY:
+			x += stepX
+			tMaxX += tDeltaX
+		case tMaxY < tMaxX:
+			y += stepY
+			tMaxY += tDeltaY
+		default:
+			// exactly through a corner, both side cells are touched
+			if !open(x+stepX, y) || !open(x, y+stepY) {
+				return false
+			}
+			x += stepX
+			y += stepY
+			tMaxX += tDeltaX
+			tMaxY += tDeltaY
+		}
+
+		if !open(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// traversalAxis returns the step direction, the segment fraction at which the
+// first cell boundary is crossed and the fraction between boundaries along one axis.
+func traversalAxis(from, to float64, cell int, cellSize float64) (int, float64, float64) {
+	delta := to - from
+	switch {
+	case delta > 0:
+		return 1, ((float64(cell+1))*cellSize - from) / delta, cellSize / delta
+	case delta < 0:
+		return -1, (float64(cell)*cellSize - from) / delta, -cellSize / delta
+	default:
+		return 0, math.Inf(1), math.Inf(1)
+	}
+}
+
+// nearestWalkable returns the closest walkable cell within snapRange rings of a cell.
+func (ng *NavGrid) nearestWalkable(index int) (int, bool) {
+	if ng.walkable(index) {
+		return index, true
+	}
+
+	x, y := index%ng.width, index/ng.width
+	origin := ng.center(index)
+
+	for ring := 1; ring <= snapRange; ring++ {
+		best, bestDist := -1, math.Inf(1)
+		for ny := y - ring; ny <= y+ring; ny++ {
+			for nx := x - ring; nx <= x+ring; nx++ {
+				onRing := abs(nx-x) == ring || abs(ny-y) == ring
+				if !onRing || nx < 0 || ny < 0 || nx >= ng.width || ny >= ng.height {
+					continue
+				}
+
+				candidate := ny*ng.width + nx
+				if ng.blocked[candidate] {
+					continue
+				}
+				if dist := vector.Vector2D(origin).DistanceTo(vector.Vector2D(ng.center(candidate))); dist < bestDist {
+					best, bestDist = candidate, dist
+				}
+			}
+		}
+		if best != -1 {
+			return best, true
+		}
+	}
+	return -1, false
+}
+
+// fits reports whether an agent centered on the position does not overlap a static collider.
+// Cells are blocked conservatively, so agents hugging a wall may stand in a blocked cell.
+func (ng *NavGrid) fits(pos state.Position) bool {
+	bounds := state.Bounds{
+		MinX: pos.X - ng.radius, MinY: pos.Y - ng.radius,
+		MaxX: pos.X + ng.radius, MaxY: pos.Y + ng.radius,
+	}
+
+	for _, cell := range ng.world.Grid.CellsInBounds(bounds) {
+		for _, entry := range cell.Entries {
+			if !entry.Layer.Has(state.LayerStatic) {
+				continue
+			}
+
+			collider, exist := ng.world.Collider.Get(entry.EntityID)
+			if !exist {
+				continue
+			}
+
+			boxMin, boxMax := collider.BoundingBox()
+			closest := vector.Vector2D{
+				X: math.Max(boxMin.X, math.Min(pos.X, boxMax.X)),
+				Y: math.Max(boxMin.Y, math.Min(pos.Y, boxMax.Y)),
+			}
+			if closest.DistanceTo(vector.Vector2D(pos)) < ng.radius {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,117 @@
+package pathfinding
+
+import (
+	"math/rand/v2"
+
+	"survival/internal/engine/state"
+)
+
+// maxFlowFields bounds the number of cached flow fields. Targets move, so old
+// fields are dropped least recently used first.
+const maxFlowFields = 16
+
+// Pathfinder answers path queries against the static collision data of a
+// world. Navigation grids are built on first use for every agent radius and
+// flow fields are shared by all agents chasing the same target cell.
+type Pathfinder struct {
+	world  *state.World
+	grids  map[float64]*NavGrid
+	fields map[flowKey]*cachedField
+	clock  int
+}
+
+type flowKey struct {
+	radius float64
+	goal   int
+}
+
+type cachedField struct {
+	field    *FlowField
+	lastUsed int
+}
+
+func NewPathfinder(world *state.World) *Pathfinder {
+	return &Pathfinder{
+		world:  world,
+		grids:  make(map[float64]*NavGrid),
+		fields: make(map[flowKey]*cachedField),
+	}
+}
+
+// Grid returns the navigation grid for agents of the given radius, building it if needed.
+func (p *Pathfinder) Grid(radius float64) *NavGrid {
+	grid, exist := p.grids[radius]
+	if !exist {
+		grid = NewNavGrid(p.world, radius)
+		p.grids[radius] = grid
+	}
+	return grid
+}
+
+// FindPath returns the smoothed path between two positions for an agent of the given radius.
+func (p *Pathfinder) FindPath(from, to state.Position, radius float64) ([]state.Position, bool) {
+	return p.Grid(radius).FindPath(from, to)
+}
+
+// FlowField returns a field towards the target for agents of the given radius.
+// Fields are cached per target cell, so the target they lead to is the one of
+// the first query for that cell.
+func (p *Pathfinder) FlowField(target state.Position, radius float64) (*FlowField, bool) {
+	grid := p.Grid(radius)
+	key := flowKey{radius: radius, goal: grid.index(target)}
+	p.clock++
+
+	if cached, exist := p.fields[key]; exist && !cached.field.Stale() {
+		cached.lastUsed = p.clock
+		return cached.field, true
+	}
+
+	field, ok := grid.FlowField(target)
+	if !ok {
+		delete(p.fields, key)
+		return nil, false
+	}
+
+	if _, exist := p.fields[key]; !exist && len(p.fields) >= maxFlowFields {
+		p.evictField()
+	}
+	p.fields[key] = &cachedField{field: field, lastUsed: p.clock}
+	return field, true
+}
+
+func (p *Pathfinder) evictField() {
+	var oldest flowKey
+	oldestUse := p.clock + 1
+	for key, cached := range p.fields {
+		if cached.lastUsed < oldestUse {
+			oldest, oldestUse = key, cached.lastUsed
+		}
+	}
+	delete(p.fields, oldest)
+}
+
+// Invalidate rebuilds every navigation grid within the bounds. Call it after a
+// static collider such as a door or destructible wall was added, moved or removed.
+// Flow fields computed before are stale and recomputed on their next query.
+func (p *Pathfinder) Invalidate(bounds state.Bounds) {
+	for _, grid := range p.grids {
+		grid.Rebuild(bounds)
+	}
+}
+
+// RandomWalkable returns the center of a random walkable cell within spread of
+// a position, giving up after the given number of attempts.
+func (p *Pathfinder) RandomWalkable(around state.Position, spread, radius float64, attempts int, rng *rand.Rand) (state.Position, bool) {
+	grid := p.Grid(radius)
+
+	for range attempts {
+		index := grid.index(state.Position{
+			X: around.X + (rng.Float64()*2-1)*spread,
+			Y: around.Y + (rng.Float64()*2-1)*spread,
+		})
+		if index != -1 && grid.walkable(index) {
+			return grid.center(index), true
+		}
+	}
+	return state.Position{}, false
+}
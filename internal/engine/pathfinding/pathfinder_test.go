@@ -0,0 +1,265 @@
+package pathfinding
+
+import (
+	"testing"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+const testRadius = 0.5
+
+func setupTestWorld() *state.World {
+	world := state.NewWorld(10, 10, 10)
+	world.Width = 100
+	world.Height = 100
+	return world
+}
+
+func addWall(world *state.World, centerX, centerY, halfW, halfH float64) (state.EntityID, []int) {
+	wallID, _ := world.Entity.Alloc()
+	collider := state.Collider{
+		Center:    state.Position{X: centerX, Y: centerY},
+		HalfSize:  vector.Vector2D{X: halfW, Y: halfH},
+		ShapeType: state.ColliderBox,
+	}
+	world.Collider.Upsert(wallID, collider)
+	world.EntityMeta.Upsert(wallID, state.WallMeta)
+
+	min, max := collider.BoundingBox()
+	cells := world.Grid.Add(wallID, state.Bounds{
+		MinX: min.X, MinY: min.Y,
+		MaxX: max.X, MaxY: max.Y,
+	}, state.LayerStatic)
+	return wallID, cells
+}
+
+func wallBounds(centerX, centerY, halfW, halfH float64) state.Bounds {
+	return state.Bounds{
+		MinX: centerX - halfW, MinY: centerY - halfH,
+		MaxX: centerX + halfW, MaxY: centerY + halfH,
+	}
+}
+
+// assertWalkable fails when an agent moving along the path would touch a wall.
+func assertWalkable(t *testing.T, ng *NavGrid, from state.Position, path []state.Position) {
+	t.Helper()
+
+	prev := vector.Vector2D(from)
+	for _, waypoint := range path {
+		segment := vector.Vector2D(waypoint).Sub(prev)
+		steps := int(segment.Magnitude()/0.1) + 1
+		for i := 1; i <= steps; i++ {
+			point := prev.Add(segment.Scale(float64(i) / float64(steps)))
+			if !ng.fits(state.Position(point)) {
+				t.Fatalf("Path step %v -> %v touches a wall at %v", prev, waypoint, point)
+			}
+		}
+		prev = vector.Vector2D(waypoint)
+	}
+}
+
+func TestFindPath_StraightLineIsSmoothed(t *testing.T) {
+	ng := NewNavGrid(setupTestWorld(), testRadius)
+
+	to := state.Position{X: 80, Y: 40}
+	path, ok := ng.FindPath(state.Position{X: 12, Y: 12}, to)
+	if !ok {
+		t.Fatalf("Expected a path in an empty world")
+	}
+	if len(path) != 1 || path[0] != to {
+		t.Errorf("Expected a single straight step to %v, got %v", to, path)
+	}
+}
+
+func TestFindPath_GoesAroundWall(t *testing.T) {
+	world := setupTestWorld()
+	// vertical wall from y=10 to y=90 leaves gaps at the top and bottom
+	addWall(world, 50, 50, 2, 40)
+	ng := NewNavGrid(world, testRadius)
+
+	from := state.Position{X: 20, Y: 50}
+	to := state.Position{X: 80, Y: 50}
+	path, ok := ng.FindPath(from, to)
+	if !ok {
+		t.Fatalf("Expected a path around the wall")
+	}
+	if path[len(path)-1] != to {
+		t.Errorf("Expected path to end at %v, got %v", to, path[len(path)-1])
+	}
+	// smoothing leaves the turns around the wall end only
+	if len(path) > 4 {
+		t.Errorf("Expected a smoothed path, got %d waypoints: %v", len(path), path)
+	}
+	assertWalkable(t, ng, from, path)
+}
+
+func TestFindPath_NarrowGap(t *testing.T) {
+	world := setupTestWorld()
+	// wall across the map with an 8 unit gap at x=50
+	addWall(world, 23, 50, 23, 1)
+	addWall(world, 77, 50, 23, 1)
+	ng := NewNavGrid(world, testRadius)
+
+	from := state.Position{X: 20, Y: 20}
+	path, ok := ng.FindPath(from, state.Position{X: 80, Y: 80})
+	if !ok {
+		t.Fatalf("Expected a path through the gap")
+	}
+	assertWalkable(t, ng, from, path)
+
+	// agents wider than the gap have to stay on their side
+	if _, ok := NewNavGrid(world, 2.5).FindPath(from, state.Position{X: 80, Y: 80}); ok {
+		t.Errorf("Expected no path for an agent wider than the gap")
+	}
+}
+
+func TestFindPath_UnreachableGoal(t *testing.T) {
+	world := setupTestWorld()
+	// box the goal in completely
+	addWall(world, 80, 70, 10, 1)
+	addWall(world, 80, 90, 10, 1)
+	addWall(world, 70, 80, 1, 10)
+	addWall(world, 90, 80, 1, 10)
+	ng := NewNavGrid(world, testRadius)
+
+	if _, ok := ng.FindPath(state.Position{X: 20, Y: 20}, state.Position{X: 80, Y: 80}); ok {
+		t.Errorf("Expected no path into a closed room")
+	}
+	if _, ok := ng.FindPath(state.Position{X: 20, Y: 20}, state.Position{X: 70, Y: 80}); ok {
+		t.Errorf("Expected no path into a wall")
+	}
+}
+
+func TestFindPath_TargetHuggingWall(t *testing.T) {
+	world := setupTestWorld()
+	addWall(world, 50, 50, 2, 20)
+	ng := NewNavGrid(world, testRadius)
+
+	// the target stands right against the wall, inside a blocked nav cell
+	to := state.Position{X: 52.6, Y: 50}
+	from := state.Position{X: 80, Y: 80}
+	path, ok := ng.FindPath(from, to)
+	if !ok {
+		t.Fatalf("Expected a path to a target against the wall")
+	}
+	if path[len(path)-1] != to {
+		t.Errorf("Expected path to end at %v, got %v", to, path[len(path)-1])
+	}
+}
+
+func TestFlowField_LeadsAroundWall(t *testing.T) {
+	world := setupTestWorld()
+	addWall(world, 50, 50, 2, 40)
+	ng := NewNavGrid(world, testRadius)
+
+	target := state.Position{X: 80, Y: 50}
+	field, ok := ng.FlowField(target)
+	if !ok {
+		t.Fatalf("Expected a flow field")
+	}
+
+	for _, start := range []state.Position{{X: 20, Y: 50}, {X: 45, Y: 20}, {X: 10, Y: 95}} {
+		pos := start
+		var path []state.Position
+		for range 200 {
+			waypoint, ok := field.Next(pos)
+			if !ok {
+				t.Fatalf("From %v: lost the field at %v", start, pos)
+			}
+			// walk up to one unit towards the waypoint
+			step := vector.Vector2D(waypoint).Sub(vector.Vector2D(pos))
+			if step.Magnitude() > 1 {
+				step = step.Normalize()
+			}
+			pos = state.Position(vector.Vector2D(pos).Add(step))
+			path = append(path, pos)
+
+			if pos == target {
+				break
+			}
+		}
+
+		if pos != target {
+			t.Errorf("From %v: expected to arrive at %v, stopped at %v", start, target, pos)
+		}
+		assertWalkable(t, ng, start, path)
+	}
+}
+
+func TestPathfinder_FlowFieldIsShared(t *testing.T) {
+	p := NewPathfinder(setupTestWorld())
+
+	first, ok := p.FlowField(state.Position{X: 80, Y: 50}, testRadius)
+	if !ok {
+		t.Fatalf("Expected a flow field")
+	}
+	// a target moving inside the same cell reuses the field
+	second, _ := p.FlowField(state.Position{X: 80.5, Y: 50.5}, testRadius)
+	if first != second {
+		t.Errorf("Expected the cached field for the same target cell")
+	}
+	// other agent sizes get their own
+	if other, _ := p.FlowField(state.Position{X: 80, Y: 50}, 1); other == first {
+		t.Errorf("Expected a separate field for another radius")
+	}
+}
+
+func TestPathfinder_InvalidateRebuildsChangedArea(t *testing.T) {
+	world := setupTestWorld()
+	// wall across the map with a door sized gap at x=50
+	addWall(world, 22.5, 50, 22.5, 1)
+	addWall(world, 77.5, 50, 22.5, 1)
+	p := NewPathfinder(world)
+
+	from, to := state.Position{X: 20, Y: 20}, state.Position{X: 80, Y: 80}
+	if _, ok := p.FindPath(from, to, testRadius); !ok {
+		t.Fatalf("Expected a path through the open door")
+	}
+	field, _ := p.FlowField(to, testRadius)
+	openDistance, _ := field.Distance(from)
+
+	// close the door
+	doorID, doorCells := addWall(world, 50, 50, 5, 1)
+	p.Invalidate(wallBounds(50, 50, 5, 1))
+
+	if _, ok := p.FindPath(from, to, testRadius); ok {
+		t.Errorf("Expected no path through the closed door")
+	}
+	if !field.Stale() {
+		t.Errorf("Expected the flow field to be stale after the rebuild")
+	}
+	closed, _ := p.FlowField(to, testRadius)
+	if _, ok := closed.Distance(from); ok {
+		t.Errorf("Expected the target to be unreachable in the new flow field")
+	}
+
+	// open it again
+	world.Grid.Remove(doorCells, doorID)
+	world.DestroyEntity(doorID)
+	p.Invalidate(wallBounds(50, 50, 5, 1))
+
+	if _, ok := p.FindPath(from, to, testRadius); !ok {
+		t.Errorf("Expected a path after the door opened again")
+	}
+	reopened, _ := p.FlowField(to, testRadius)
+	if distance, ok := reopened.Distance(from); !ok || distance != openDistance {
+		t.Errorf("Expected distance %.2f after reopening, got %.2f", openDistance, distance)
+	}
+}
+
+func TestNavGrid_RebuildMatchesFullBuild(t *testing.T) {
+	world := setupTestWorld()
+	addWall(world, 30, 30, 5, 5)
+	ng := NewNavGrid(world, testRadius)
+
+	addWall(world, 60, 70, 3, 8)
+	ng.Rebuild(wallBounds(60, 70, 3, 8))
+
+	fresh := NewNavGrid(world, testRadius)
+	for i := range fresh.blocked {
+		if ng.blocked[i] != fresh.blocked[i] {
+			t.Fatalf("Cell %v differs between incremental and full build", ng.center(i))
+		}
+	}
+}
@@ -6,20 +6,37 @@ import (
 )
 
 const (
-	PlayerInputEnvelope RequestEnvelopeType = "player_input"
-	ListRoomsEnvelope   RequestEnvelopeType = "list_rooms"
-	RequestJoinEnvelope RequestEnvelopeType = "request_join"
+	PlayerInputEnvelope       RequestEnvelopeType = "player_input"
+	ListRoomsEnvelope         RequestEnvelopeType = "list_rooms"
+	RequestJoinEnvelope       RequestEnvelopeType = "request_join"
+	ScoreboardRequestEnvelope RequestEnvelopeType = "request_scoreboard"
+	InventoryEquipEnvelope    RequestEnvelopeType = "inventory_equip"
+	InventoryDropEnvelope     RequestEnvelopeType = "inventory_drop"
+	InventoryPickUpEnvelope   RequestEnvelopeType = "inventory_pick_up"
+	InventoryMoveEnvelope     RequestEnvelopeType = "inventory_move"
+	SnapshotAckEnvelope       RequestEnvelopeType = "snapshot_ack"
 
 	GameUpdateEnvelope        ResponseEnvelopeType = "game_update"
+	GameUpdateDeltaEnvelope   ResponseEnvelopeType = "game_update_delta"
 	StaticDataEnvelope        ResponseEnvelopeType = "static_data"
+	StaticDataDeltaEnvelope   ResponseEnvelopeType = "static_data_delta"
 	SystemNotifyEnvelop       ResponseEnvelopeType = "system_notify"
 	SystemSetSessionEnvelope  ResponseEnvelopeType = "system_set_session"
 	ErrInvalidSession         ResponseEnvelopeType = "error_invalid_session"
 	ListRoomsResponseEnvelope ResponseEnvelopeType = "list_rooms_response"
 	ErrorResponseEnvelope     ResponseEnvelopeType = "error"
 	JoinRoomSuccessEnvelope   ResponseEnvelopeType = "join_room_success"
+	LightMapEnvelope          ResponseEnvelopeType = "light_map"
+	ScoreboardEnvelope        ResponseEnvelopeType = "scoreboard"
+	InventoryUpdateEnvelope   ResponseEnvelopeType = "inventory_update"
+	PlayerLeftEnvelope        ResponseEnvelopeType = "player_left"
+	LightMapDeltaEnvelope     ResponseEnvelopeType = "light_map_delta"
 )
 
+// ErrorCodeBadRequest marks an ErrorPayload for a request the server rejected,
+// the connection stays usable.
+const ErrorCodeBadRequest = 400
+
 type ResponseEnvelopeType string
 
 type RequestEnvelopeType string
@@ -41,17 +58,42 @@ type ResponseEnvelope struct {
 	Payload      json.RawMessage      `json:"payload"`
 }
 
+// Subprotocols offered in Sec-WebSocket-Protocol, each selecting the codec of
+// the connection. Connections that negotiate none use JSON.
+const (
+	SubprotocolBinary = "survival.binary.v1"
+	SubprotocolJSON   = "survival.json.v1"
+)
+
 // Codec is an interface for encoding and decoding messages.
 type Codec interface {
 	Encode(data interface{}) ([]byte, error)
 	Decode(data []byte, v interface{}) error
 }
 
+// Both ends of a connection ping the other every PingInterval and drop the
+// connection once they heard nothing, pongs included, for IdleTimeout.
+const (
+	PingInterval = 5 * time.Second
+	IdleTimeout  = 3 * PingInterval
+)
+
+// RTT are the round trip times of a connection measured with pings. Smoothed
+// follows the samples slowly, Jitter is the mean change between samples.
+type RTT struct {
+	Last     time.Duration
+	Smoothed time.Duration
+	Jitter   time.Duration
+	Samples  int
+}
+
 // RawConnection is an interface for raw connection operations.
 type RawConnection interface {
 	ReadMessage() ([]byte, error)
 	WriteMessage(data []byte) error
 	Close() error
+	// RTT returns the round trip times measured so far.
+	RTT() RTT
 }
 
 type Command struct {
@@ -72,13 +114,31 @@ type PlayerInput struct {
 	MoveHorizontal float64      `json:"MoveHorizontal"`
 	LookHorizontal float64      `json:"LookHorizontal"`
 	MovementType   MovementType `json:"MovementType"`
+	Sprint         bool         `json:"Sprint"`
 	SwitchWeapon   bool         `json:"SwitchWeapon"`
 	Reload         bool         `json:"Reload"`
 	FastReload     bool         `json:"FastReload"`
 	Fire           bool         `json:"Fire"`
 	Timestamp      int64        `json:"Timestamp"`
+
+	ToggleFlashlight bool `json:"ToggleFlashlight"`
+
+	// Sequence numbers the inputs of a client, which the server reports back
+	// in MovementInfo.LastInput once applied.
+	Sequence uint32 `json:"Sequence,omitempty"`
+	// InterpolationDelay is how many milliseconds behind the latest game
+	// update the client draws the other players. Shots are resolved against
+	// the players where the client drew them.
+	InterpolationDelay uint32 `json:"InterpolationDelay,omitempty"`
+	// Previous resends the inputs sent just before, oldest first, in case
+	// they got lost. The server drops the ones it already has.
+	Previous []PlayerInput `json:"Previous,omitempty"`
 }
 
+// InputRedundancy is the number of previous inputs a client resends with each
+// input.
+const InputRedundancy = 3
+
 type RequestJoinPayload struct {
 	RoomID string `json:"room_id"`
 }
@@ -96,39 +156,201 @@ type RoomInfo struct {
 	Name        string `json:"name"`
 	PlayerCount int    `json:"player_count"`
 	MaxPlayers  int    `json:"max_players"`
+	Mode        string `json:"mode,omitempty"`
 }
 
 type SystemNotify struct {
 	Message string `json:"message"`
 }
 
+// LoginRequest is posted to /login for a token to connect with.
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginResponse carries the token presented on the WebSocket upgrade, as a
+// bearer token or the token query parameter, and the identity it was issued
+// for. ExpiresAt is unix seconds.
+type LoginResponse struct {
+	Token     string `json:"token"`
+	ClientID  string `json:"client_id"`
+	Name      string `json:"name"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
 type SystemSetSessionPayload struct {
 	ClientID  string `json:"client_id"`
 	SessionID string `json:"session_id"`
 }
 
+// PlayerLeftPayload tells the players of a room that a player left it for
+// good, its session expired.
+type PlayerLeftPayload struct {
+	PlayerID uint64 `json:"player_id"`
+	Name     string `json:"name"`
+}
+
 type ErrorPayload struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
 
+// GameUpdatePayload is the full state a player sees. Sequence numbers the
+// updates sent to the client, which acks them so the next ones can be sent as
+// a GameUpdateDeltaPayload.
 type GameUpdatePayload struct {
-	Me        PlayerInfo   `json:"me"`
-	Views     []PlayerInfo `json:"views"`
-	Timestamp int64        `json:"timestamp"` // timestamp unix milli
+	Sequence  uint32        `json:"seq"`
+	Me        PlayerInfo    `json:"me"`
+	Movement  *MovementInfo `json:"movement,omitempty"` // only set for players that can move
+	Views     []PlayerInfo  `json:"views"`
+	Sounds    []SoundInfo   `json:"sounds,omitempty"`
+	Survival  *SurvivalInfo `json:"survival,omitempty"` // only set in survival rooms
+	Round     *RoundInfo    `json:"round,omitempty"`    // only set in rooms playing rounds
+	Zone      *ZoneInfo     `json:"zone,omitempty"`     // only set while a safe zone is active
+	Timestamp int64         `json:"timestamp"`          // timestamp unix milli
+}
+
+const (
+	SurvivalPhaseWaiting   = "waiting"
+	SurvivalPhaseCountdown = "countdown"
+	SurvivalPhaseWave      = "wave"
+	SurvivalPhaseWon       = "won"
+	SurvivalPhaseLost      = "lost"
+)
+
+const (
+	RoundPhaseWarmup    = "warmup"
+	RoundPhaseLive      = "live"
+	RoundPhasePostRound = "post_round"
+)
+
+// RoundInfo is the round progress of a room. Timer is the seconds left in the
+// phase, 0 while waiting for players or in rounds without a time limit.
+// Winner and WinnerTeam are only set after a round was decided.
+type RoundInfo struct {
+	Mode       string  `json:"mode"`
+	Phase      string  `json:"phase"`
+	Round      int     `json:"round"`
+	Timer      float64 `json:"timer"`
+	Winner     uint64  `json:"winner,omitempty"`
+	WinnerTeam int     `json:"winner_team,omitempty"`
+}
+
+// ZoneInfo is the battle royale safe zone, a circle players take Damage
+// outside of every second. TimeToShrink is the seconds until the zone starts
+// shrinking towards the next circle, or while Shrinking until it gets there.
+type ZoneInfo struct {
+	X            float64 `json:"x"`
+	Y            float64 `json:"y"`
+	Radius       float64 `json:"radius"`
+	NextX        float64 `json:"next_x"`
+	NextY        float64 `json:"next_y"`
+	NextRadius   float64 `json:"next_radius"`
+	TimeToShrink float64 `json:"time_to_shrink"`
+	Shrinking    bool    `json:"shrinking,omitempty"`
+	Phase        int     `json:"phase"`
+	Damage       int     `json:"damage"`
+}
+
+// SurvivalInfo is the wave progress of a survival room.
+// Countdown is the seconds left before the next wave, or before the reset once won or lost.
+type SurvivalInfo struct {
+	Phase            string  `json:"phase"`
+	Wave             int     `json:"wave"`
+	TotalWaves       int     `json:"total_waves"`
+	Countdown        float64 `json:"countdown"`
+	EnemiesRemaining int     `json:"enemies_remaining"`
+}
+
+const (
+	SoundKindFootstep = "footstep"
+	SoundKindGunshot  = "gunshot"
+	SoundKindDoor     = "door"
+)
+
+// SoundInfo is a sound the player heard during the last tick.
+// Dir is the approximate world angle towards the sound, Loudness is in (0, 1].
+type SoundInfo struct {
+	Kind     string  `json:"kind"`
+	Dir      float64 `json:"dir"`
+	Loudness float64 `json:"loudness"`
 }
 
 type PlayerInfo struct {
-	ID  uint64  `json:"id"`
-	X   float64 `json:"x"`
-	Y   float64 `json:"y"`
-	Dir float64 `json:"dir"`
+	ID     uint64  `json:"id"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Dir    float64 `json:"dir"`
+	Health int     `json:"health"`
+	Enemy  bool    `json:"enemy,omitempty"`
+	// Team is the team of the player in team modes, 0 otherwise.
+	Team int `json:"team,omitempty"`
+	// Stamina is the percentage of stamina left, only sent for the receiving player.
+	Stamina int `json:"stamina,omitempty"`
+	// Effects are the active status effects, only sent for the receiving player.
+	Effects []EffectInfo `json:"effects,omitempty"`
+}
+
+// EffectInfo is an active status effect such as "bleed" or "stun".
+// Remaining is the time left in seconds.
+type EffectInfo struct {
+	Kind      string  `json:"kind"`
+	Remaining float64 `json:"remaining"`
+	Stacks    int     `json:"stacks"`
 }
 
+// MovementInfo is what the client needs to predict the moves of the
+// receiving player with the movement package. Me holds the position and
+// direction after the input numbered LastInput, VX and VY the velocity.
+// Speed is the movement speed with effects applied, before sprinting. The
+// player moves without inertia when Acceleration is 0.
+type MovementInfo struct {
+	LastInput     uint32  `json:"last_input"`
+	VX            float64 `json:"vx"`
+	VY            float64 `json:"vy"`
+	Speed         float64 `json:"speed"`
+	RotationSpeed float64 `json:"rotation_speed"`
+	Radius        float64 `json:"radius"`
+	Acceleration  float64 `json:"acceleration,omitempty"`
+	Friction      float64 `json:"friction,omitempty"`
+	MaxSpeed      float64 `json:"max_speed,omitempty"`
+	CanSprint     bool    `json:"can_sprint,omitempty"`
+	Stunned       bool    `json:"stunned,omitempty"`
+}
+
+// StaticDataPayload holds the static colliders of the floor the player is on,
+// sent again whenever the player moves to another floor.
 type StaticDataPayload struct {
 	Colliders []Collider `json:"colliders"`
 	MapWidth  float64    `json:"map_width"`
 	MapHeight float64    `json:"map_height"`
+	// Floor is the floor of the colliders, 0 is the ground floor.
+	Floor      int     `json:"floor"`
+	FloorCount int     `json:"floor_count"`
+	Stairs     []Stair `json:"stairs,omitempty"`
+}
+
+const (
+	StairKindStairs = "stairs"
+	StairKindLadder = "ladder"
+)
+
+// Stair is an area on the current floor leading to floor To.
+type Stair struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	HalfX float64 `json:"half_x"`
+	HalfY float64 `json:"half_y"`
+	Kind  string  `json:"kind"`
+	To    int     `json:"to"`
+}
+
+// StaticDataDeltaPayload updates the static data sent on join with the
+// colliders removed from and added to the map, such as destroyed walls.
+type StaticDataDeltaPayload struct {
+	Removed []uint64   `json:"removed,omitempty"`
+	Added   []Collider `json:"added,omitempty"`
 }
 
 type Collider struct {
@@ -143,3 +365,94 @@ type Collider struct {
 	Height        float64 `json:"height"`
 	BaseElevation float64 `json:"base_elevation"`
 }
+
+// LightMapPayload carries the light level of every map grid cell in row-major order.
+// Levels range from 0 (pitch dark) to 255 (fully lit).
+type LightMapPayload struct {
+	CellSize float64 `json:"cell_size"`
+	Width    int     `json:"width"`
+	Height   int     `json:"height"`
+	Levels   []uint8 `json:"levels"`
+}
+
+// LightMapDeltaPayload carries the light levels of the cells that changed
+// since the last light map or delta, Levels[i] being the level of the cell at
+// the row-major index Indexes[i].
+type LightMapDeltaPayload struct {
+	Indexes []int   `json:"indexes"`
+	Levels  []uint8 `json:"levels"`
+}
+
+type ScoreboardRequestPayload struct {
+	// No fields needed, the scoreboard of the sender's room is returned
+}
+
+// ScoreboardPayload lists the statistics of the players in the room for the
+// current match. It is sent on request and whenever it changes.
+type ScoreboardPayload struct {
+	Entries []ScoreboardEntry `json:"entries"`
+}
+
+// ScoreboardEntry is the statistics of one player. Damage is the damage dealt
+// to others, Accuracy the share of shots that hit, from 0 to 1.
+type ScoreboardEntry struct {
+	PlayerID uint64  `json:"player_id"`
+	Name     string  `json:"name"`
+	Bot      bool    `json:"bot,omitempty"`
+	Team     int     `json:"team,omitempty"`
+	Kills    int     `json:"kills"`
+	Deaths   int     `json:"deaths"`
+	Assists  int     `json:"assists"`
+	Damage   int     `json:"damage"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+type InventoryEquipPayload struct {
+	ItemID string `json:"item_id"`
+}
+
+type InventoryDropPayload struct {
+	ItemID string `json:"item_id"`
+}
+
+type InventoryPickUpPayload struct {
+	PickupID uint64 `json:"pickup_id"`
+}
+
+// InventoryMovePayload moves an item to Slot, counted within its kind.
+type InventoryMovePayload struct {
+	ItemID string `json:"item_id"`
+	Slot   int    `json:"slot"`
+}
+
+// InventoryPayload is what the player carries and the items lying within
+// reach. It is sent to the owner whenever it changes.
+type InventoryPayload struct {
+	Items    []InventoryItem `json:"items"`
+	MaxSlots int             `json:"max_slots"`
+	Nearby   []GroundItem    `json:"nearby,omitempty"`
+}
+
+const (
+	ItemKindMelee    = "melee"
+	ItemKindRanged   = "ranged"
+	ItemKindMagazine = "magazine"
+)
+
+// InventoryItem is a carried item. Ammo and Capacity describe the magazine,
+// for a ranged weapon the loaded one.
+type InventoryItem struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Ammo     int    `json:"ammo,omitempty"`
+	Capacity int    `json:"capacity,omitempty"`
+	Equipped bool   `json:"equipped,omitempty"`
+}
+
+// GroundItem is an item lying within reach, PickupID identifies it for pick up.
+type GroundItem struct {
+	PickupID uint64        `json:"pickup_id"`
+	Item     InventoryItem `json:"item"`
+	X        float64       `json:"x"`
+	Y        float64       `json:"y"`
+}
@@ -0,0 +1,223 @@
+package ports
+
+import "math"
+
+// PositionQuantum is the precision of the positions of other players in game
+// updates. A power of two keeps the quantized positions exact in floats, so
+// the client rebuilds deltas to the very values the server compared.
+const PositionQuantum = 1.0 / 64
+
+// angleSteps is the number of directions a turn is quantized to.
+const angleSteps = 1 << 16
+
+// SnapshotHistorySize is the number of game updates the server can send a
+// delta against and the client keeps to apply them. An update acked longer
+// ago than this is followed by a full one.
+const SnapshotHistorySize = 32
+
+// PlayerFields marks the fields of a PlayerDelta that changed.
+type PlayerFields uint8
+
+const (
+	PlayerFieldPosition PlayerFields = 1 << iota
+	PlayerFieldDir
+	PlayerFieldHealth
+	PlayerFieldEnemy
+	PlayerFieldTeam
+
+	PlayerFieldAll = PlayerFieldPosition | PlayerFieldDir | PlayerFieldHealth | PlayerFieldEnemy | PlayerFieldTeam
+)
+
+// GameUpdateDeltaPayload is a game update sent as the changes since the
+// update numbered Baseline, which the client acked. Views only holds the
+// players that changed or came into view, Removed the ones out of view. The
+// receiving player, its movement, sounds and mode information are sent in full.
+type GameUpdateDeltaPayload struct {
+	Sequence  uint32        `json:"seq"`
+	Baseline  uint32        `json:"baseline"`
+	Me        PlayerInfo    `json:"me"`
+	Movement  *MovementInfo `json:"movement,omitempty"`
+	Views     []PlayerDelta `json:"views,omitempty"`
+	Removed   []uint64      `json:"removed,omitempty"`
+	Sounds    []SoundInfo   `json:"sounds,omitempty"`
+	Survival  *SurvivalInfo `json:"survival,omitempty"`
+	Round     *RoundInfo    `json:"round,omitempty"`
+	Zone      *ZoneInfo     `json:"zone,omitempty"`
+	Timestamp int64         `json:"timestamp"`
+}
+
+// PlayerDelta is a player in view with the fields listed in Fields. DX and DY
+// are the quantized move since the baseline, from 0 for a player new in view,
+// Dir is the quantized direction.
+type PlayerDelta struct {
+	ID     uint64       `json:"id"`
+	Fields PlayerFields `json:"fields"`
+	DX     int32        `json:"dx,omitempty"`
+	DY     int32        `json:"dy,omitempty"`
+	Dir    uint16       `json:"dir,omitempty"`
+	Health int          `json:"health,omitempty"`
+	Enemy  bool         `json:"enemy,omitempty"`
+	Team   int          `json:"team,omitempty"`
+}
+
+// SnapshotAckPayload acknowledges the game update numbered Sequence, the
+// latest one the client received.
+type SnapshotAckPayload struct {
+	Sequence uint32 `json:"seq"`
+}
+
+func QuantizePosition(v float64) int32 {
+	return int32(math.Round(v / PositionQuantum))
+}
+
+func DequantizePosition(q int32) float64 {
+	return float64(q) * PositionQuantum
+}
+
+// QuantizeAngle maps an angle in radians to one of angleSteps directions.
+func QuantizeAngle(rad float64) uint16 {
+	turns := rad / (2 * math.Pi)
+	turns -= math.Floor(turns)
+	return uint16(int(math.Round(turns*angleSteps)) % angleSteps)
+}
+
+// DequantizeAngle returns the angle in radians in [0, 2π).
+func DequantizeAngle(q uint16) float64 {
+	return float64(q) * 2 * math.Pi / angleSteps
+}
+
+// QuantizeViews rounds the positions and directions of the players in view
+// to what deltas can carry, so full and delta updates agree.
+func QuantizeViews(update *GameUpdatePayload) {
+	for i, view := range update.Views {
+		update.Views[i].X = DequantizePosition(QuantizePosition(view.X))
+		update.Views[i].Y = DequantizePosition(QuantizePosition(view.Y))
+		update.Views[i].Dir = DequantizeAngle(QuantizeAngle(view.Dir))
+	}
+}
+
+// DiffGameUpdate returns next as a delta against base. Both must have their
+// views quantized.
+func DiffGameUpdate(base, next GameUpdatePayload) GameUpdateDeltaPayload {
+	delta := GameUpdateDeltaPayload{
+		Sequence:  next.Sequence,
+		Baseline:  base.Sequence,
+		Me:        next.Me,
+		Movement:  next.Movement,
+		Sounds:    next.Sounds,
+		Survival:  next.Survival,
+		Round:     next.Round,
+		Zone:      next.Zone,
+		Timestamp: next.Timestamp,
+	}
+
+	previous := make(map[uint64]PlayerInfo, len(base.Views))
+	for _, view := range base.Views {
+		previous[view.ID] = view
+	}
+	for _, view := range next.Views {
+		old, exist := previous[view.ID]
+		delete(previous, view.ID)
+		if player := diffPlayer(old, view, exist); player.Fields != 0 {
+			delta.Views = append(delta.Views, player)
+		}
+	}
+	for _, view := range base.Views {
+		if _, gone := previous[view.ID]; gone {
+			delta.Removed = append(delta.Removed, view.ID)
+		}
+	}
+	return delta
+}
+
+func diffPlayer(old, next PlayerInfo, exist bool) PlayerDelta {
+	if !exist {
+		old = PlayerInfo{}
+	}
+	delta := PlayerDelta{ID: next.ID}
+	dx := QuantizePosition(next.X) - QuantizePosition(old.X)
+	dy := QuantizePosition(next.Y) - QuantizePosition(old.Y)
+	if !exist || dx != 0 || dy != 0 {
+		delta.Fields |= PlayerFieldPosition
+		delta.DX, delta.DY = dx, dy
+	}
+	if dir := QuantizeAngle(next.Dir); !exist || dir != QuantizeAngle(old.Dir) {
+		delta.Fields |= PlayerFieldDir
+		delta.Dir = dir
+	}
+	if !exist || next.Health != old.Health {
+		delta.Fields |= PlayerFieldHealth
+		delta.Health = next.Health
+	}
+	if !exist || next.Enemy != old.Enemy {
+		delta.Fields |= PlayerFieldEnemy
+		delta.Enemy = next.Enemy
+	}
+	if !exist || next.Team != old.Team {
+		delta.Fields |= PlayerFieldTeam
+		delta.Team = next.Team
+	}
+	return delta
+}
+
+// Apply rebuilds the full game update from the delta and its baseline.
+func (d GameUpdateDeltaPayload) Apply(base GameUpdatePayload) GameUpdatePayload {
+	update := GameUpdatePayload{
+		Sequence:  d.Sequence,
+		Me:        d.Me,
+		Movement:  d.Movement,
+		Sounds:    d.Sounds,
+		Survival:  d.Survival,
+		Round:     d.Round,
+		Zone:      d.Zone,
+		Timestamp: d.Timestamp,
+	}
+
+	removed := make(map[uint64]bool, len(d.Removed))
+	for _, id := range d.Removed {
+		removed[id] = true
+	}
+	changed := make(map[uint64]PlayerDelta, len(d.Views))
+	for _, view := range d.Views {
+		changed[view.ID] = view
+	}
+
+	update.Views = make([]PlayerInfo, 0, len(base.Views)+len(d.Views))
+	for _, view := range base.Views {
+		if removed[view.ID] {
+			continue
+		}
+		if delta, exist := changed[view.ID]; exist {
+			view = delta.apply(view)
+			delete(changed, view.ID)
+		}
+		update.Views = append(update.Views, view)
+	}
+	// the rest came into view since the baseline
+	for _, view := range d.Views {
+		if delta, exist := changed[view.ID]; exist {
+			update.Views = append(update.Views, delta.apply(PlayerInfo{ID: view.ID}))
+		}
+	}
+	return update
+}
+
+func (d PlayerDelta) apply(player PlayerInfo) PlayerInfo {
+	if d.Fields&PlayerFieldPosition != 0 {
+		player.X = DequantizePosition(QuantizePosition(player.X) + d.DX)
+		player.Y = DequantizePosition(QuantizePosition(player.Y) + d.DY)
+	}
+	if d.Fields&PlayerFieldDir != 0 {
+		player.Dir = DequantizeAngle(d.Dir)
+	}
+	if d.Fields&PlayerFieldHealth != 0 {
+		player.Health = d.Health
+	}
+	if d.Fields&PlayerFieldEnemy != 0 {
+		player.Enemy = d.Enemy
+	}
+	if d.Fields&PlayerFieldTeam != 0 {
+		player.Team = d.Team
+	}
+	return player
+}
@@ -1,6 +1,10 @@
 package state
 
-import "sync"
+import (
+	"sync"
+
+	"survival/internal/engine/vector"
+)
 
 type WorldCommand struct {
 	EntityID   EntityID
@@ -17,6 +21,26 @@ type WorldCommand struct {
 	VerticalBody  VerticalBody
 	Input         Input
 	PrePosition   PrePosition
+	ViewIDs       ViewIDs
+	Light         Light
+	Hearing       HeardSounds
+	Weapon        Weapon
+	Bot           Bot
+	Enemy         Enemy
+	Stamina       Stamina
+	Effects       Effects
+	Team          Team
+	Inventory     Inventory
+	Pickup        Pickup
+	Destructible  Destructible
+	Floor         Floor
+	Velocity      Velocity
+
+	// Impulse is added to the entity Velocity after the components are updated.
+	Impulse vector.Vector2D
+
+	// Destroy removes the entity and all its components instead of updating them.
+	Destroy bool
 }
 
 // CommandBuffer is a thread-safe buffer for WorldCommands.
@@ -0,0 +1,60 @@
+package state
+
+import "survival/internal/engine/vector"
+
+type CombatEventKind uint8
+
+const (
+	CombatShot CombatEventKind = iota
+	CombatHit
+	CombatKill
+)
+
+// CombatEvent records a shot, hit or kill that happened during the current tick.
+// Victim and Damage are only set for hits and kills. Shot marks hits and kills
+// of a fired weapon, damage over time such as bleeding leaves it unset.
+type CombatEvent struct {
+	Kind     CombatEventKind
+	Attacker EntityID
+	Victim   EntityID
+	Damage   int
+	Shot     bool
+}
+
+// Damage is dealt by something other than a shot, such as a bleed. It is
+// applied by the combat system so it adds up with the shots of the same tick.
+// Impulse knocks the victim back when the damage lands, such as the blast of
+// an explosion.
+type Damage struct {
+	Attacker EntityID
+	Victim   EntityID
+	Amount   int
+	Impulse  vector.Vector2D
+}
+
+// QueueDamage queues damage to be applied on the next combat update.
+func (w *World) QueueDamage(damage Damage) {
+	w.damage = append(w.damage, damage)
+}
+
+// QueuedDamage returns the damage queued since the last ClearQueuedDamage.
+func (w *World) QueuedDamage() []Damage {
+	return w.damage
+}
+
+func (w *World) ClearQueuedDamage() {
+	w.damage = w.damage[:0]
+}
+
+func (w *World) EmitCombatEvent(event CombatEvent) {
+	w.combatEvents = append(w.combatEvents, event)
+}
+
+// CombatEvents returns the combat events emitted since the last ClearCombatEvents.
+func (w *World) CombatEvents() []CombatEvent {
+	return w.combatEvents
+}
+
+func (w *World) ClearCombatEvents() {
+	w.combatEvents = w.combatEvents[:0]
+}
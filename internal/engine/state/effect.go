@@ -0,0 +1,99 @@
+package state
+
+import "math"
+
+type EffectKind uint8
+
+const (
+	EffectNone EffectKind = iota
+	// EffectBleed deals Magnitude damage per stack on every tick.
+	EffectBleed
+	// EffectSlow reduces the movement speed by the Magnitude fraction.
+	EffectSlow
+	// EffectStun stops moving, turning and firing.
+	EffectStun
+	// EffectInvulnerable ignores all damage, given for a moment after spawning.
+	EffectInvulnerable
+)
+
+func (k EffectKind) String() string {
+	switch k {
+	case EffectBleed:
+		return "bleed"
+	case EffectSlow:
+		return "slow"
+	case EffectStun:
+		return "stun"
+	case EffectInvulnerable:
+		return "invulnerable"
+	default:
+		return "none"
+	}
+}
+
+// Effect is a timed status effect on an entity. Remaining is the time left in
+// seconds and TickIn the time until the next tick of effects that tick.
+// Source is the entity that caused it, credited for bleed damage.
+type Effect struct {
+	Kind      EffectKind
+	Remaining float64
+	Magnitude float64
+	Stacks    int
+	Source    EntityID
+	TickIn    float64
+}
+
+// Effects are the active effects of an entity, at most one per kind.
+type Effects []Effect
+
+func (e Effects) Get(kind EffectKind) (Effect, bool) {
+	for _, effect := range e {
+		if effect.Kind == kind {
+			return effect, true
+		}
+	}
+	return Effect{}, false
+}
+
+func (e Effects) Has(kind EffectKind) bool {
+	_, ok := e.Get(kind)
+	return ok
+}
+
+// MovementFactor scales the movement speed by the effects, 0 while stunned.
+func (e Effects) MovementFactor() float64 {
+	if e.Has(EffectStun) {
+		return 0
+	}
+	if slow, ok := e.Get(EffectSlow); ok {
+		return math.Max(0, 1-slow.Magnitude)
+	}
+	return 1
+}
+
+// EffectApplication is an effect waiting to be merged into the target's active effects.
+type EffectApplication struct {
+	Target EntityID
+	Effect Effect
+}
+
+// ApplyEffect queues an effect for the target. It is merged with the active
+// effects according to the stacking rule of its kind on the next effect update.
+func (w *World) ApplyEffect(target EntityID, effect Effect) {
+	w.pendingEffects = append(w.pendingEffects, EffectApplication{Target: target, Effect: effect})
+}
+
+// PendingEffects returns the effects applied since the last ClearPendingEffects.
+func (w *World) PendingEffects() []EffectApplication {
+	return w.pendingEffects
+}
+
+func (w *World) ClearPendingEffects() {
+	w.pendingEffects = w.pendingEffects[:0]
+}
+
+// HasEffect reports whether the entity has an active effect of the kind.
+func (w *World) HasEffect(id EntityID, kind EffectKind) bool {
+	effects, exist := w.Effects.Get(id)
+	return exist && effects.Has(kind)
+}
@@ -19,12 +19,40 @@ const (
 	ComponentVerticalBody
 	ComponentInput
 	ComponentPrePosition
+	ComponentLight
+	ComponentHearing
+	ComponentWeapon
+	ComponentBot
+	ComponentEnemy
+	ComponentStamina
+	ComponentEffects
+	ComponentTeam
+	ComponentInventory
+	ComponentPickup
+	ComponentDestructible
+	ComponentFloor
+	ComponentVelocity
 
 	PlayerMeta = ComponentMeta | ComponentPosition | ComponentDirection | ComponentMovementSpeed |
 		ComponentRotationSpeed | ComponentPlayerHitbox | ComponentHealth |
-		ComponentViewIDs | ComponentInput | ComponentPrePosition
+		ComponentViewIDs | ComponentInput | ComponentPrePosition | ComponentLight | ComponentHearing |
+		ComponentWeapon | ComponentStamina | ComponentEffects | ComponentInventory | ComponentFloor
 
-	WallMeta = ComponentMeta | ComponentPosition | ComponentVerticalBody | ComponentCollider
+	BotMeta = PlayerMeta | ComponentBot
+
+	// EnemyMeta is a hostile NPC. It neither sees, hears nor carries a light,
+	// it just hunts players down with the weapon it was made with.
+	EnemyMeta = (PlayerMeta &^ (ComponentViewIDs | ComponentHearing | ComponentLight | ComponentInventory)) | ComponentEnemy
+
+	WallMeta = ComponentMeta | ComponentPosition | ComponentVerticalBody | ComponentCollider | ComponentFloor
+
+	// DestructibleWallMeta is a wall that is removed once its health runs out.
+	DestructibleWallMeta = WallMeta | ComponentDestructible
+
+	LightMeta = ComponentMeta | ComponentPosition | ComponentDirection | ComponentLight
+
+	// PickupMeta is an item lying on the ground until a player picks it up.
+	PickupMeta = ComponentMeta | ComponentPosition | ComponentPickup | ComponentFloor
 )
 
 const (
@@ -105,6 +133,69 @@ type VerticalBody struct {
 
 type PrePosition Position
 
+// Light is a light source attached to an entity. The light is centered on the
+// entity Position and, when Cone is set, points along the entity Direction.
+type Light struct {
+	Radius    float64
+	Intensity float64
+	Cone      float64 // half-angle in radians, 0 means omnidirectional
+	Enabled   bool
+}
+
+// Weapon holds the stats of the hitscan weapon an entity fires.
+// Cooldown counts down to the next allowed shot.
+type Weapon struct {
+	Damage       int
+	Range        float64
+	FireInterval float64
+	Cooldown     float64
+	// HitEffect is applied to every victim hit, EffectNone for plain damage.
+	HitEffect Effect
+	// Knockback is the impulse pushing a victim hit away from the attacker,
+	// Recoil the one pushing the attacker back on every attack.
+	Knockback float64
+	Recoil    float64
+}
+
+// Team is the side a player fights for in team modes. NoTeam players are on their own.
+type Team uint8
+
+const NoTeam Team = 0
+
+// Stamina limits sprinting. It drains while sprinting and regenerates otherwise.
+// Running out exhausts the entity, forcing it to walk until stamina recovers.
+type Stamina struct {
+	Current   float64
+	Max       float64
+	Exhausted bool
+}
+
+// CanSprint reports whether there is stamina left to sprint on.
+func (s Stamina) CanSprint() bool {
+	return !s.Exhausted && s.Current > 0
+}
+
+type BotState uint8
+
+const (
+	BotPatrol BotState = iota
+	BotChase
+	BotAttack
+)
+
+// Bot is the AI state of a server controlled player.
+type Bot struct {
+	State    BotState
+	Target   EntityID
+	Path     []Position
+	RepathIn float64
+}
+
+// Enemy is the AI state of a hostile NPC hunting the nearest player.
+type Enemy struct {
+	Target EntityID
+}
+
 type MovementType uint8
 
 const (
@@ -118,10 +209,39 @@ type Input struct {
 	LookHorizontal float64
 	MovementType   MovementType
 
+	Sprint bool
+
 	Fire         bool
 	SwitchWeapon bool
 	Reload       bool
 	FastReload   bool
 
+	ToggleFlashlight bool
+
 	Timestamp int64
+	// Sequence numbers the inputs of a client, see World.MovementSnapshot.
+	Sequence uint32
+	// ViewTick is the tick the client saw when giving the input, shots are
+	// resolved against the player hitboxes as of it. 0 resolves them live.
+	ViewTick uint64
+}
+
+// withTriggersOf adds the one-shot actions of another input to the input.
+func (i Input) withTriggersOf(other Input) Input {
+	i.Fire = i.Fire || other.Fire
+	i.SwitchWeapon = i.SwitchWeapon || other.SwitchWeapon
+	i.Reload = i.Reload || other.Reload
+	i.FastReload = i.FastReload || other.FastReload
+	i.ToggleFlashlight = i.ToggleFlashlight || other.ToggleFlashlight
+	return i
+}
+
+// ClearTriggers resets the one-shot actions, which only last for the tick they arrived in.
+func (i Input) ClearTriggers() Input {
+	i.Fire = false
+	i.SwitchWeapon = false
+	i.Reload = false
+	i.FastReload = false
+	i.ToggleFlashlight = false
+	return i
 }
@@ -0,0 +1,27 @@
+package state
+
+import "testing"
+
+func TestEntityManager_FreeReusesIndex(t *testing.T) {
+	em := NewEntityManager()
+
+	e, ok := em.Alloc()
+	if !ok {
+		t.Fatal("Alloc should succeed")
+	}
+	if !em.Free(e) {
+		t.Fatal("Free should succeed for a live entity")
+	}
+	if em.IsAlive(e) {
+		t.Error("freed entity should not be alive")
+	}
+	if em.Free(e) {
+		t.Error("Free should fail for a freed entity")
+	}
+
+	reused, _ := em.Alloc()
+	if reused.Index() != e.Index() || reused.Version() != e.Version()+1 {
+		t.Errorf("expected index %d with version %d, got index %d with version %d",
+			e.Index(), e.Version()+1, reused.Index(), reused.Version())
+	}
+}
@@ -0,0 +1,112 @@
+package state
+
+// Floor is the storey an entity is on. Every floor has its own grid, so
+// entities only collide with, see and shoot at what is on their floor.
+// Entities without a Floor component are on the ground floor.
+type Floor int
+
+const GroundFloor Floor = 0
+
+type StairKind uint8
+
+const (
+	// StairsKind is walked up or down without stopping.
+	StairsKind StairKind = iota
+	// LadderKind holds the climber in place for a moment.
+	LadderKind
+)
+
+func (k StairKind) String() string {
+	if k == LadderKind {
+		return "ladder"
+	}
+	return "stairs"
+}
+
+// Stair is an area connecting two floors. Walking into it on one of them
+// moves the entity to the other one.
+type Stair struct {
+	Kind   StairKind
+	Bounds Bounds
+	From   Floor
+	To     Floor
+}
+
+// Contains reports whether a position is inside the stair area.
+func (s Stair) Contains(pos Position) bool {
+	return pos.X >= s.Bounds.MinX && pos.X <= s.Bounds.MaxX && pos.Y >= s.Bounds.MinY && pos.Y <= s.Bounds.MaxY
+}
+
+// Destination returns the floor the stair leads to from floor, false when it
+// does not reach floor.
+func (s Stair) Destination(floor Floor) (Floor, bool) {
+	switch floor {
+	case s.From:
+		return s.To, true
+	case s.To:
+		return s.From, true
+	default:
+		return 0, false
+	}
+}
+
+// FloorChange records an entity moving to another floor during an update,
+// by taking a stair or respawning.
+type FloorChange struct {
+	ID   EntityID
+	From Floor
+	To   Floor
+}
+
+// AddFloor adds a floor above the others with a grid the size of the ground
+// floor one and returns it.
+func (w *World) AddFloor() Floor {
+	width, height := w.Grid.Size()
+	w.floors = append(w.floors, *NewGrid(w.Grid.CellSize(), width, height))
+	return Floor(len(w.floors))
+}
+
+// FloorCount returns the number of floors, the ground floor included.
+func (w *World) FloorCount() int {
+	return len(w.floors) + 1
+}
+
+// FloorGrid returns the grid of the static colliders on a floor, nil for a
+// floor the map does not have.
+func (w *World) FloorGrid(floor Floor) *Grid {
+	switch {
+	case floor == GroundFloor:
+		return &w.Grid
+	case floor > 0 && int(floor) <= len(w.floors):
+		return &w.floors[floor-1]
+	default:
+		return nil
+	}
+}
+
+// FloorOf returns the floor an entity is on.
+func (w *World) FloorOf(id EntityID) Floor {
+	floor, _ := w.Floor.Get(id)
+	return floor
+}
+
+// SameFloor reports whether both entities are on the same floor.
+func (w *World) SameFloor(a, b EntityID) bool {
+	return w.FloorOf(a) == w.FloorOf(b)
+}
+
+// RecordFloorChange records an entity moving to another floor, the change
+// itself is queued with UpdatePlayer.
+func (w *World) RecordFloorChange(change FloorChange) {
+	w.floorChanges = append(w.floorChanges, change)
+}
+
+// FloorChanges returns the floor changes recorded during the current update.
+func (w *World) FloorChanges() []FloorChange {
+	return w.floorChanges
+}
+
+// ClearFloorChanges drops the recorded changes, should be called at the start of an update.
+func (w *World) ClearFloorChanges() {
+	w.floorChanges = w.floorChanges[:0]
+}
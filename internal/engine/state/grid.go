@@ -56,7 +56,8 @@ func (g *Grid) Add(id EntityID, bounds Bounds, layer LayerMask) []int {
 	return indexes
 }
 
-func (g *Grid) Remove(indexes []uint64, id EntityID) {
+// Remove removes an entity from the given grid cell indexes, as returned by Add.
+func (g *Grid) Remove(indexes []int, id EntityID) {
 	for _, cellIDX := range indexes {
 		cell := &g.cellSlice[cellIDX]
 
@@ -81,6 +82,11 @@ func (g *Grid) AllCells() iter.Seq2[int, *GridCell] {
 	}
 }
 
+// Cell returns the grid cell at the linear index.
+func (g *Grid) Cell(index int) *GridCell {
+	return &g.cellSlice[index]
+}
+
 // CellsInBounds yields all grid cells within the given world-coord bounds.
 func (g *Grid) CellsInBounds(bounds Bounds) iter.Seq2[int, *GridCell] {
 	minGX, minGY := g.GridCoord(bounds.MinX, bounds.MinY)
@@ -100,6 +106,15 @@ func (g *Grid) CellsInBounds(bounds Bounds) iter.Seq2[int, *GridCell] {
 	}
 }
 
+func (g *Grid) CellSize() float64 {
+	return g.cellSize
+}
+
+// Size returns the number of cells along each axis.
+func (g *Grid) Size() (int, int) {
+	return g.width, g.height
+}
+
 // GridCoord converts world coordinates to grid coordinates.
 // float(-0.5) floored is -1, so this works correctly for negative coordinates as well.
 func (g *Grid) GridCoord(x, y float64) (int, int) {
@@ -0,0 +1,63 @@
+package state
+
+import "testing"
+
+func TestSetInput_OneInputPerTick(t *testing.T) {
+	w := NewWorld(10, 10, 10)
+	id := EntityID(1)
+
+	w.SetInput(id, Input{MoveVertical: 1, Fire: true, Sequence: 1})
+	w.SetInput(id, Input{MoveHorizontal: 1, Sequence: 2})
+
+	w.SyncInputBuffer()
+	if input, _ := w.Input.Get(id); input.Sequence != 1 || !input.Fire {
+		t.Fatalf("Expected the first input applied first, got %+v", input)
+	}
+	w.SyncInputBuffer()
+	if input, _ := w.Input.Get(id); input.Sequence != 2 || input.Fire {
+		t.Fatalf("Expected the second input on the next tick, got %+v", input)
+	}
+
+	// nothing queued, the input is held without its one-shot actions
+	w.SetInput(id, Input{MoveHorizontal: 1, Reload: true, Sequence: 3})
+	w.SyncInputBuffer()
+	w.SyncInputBuffer()
+	if input, _ := w.Input.Get(id); input.Sequence != 3 || input.MoveHorizontal != 1 || input.Reload {
+		t.Errorf("Expected the last input held with its triggers cleared, got %+v", input)
+	}
+}
+
+func TestSetInput_DropsDuplicateAndStaleInputs(t *testing.T) {
+	w := NewWorld(10, 10, 10)
+	id := EntityID(1)
+
+	w.SetInput(id, Input{Sequence: 4})
+	w.SetInput(id, Input{Sequence: 4, Fire: true})
+	w.SetInput(id, Input{Sequence: 2, Fire: true})
+	w.SetInput(id, Input{Sequence: 5})
+
+	w.SyncInputBuffer()
+	w.SyncInputBuffer()
+	w.SyncInputBuffer()
+	if input, _ := w.Input.Get(id); input.Sequence != 5 || input.Fire {
+		t.Errorf("Expected only inputs 4 and 5 applied, got %+v", input)
+	}
+	if queue := w.inputQueues[id]; len(queue.inputs) != 0 {
+		t.Errorf("Expected the queue drained, got %+v", queue.inputs)
+	}
+}
+
+func TestSetInput_FullQueueDropsOldestKeepingTriggers(t *testing.T) {
+	w := NewWorld(10, 10, 10)
+	id := EntityID(1)
+
+	w.SetInput(id, Input{Fire: true, Sequence: 1})
+	for i := range MaxQueuedInputs {
+		w.SetInput(id, Input{Sequence: uint32(i + 2)})
+	}
+
+	w.SyncInputBuffer()
+	if input, _ := w.Input.Get(id); input.Sequence != 2 || !input.Fire {
+		t.Errorf("Expected the oldest input dropped and its shot kept, got %+v", input)
+	}
+}
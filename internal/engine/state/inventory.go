@@ -0,0 +1,62 @@
+package state
+
+import (
+	"cmp"
+	"slices"
+
+	"survival/internal/engine/weapons"
+)
+
+// Inventory is what a player carries. The stored value shares its slices, so
+// it has to be cloned before being changed and written back with UpdatePlayer.
+type Inventory = weapons.Inventory
+
+// Pickup is an item lying on the ground at the entity Position.
+type Pickup struct {
+	Item weapons.Item
+}
+
+// GroundItem is a pickup together with where it lies.
+type GroundItem struct {
+	ID       EntityID
+	Item     weapons.Item
+	Position Position
+}
+
+// CreatePickup allocates an item entity lying at pos on floor.
+// This bypasses CommandBuffer for immediate effect since EntityID must be returned synchronously.
+func (w *World) CreatePickup(pos Position, floor Floor, item weapons.Item) (EntityID, bool) {
+	id, ok := w.Entity.Alloc()
+	if !ok {
+		return 0, false
+	}
+
+	w.buf.Push(WorldCommand{
+		EntityID:   id,
+		UpdateMeta: PickupMeta,
+		Meta:       PickupMeta,
+		Position:   pos,
+		Pickup:     Pickup{Item: item},
+		Floor:      floor,
+	})
+	return id, true
+}
+
+// PickupsNear returns the items lying within radius of pos on floor, ordered by ID.
+func (w *World) PickupsNear(pos Position, floor Floor, radius float64) []GroundItem {
+	var items []GroundItem
+	for id, meta := range w.EntityMeta.All() {
+		if !meta.Has(PickupMeta) || w.FloorOf(id) != floor {
+			continue
+		}
+		itemPos, _ := w.Position.Get(id)
+		dx, dy := itemPos.X-pos.X, itemPos.Y-pos.Y
+		if dx*dx+dy*dy > radius*radius {
+			continue
+		}
+		pickup, _ := w.Pickup.Get(id)
+		items = append(items, GroundItem{ID: id, Item: pickup.Item, Position: itemPos})
+	}
+	slices.SortFunc(items, func(a, b GroundItem) int { return cmp.Compare(a.ID, b.ID) })
+	return items
+}
@@ -0,0 +1,101 @@
+package state
+
+import "slices"
+
+// LightMap stores the computed light level of every grid cell.
+// Levels share the linear index layout of Grid, 0 is pitch dark and 1 is fully lit.
+type LightMap struct {
+	cellSize      float64
+	width, height int
+	levels        []float64
+	quantized     []uint8
+	version       uint64
+}
+
+func NewLightMap(cellSize float64, width, height int) *LightMap {
+	levels := make([]float64, width*height)
+	quantized := make([]uint8, width*height)
+	for i := range levels {
+		levels[i] = 1
+		quantized[i] = 255
+	}
+
+	return &LightMap{
+		cellSize:  cellSize,
+		width:     width,
+		height:    height,
+		levels:    levels,
+		quantized: quantized,
+	}
+}
+
+// Level returns the light level of the cell at the given linear index.
+// Out of range indexes are treated as fully lit.
+func (lm *LightMap) Level(index int) float64 {
+	if index < 0 || index >= len(lm.levels) {
+		return 1
+	}
+	return lm.levels[index]
+}
+
+// LevelAt returns the light level of the cell containing the world coordinate.
+func (lm *LightMap) LevelAt(x, y float64) float64 {
+	gx := int(x / lm.cellSize)
+	gy := int(y / lm.cellSize)
+	if x < 0 || y < 0 || gx >= lm.width || gy >= lm.height {
+		return 1
+	}
+	return lm.levels[gy*lm.width+gx]
+}
+
+// SetLevels replaces all cell levels. The version is only bumped when the
+// quantized levels change, so small fluctuations do not cause a resend.
+func (lm *LightMap) SetLevels(levels []float64) {
+	copy(lm.levels, levels)
+
+	changed := false
+	for i, level := range lm.levels {
+		q := uint8(clamp01(level)*255 + 0.5)
+		if lm.quantized[i] != q {
+			lm.quantized[i] = q
+			changed = true
+		}
+	}
+
+	if changed {
+		lm.version++
+	}
+}
+
+// Version increases each time the quantized light levels change.
+func (lm *LightMap) Version() uint64 {
+	return lm.version
+}
+
+func (lm *LightMap) Snapshot() LightMapSnapshot {
+	return LightMapSnapshot{
+		CellSize: lm.cellSize,
+		Width:    lm.width,
+		Height:   lm.height,
+		Levels:   slices.Clone(lm.quantized),
+		Version:  lm.version,
+	}
+}
+
+type LightMapSnapshot struct {
+	CellSize float64
+	Width    int
+	Height   int
+	Levels   []uint8
+	Version  uint64
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,58 @@
+package state
+
+type NoiseKind uint8
+
+const (
+	NoiseNone NoiseKind = iota
+	NoiseFootstep
+	NoiseGunshot
+	NoiseDoor
+)
+
+func (k NoiseKind) String() string {
+	switch k {
+	case NoiseFootstep:
+		return "footstep"
+	case NoiseGunshot:
+		return "gunshot"
+	case NoiseDoor:
+		return "door"
+	default:
+		return "none"
+	}
+}
+
+// NoiseEvent is a sound emitted during the current tick.
+// Loudness is the distance in world units the sound carries in open space.
+type NoiseEvent struct {
+	Source   EntityID
+	Kind     NoiseKind
+	Position Position
+	Loudness float64
+}
+
+// HeardSound is a noise as perceived by a listener.
+// Direction is the approximate world angle from the listener towards the sound,
+// and Loudness is the perceived strength in (0, 1].
+type HeardSound struct {
+	Kind      NoiseKind
+	Direction Direction
+	Loudness  float64
+}
+
+type HeardSounds []HeardSound
+
+// EmitNoise queues a noise event to be propagated at the end of the current tick.
+func (w *World) EmitNoise(event NoiseEvent) {
+	w.noises = append(w.noises, event)
+}
+
+// Noises returns the noise events emitted during the current tick.
+func (w *World) Noises() []NoiseEvent {
+	return w.noises
+}
+
+// ClearNoises drops all queued noise events, should be called once they were propagated.
+func (w *World) ClearNoises() {
+	w.noises = w.noises[:0]
+}
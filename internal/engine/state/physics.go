@@ -0,0 +1,61 @@
+package state
+
+import "survival/internal/engine/vector"
+
+// Velocity moves an entity with inertia, in units per second. Input
+// accelerates it towards the movement speed by Acceleration, without input
+// Friction slows it down to a stop, both in units per second squared.
+// Impulses add to Linear directly and may outrun the movement speed until
+// friction brings it back, MaxSpeed caps the speed reached either way.
+type Velocity struct {
+	Linear       vector.Vector2D
+	Acceleration float64
+	Friction     float64
+	MaxSpeed     float64
+}
+
+// ApplyImpulse queues an instant change of the entity velocity, such as a
+// knockback or recoil. It is added to the velocity with the other commands,
+// entities without a Velocity component ignore it.
+func (w *World) ApplyImpulse(id EntityID, impulse vector.Vector2D) {
+	if impulse == (vector.Vector2D{}) {
+		return
+	}
+	w.buf.Push(WorldCommand{EntityID: id, Impulse: impulse})
+}
+
+// MovementSnapshot is how a player moves as of the last tick. LastInput is
+// the sequence of the input applied last, Speed the movement speed with the
+// effects applied.
+type MovementSnapshot struct {
+	LastInput     uint32
+	Velocity      Velocity
+	Speed         MovementSpeed
+	RotationSpeed RotationSpeed
+	Radius        float64
+	CanSprint     bool
+	Stunned       bool
+}
+
+func (w *World) MovementSnapshot(id EntityID) (MovementSnapshot, bool) {
+	moveSpeed, moveSpeedExist := w.MovementSpeed.Get(id)
+	rotSpeed, rotSpeedExist := w.RotationSpeed.Get(id)
+	hitbox, hitboxExist := w.PlayerHitbox.Get(id)
+	if !w.Entity.IsAlive(id) || !moveSpeedExist || !rotSpeedExist || !hitboxExist {
+		return MovementSnapshot{}, false
+	}
+
+	input, _ := w.Input.Get(id)
+	velocity, _ := w.Velocity.Get(id)
+	effects, _ := w.Effects.Get(id)
+	stamina, _ := w.Stamina.Get(id)
+	return MovementSnapshot{
+		LastInput:     input.Sequence,
+		Velocity:      velocity,
+		Speed:         moveSpeed * MovementSpeed(effects.MovementFactor()),
+		RotationSpeed: rotSpeed,
+		Radius:        hitbox.Radius,
+		CanSprint:     stamina.CanSprint(),
+		Stunned:       effects.Has(EffectStun),
+	}, true
+}
@@ -0,0 +1,45 @@
+package state
+
+// HitboxHistorySize is the number of ticks of player hitboxes kept to rewind
+// shots to, about half a second.
+const HitboxHistorySize = 32
+
+// hitboxFrame holds the player hitboxes as of the end of a tick.
+type hitboxFrame struct {
+	tick     uint64
+	hitboxes map[EntityID]PlayerHitbox
+}
+
+// Tick returns the number of the last recorded tick, 0 before the first.
+func (w *World) Tick() uint64 {
+	return w.tick
+}
+
+// RecordHitboxes ends the tick and keeps the player hitboxes as of it, so
+// shots can later be resolved against where players were then. Should be
+// called at the end of each simulation tick.
+func (w *World) RecordHitboxes() {
+	w.tick++
+	frame := &w.hitboxHistory[w.tick%HitboxHistorySize]
+	if frame.hitboxes == nil {
+		frame.hitboxes = make(map[EntityID]PlayerHitbox)
+	}
+	clear(frame.hitboxes)
+	frame.tick = w.tick
+	for id, hitbox := range w.PlayerHitbox.All() {
+		// hitscan aims at the position, the hitbox center may lag behind
+		if pos, exist := w.Position.Get(id); exist {
+			frame.hitboxes[id] = PlayerHitbox{Center: pos, Radius: hitbox.Radius}
+		}
+	}
+}
+
+// HitboxesAt returns the player hitboxes as of the end of a past tick, false
+// once the tick left the history. The map must not be modified.
+func (w *World) HitboxesAt(tick uint64) (map[EntityID]PlayerHitbox, bool) {
+	frame := w.hitboxHistory[tick%HitboxHistorySize]
+	if frame.tick != tick || frame.hitboxes == nil {
+		return nil, false
+	}
+	return frame.hitboxes, true
+}
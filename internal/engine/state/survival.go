@@ -0,0 +1,41 @@
+package state
+
+type SurvivalPhase uint8
+
+const (
+	// SurvivalWaiting means no player is in the game yet.
+	SurvivalWaiting SurvivalPhase = iota
+	// SurvivalCountdown is the break before the next wave spawns.
+	SurvivalCountdown
+	// SurvivalWave means enemies of the current wave are still alive.
+	SurvivalWave
+	// SurvivalWon means the players survived every wave.
+	SurvivalWon
+	// SurvivalLost means every player died.
+	SurvivalLost
+)
+
+func (p SurvivalPhase) String() string {
+	switch p {
+	case SurvivalCountdown:
+		return "countdown"
+	case SurvivalWave:
+		return "wave"
+	case SurvivalWon:
+		return "won"
+	case SurvivalLost:
+		return "lost"
+	default:
+		return "waiting"
+	}
+}
+
+// SurvivalState is the progress of a survival game.
+// Countdown is the time left in the current phase, 0 while a wave is fought.
+type SurvivalState struct {
+	Phase            SurvivalPhase
+	Wave             int
+	TotalWaves       int
+	Countdown        float64
+	EnemiesRemaining int
+}
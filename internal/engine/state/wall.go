@@ -0,0 +1,68 @@
+package state
+
+// Destructible is a wall that breaks once its health runs out.
+type Destructible struct {
+	Health    Health
+	MaxHealth Health
+}
+
+// StaticChange is a static collider added to or removed from the map during
+// an update, with the area it covers.
+type StaticChange struct {
+	ID      EntityID
+	Removed bool
+	Bounds  Bounds
+	Floor   Floor
+}
+
+// AddStatic puts a static collider on the map, entering it into the grid of
+// its floor right away. Should be called once its Collider and Floor
+// components are set.
+func (w *World) AddStatic(id EntityID) {
+	collider, exist := w.Collider.Get(id)
+	floor := w.FloorOf(id)
+	grid := w.FloorGrid(floor)
+	if !exist || grid == nil {
+		return
+	}
+
+	bounds := colliderBounds(collider)
+	grid.Add(id, bounds, LayerStatic)
+	w.staticChanges = append(w.staticChanges, StaticChange{ID: id, Bounds: bounds, Floor: floor})
+}
+
+// RemoveStatic takes a static collider off the map. It leaves the grid right
+// away, so nothing collides with it for the rest of the update, and the
+// entity is removed with the other commands.
+func (w *World) RemoveStatic(id EntityID) {
+	collider, exist := w.Collider.Get(id)
+	floor := w.FloorOf(id)
+	grid := w.FloorGrid(floor)
+	if !exist || grid == nil {
+		return
+	}
+
+	bounds := colliderBounds(collider)
+	var cells []int
+	for index := range grid.CellsInBounds(bounds) {
+		cells = append(cells, index)
+	}
+	grid.Remove(cells, id)
+	w.QueueDestroy(id)
+	w.staticChanges = append(w.staticChanges, StaticChange{ID: id, Removed: true, Bounds: bounds, Floor: floor})
+}
+
+// StaticChanges returns the static colliders added or removed during the current update.
+func (w *World) StaticChanges() []StaticChange {
+	return w.staticChanges
+}
+
+// ClearStaticChanges drops the recorded changes, should be called at the start of an update.
+func (w *World) ClearStaticChanges() {
+	w.staticChanges = w.staticChanges[:0]
+}
+
+func colliderBounds(collider Collider) Bounds {
+	min, max := collider.BoundingBox()
+	return Bounds{MinX: min.X, MinY: min.Y, MaxX: max.X, MaxY: max.Y}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"sync"
+
+	"survival/internal/engine/vector"
 )
 
 type World struct {
@@ -24,11 +26,52 @@ type World struct {
 
 	VerticalBody ComponentManager[VerticalBody]
 
-	Input          ComponentManager[Input]
-	inputMapBuffer map[EntityID]Input
-	inputMutex     *sync.Mutex
+	Light   ComponentManager[Light]
+	Hearing ComponentManager[HeardSounds]
+
+	Weapon ComponentManager[Weapon]
+	Bot    ComponentManager[Bot]
+	Enemy  ComponentManager[Enemy]
+
+	Stamina ComponentManager[Stamina]
+	Effects ComponentManager[Effects]
+	Team    ComponentManager[Team]
+
+	Inventory ComponentManager[Inventory]
+	Pickup    ComponentManager[Pickup]
+
+	Destructible ComponentManager[Destructible]
+	Floor        ComponentManager[Floor]
+	Velocity     ComponentManager[Velocity]
+
+	Input       ComponentManager[Input]
+	inputQueues map[EntityID]*inputQueue
+	inputMutex  *sync.Mutex
+
+	// Grid holds the static colliders of the ground floor, the floors above
+	// have their own, see FloorGrid.
+	Grid   Grid
+	floors []Grid
+	// Stairs connect the floors.
+	Stairs []Stair
 
-	Grid Grid
+	LightMap     LightMap
+	AmbientLight float64
+
+	// Survival is only advanced in survival mode.
+	Survival SurvivalState
+	// Zone is only advanced in games with a safe zone.
+	Zone ZoneState
+
+	noises         []NoiseEvent
+	combatEvents   []CombatEvent
+	damage         []Damage
+	pendingEffects []EffectApplication
+	staticChanges  []StaticChange
+	floorChanges   []FloorChange
+
+	tick          uint64
+	hitboxHistory [HitboxHistorySize]hitboxFrame
 
 	buf *CommandBuffer
 
@@ -37,25 +80,40 @@ type World struct {
 
 func NewWorld(gridCellSize float64, gridWidth, gridHeight int) *World {
 	return &World{
-		Entity:         NewEntityManager(),
-		EntityMeta:     *NewComponentManager[Meta](), // TODO: refactor this, use pointer or not
-		Position:       *NewComponentManager[Position](),
-		PrePosition:    *NewComponentManager[PrePosition](),
-		Direction:      *NewComponentManager[Direction](),
-		MovementSpeed:  *NewComponentManager[MovementSpeed](),
-		RotationSpeed:  *NewComponentManager[RotationSpeed](),
-		ViewIDs:        *NewComponentManager[ViewIDs](),
-		PlayerHitbox:   *NewComponentManager[PlayerHitbox](),
-		Health:         *NewComponentManager[Health](),
-		Collider:       *NewComponentManager[Collider](),
-		VerticalBody:   *NewComponentManager[VerticalBody](),
-		Input:          *NewComponentManager[Input](),
-		inputMapBuffer: make(map[EntityID]Input),
-		inputMutex:     &sync.Mutex{},
-		Grid:           *NewGrid(gridCellSize, gridWidth, gridHeight),
-		buf:            NewCommandBuffer(),
-		Width:          0,
-		Height:         0,
+		Entity:        NewEntityManager(),
+		EntityMeta:    *NewComponentManager[Meta](), // TODO: refactor this, use pointer or not
+		Position:      *NewComponentManager[Position](),
+		PrePosition:   *NewComponentManager[PrePosition](),
+		Direction:     *NewComponentManager[Direction](),
+		MovementSpeed: *NewComponentManager[MovementSpeed](),
+		RotationSpeed: *NewComponentManager[RotationSpeed](),
+		ViewIDs:       *NewComponentManager[ViewIDs](),
+		PlayerHitbox:  *NewComponentManager[PlayerHitbox](),
+		Health:        *NewComponentManager[Health](),
+		Collider:      *NewComponentManager[Collider](),
+		VerticalBody:  *NewComponentManager[VerticalBody](),
+		Light:         *NewComponentManager[Light](),
+		Hearing:       *NewComponentManager[HeardSounds](),
+		Weapon:        *NewComponentManager[Weapon](),
+		Bot:           *NewComponentManager[Bot](),
+		Enemy:         *NewComponentManager[Enemy](),
+		Stamina:       *NewComponentManager[Stamina](),
+		Effects:       *NewComponentManager[Effects](),
+		Team:          *NewComponentManager[Team](),
+		Inventory:     *NewComponentManager[Inventory](),
+		Pickup:        *NewComponentManager[Pickup](),
+		Destructible:  *NewComponentManager[Destructible](),
+		Floor:         *NewComponentManager[Floor](),
+		Velocity:      *NewComponentManager[Velocity](),
+		Input:         *NewComponentManager[Input](),
+		inputQueues:   make(map[EntityID]*inputQueue),
+		inputMutex:    &sync.Mutex{},
+		Grid:          *NewGrid(gridCellSize, gridWidth, gridHeight),
+		LightMap:      *NewLightMap(gridCellSize, gridWidth, gridHeight),
+		AmbientLight:  1,
+		buf:           NewCommandBuffer(),
+		Width:         0,
+		Height:        0,
 	}
 }
 
@@ -68,13 +126,70 @@ func (w *World) CreateEntity() (EntityID, bool) {
 // DestroyEntity removes an entity and all its associated components.
 // Should not be called directly, use command buffer function instead.
 func (w *World) DestroyEntity(e EntityID) bool {
-	// todo: lock up meta components and remove all components associated with entity e
+	if !w.Entity.IsAlive(e) {
+		return false
+	}
+
+	w.EntityMeta.Remove(e)
+	w.Position.Remove(e)
+	w.PrePosition.Remove(e)
+	w.Direction.Remove(e)
+	w.MovementSpeed.Remove(e)
+	w.RotationSpeed.Remove(e)
+	w.ViewIDs.Remove(e)
+	w.PlayerHitbox.Remove(e)
+	w.Health.Remove(e)
+	w.Collider.Remove(e)
+	w.VerticalBody.Remove(e)
+	w.Light.Remove(e)
+	w.Hearing.Remove(e)
+	w.Weapon.Remove(e)
+	w.Bot.Remove(e)
+	w.Enemy.Remove(e)
+	w.Stamina.Remove(e)
+	w.Effects.Remove(e)
+	w.Team.Remove(e)
+	w.Inventory.Remove(e)
+	w.Pickup.Remove(e)
+	w.Destructible.Remove(e)
+	w.Floor.Remove(e)
+	w.Velocity.Remove(e)
+	w.Input.Remove(e)
+
+	w.inputMutex.Lock()
+	delete(w.inputQueues, e)
+	w.inputMutex.Unlock()
+
 	return w.Entity.Free(e)
 }
 
+// QueueDestroy queues the removal of an entity, applied with the other commands.
+func (w *World) QueueDestroy(e EntityID) {
+	w.buf.Push(WorldCommand{EntityID: e, Destroy: true})
+}
+
+// Teammates reports whether both entities are on the same team. Players without a team have no teammates.
+func (w *World) Teammates(a, b EntityID) bool {
+	teamA, _ := w.Team.Get(a)
+	teamB, _ := w.Team.Get(b)
+	return teamA != NoTeam && teamA == teamB
+}
+
+// IsDead reports whether the entity has a Health component that dropped to zero.
+func (w *World) IsDead(e EntityID) bool {
+	health, exist := w.Health.Get(e)
+	return exist && health <= 0
+}
+
 // CreatePlayer allocates a player entity and adds all player components.
 // This bypasses CommandBuffer for immediate effect since EntityID must be returned synchronously.
 func (w *World) CreatePlayer(cfg CreatePlayer) (EntityID, bool) {
+	return w.createPlayer(cfg, PlayerMeta)
+}
+
+// createPlayer allocates an entity with the player body described by cfg and
+// adds the components of base, so NPCs don't get the ones they never use.
+func (w *World) createPlayer(cfg CreatePlayer, base Meta) (EntityID, bool) {
 	id, ok := w.Entity.Alloc()
 	if !ok {
 		return 0, false
@@ -85,14 +200,21 @@ func (w *World) CreatePlayer(cfg CreatePlayer) (EntityID, bool) {
 	w.UpdatePlayer(
 		id,
 		UpdatePlayer{
-			UpdateMeta:    PlayerMeta,
+			UpdateMeta:    cfg.meta(base),
 			Position:      cfg.Position,
+			PrePosition:   PrePosition(cfg.Position),
 			Direction:     cfg.Direction,
 			MovementSpeed: cfg.MovementSpeed,
 			RotationSpeed: cfg.RotationSpeed,
-			Meta:          PlayerMeta,
+			Meta:          cfg.meta(base),
 			PlayerHitbox:  PlayerHitbox{cfg.Position, cfg.Radius},
 			Health:        cfg.Health,
+			Light:         cfg.Flashlight,
+			Weapon:        cfg.Weapon,
+			Stamina:       Stamina{Current: cfg.Stamina, Max: cfg.Stamina},
+			Inventory:     cfg.Inventory,
+			Floor:         cfg.Floor,
+			Velocity:      cfg.Velocity,
 		},
 	)
 
@@ -106,6 +228,35 @@ type CreatePlayer struct {
 	RotationSpeed RotationSpeed
 	Radius        float64
 	Health        Health
+	Flashlight    Light
+	Weapon        Weapon
+	Stamina       float64 // maximum stamina, players spawn rested
+	Inventory     Inventory
+	Floor         Floor
+	// Velocity gives the player inertia, players without it move instantly.
+	Velocity Velocity
+}
+
+// meta adds the optional components set in cfg to base.
+func (cfg CreatePlayer) meta(base Meta) Meta {
+	if cfg.Velocity != (Velocity{}) {
+		return base | ComponentVelocity
+	}
+	return base
+}
+
+// CreateBot allocates a player entity driven by the server AI. It is put on
+// the ground floor, the only one navigation covers.
+func (w *World) CreateBot(cfg CreatePlayer) (EntityID, bool) {
+	cfg.Floor = GroundFloor
+	return w.createPlayer(cfg, BotMeta)
+}
+
+// CreateEnemy allocates a hostile NPC with the player body described by cfg,
+// on the ground floor like bots. It only gets the components of EnemyMeta.
+func (w *World) CreateEnemy(cfg CreatePlayer) (EntityID, bool) {
+	cfg.Floor = GroundFloor
+	return w.createPlayer(cfg, EnemyMeta)
 }
 
 func (w *World) UpdatePlayer(id EntityID, player UpdatePlayer) {
@@ -121,6 +272,19 @@ func (w *World) UpdatePlayer(id EntityID, player UpdatePlayer) {
 		PlayerShape:   player.PlayerHitbox,
 		Health:        player.Health,
 		PrePosition:   player.PrePosition,
+		ViewIDs:       player.ViewIDs,
+		Light:         player.Light,
+		Hearing:       player.HeardSounds,
+		Weapon:        player.Weapon,
+		Bot:           player.Bot,
+		Enemy:         player.Enemy,
+		Stamina:       player.Stamina,
+		Effects:       player.Effects,
+		Team:          player.Team,
+		Inventory:     player.Inventory,
+		Destructible:  player.Destructible,
+		Floor:         player.Floor,
+		Velocity:      player.Velocity,
 	})
 }
 
@@ -134,6 +298,19 @@ type UpdatePlayer struct {
 	PlayerHitbox
 	Health
 	PrePosition
+	ViewIDs
+	Light
+	HeardSounds
+	Weapon
+	Bot
+	Enemy
+	Stamina
+	Effects
+	Team
+	Inventory
+	Destructible
+	Floor
+	Velocity
 }
 
 func (w *World) ApplyCommands() {
@@ -150,6 +327,11 @@ func (w *World) ApplyCommands() {
 			continue
 		}
 
+		if cmd.Destroy {
+			w.DestroyEntity(entityID)
+			continue
+		}
+
 		log.Printf("Applying command for EntityID %d with UpdateMeta %b", entityID, cmd.UpdateMeta)
 
 		if cmd.UpdateMeta.Has(ComponentMeta) {
@@ -207,6 +389,83 @@ func (w *World) ApplyCommands() {
 				// TODO: log error
 			}
 		}
+		if cmd.UpdateMeta.Has(ComponentViewIDs) {
+			if !w.ViewIDs.Upsert(entityID, cmd.ViewIDs) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentLight) {
+			if !w.Light.Upsert(entityID, cmd.Light) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentHearing) {
+			if !w.Hearing.Upsert(entityID, cmd.Hearing) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentWeapon) {
+			if !w.Weapon.Upsert(entityID, cmd.Weapon) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentBot) {
+			if !w.Bot.Upsert(entityID, cmd.Bot) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentEnemy) {
+			if !w.Enemy.Upsert(entityID, cmd.Enemy) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentStamina) {
+			if !w.Stamina.Upsert(entityID, cmd.Stamina) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentEffects) {
+			if !w.Effects.Upsert(entityID, cmd.Effects) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentTeam) {
+			if !w.Team.Upsert(entityID, cmd.Team) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentInventory) {
+			if !w.Inventory.Upsert(entityID, cmd.Inventory) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentPickup) {
+			if !w.Pickup.Upsert(entityID, cmd.Pickup) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentDestructible) {
+			if !w.Destructible.Upsert(entityID, cmd.Destructible) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentFloor) {
+			if !w.Floor.Upsert(entityID, cmd.Floor) {
+				// TODO: log error
+			}
+		}
+		if cmd.UpdateMeta.Has(ComponentVelocity) {
+			if !w.Velocity.Upsert(entityID, cmd.Velocity) {
+				// TODO: log error
+			}
+		}
+
+		if cmd.Impulse != (vector.Vector2D{}) {
+			if velocity, exist := w.Velocity.Get(entityID); exist {
+				velocity.Linear = velocity.Linear.Add(cmd.Impulse)
+				w.Velocity.Set(entityID, velocity)
+			}
+		}
 	}
 }
 
@@ -230,11 +489,13 @@ func (w *World) PlayerSnapshotWithView(id EntityID) (PlayerSnapshotWithView, boo
 		log.Printf("PlayerSnapshotWithView: failed to get player location for EntityID %d", id)
 		return PlayerSnapshotWithView{}, false
 	}
+	sounds, _ := w.Hearing.Get(id)
+
 	viewIDs, exist := w.ViewIDs.Get(id)
 	if !exist {
 		// TODO: log error
 		log.Printf("PlayerSnapshotWithView: no ViewIDs component for EntityID %d", id)
-		return PlayerSnapshotWithView{Player: player}, true
+		return PlayerSnapshotWithView{Player: player, Sounds: sounds}, true
 	}
 
 	views := make([]PlayerSnapshot, len(viewIDs))
@@ -245,7 +506,7 @@ func (w *World) PlayerSnapshotWithView(id EntityID) (PlayerSnapshotWithView, boo
 			continue
 		}
 	}
-	return PlayerSnapshotWithView{Player: player, Views: views}, true
+	return PlayerSnapshotWithView{Player: player, Views: views, Sounds: sounds}, true
 }
 
 func (w *World) StaticEntities() []StaticEntity {
@@ -254,6 +515,7 @@ func (w *World) StaticEntities() []StaticEntity {
 		entity := StaticEntity{
 			ID:       entityID,
 			Collider: collider,
+			Floor:    w.FloorOf(entityID),
 		}
 		if vertBody, ok := w.VerticalBody.Get(entityID); ok {
 			entity.VerticalBody = vertBody
@@ -297,6 +559,30 @@ func (w *World) playerLocation(id EntityID) (PlayerSnapshot, bool) {
 			// TODO: log error
 		}
 	}
+
+	if meta.Has(ComponentHealth) {
+		if health, exist := w.Health.Get(id); exist {
+			snapshot.Health = health
+		}
+	}
+
+	if meta.Has(ComponentStamina) {
+		if stamina, exist := w.Stamina.Get(id); exist {
+			snapshot.Stamina = stamina
+		}
+	}
+
+	if meta.Has(ComponentEffects) {
+		if effects, exist := w.Effects.Get(id); exist {
+			snapshot.Effects = effects
+		}
+	}
+
+	if meta.Has(ComponentTeam) {
+		snapshot.Team, _ = w.Team.Get(id)
+	}
+
+	snapshot.Enemy = meta.Has(ComponentEnemy)
 	return snapshot, true
 }
 
@@ -304,11 +590,17 @@ type PlayerSnapshot struct {
 	ID        EntityID  `json:"id"`
 	Direction Direction `json:"direction"`
 	Position  Position  `json:"position"`
+	Health    Health    `json:"health"`
+	Enemy     bool      `json:"enemy"`
+	Stamina   Stamina   `json:"stamina"`
+	Effects   Effects   `json:"effects"`
+	Team      Team      `json:"team"`
 }
 
 type PlayerSnapshotWithView struct {
 	Player PlayerSnapshot   `json:"player"`
 	Views  []PlayerSnapshot `json:"views"`
+	Sounds HeardSounds      `json:"sounds"`
 }
 
 type StaticEntity struct {
@@ -316,6 +608,7 @@ type StaticEntity struct {
 	Collider        Collider     `json:"collider"`
 	VerticalBody    VerticalBody `json:"vertical_body"`
 	HasVerticalBody bool         `json:"has_vertical_body"`
+	Floor           Floor        `json:"floor"`
 }
 
 type MapInfo struct {
@@ -323,37 +616,67 @@ type MapInfo struct {
 	Height float64
 }
 
-// SetInput buffers the input for an entity.
-// Inputs are merged if multiple inputs are set before SyncInputBuffer is called.
+// MaxQueuedInputs is the number of inputs an entity can have waiting. Past
+// it the oldest input is dropped, its one-shot actions carried over to the
+// next one, so a client sending too fast does not fall further behind.
+const MaxQueuedInputs = 8
+
+// inputQueue holds the inputs of an entity waiting for their tick. last is
+// the highest sequence queued, inputs up to it are duplicates or arrived too
+// late.
+type inputQueue struct {
+	inputs []Input
+	last   uint32
+}
+
+// SetInput queues the input for an entity, one input is applied per tick.
+// Inputs with a sequence that is not higher than any queued before are
+// dropped, unnumbered inputs are always queued.
 // This method is thread-safe.
 func (w *World) SetInput(entityID EntityID, input Input) {
 	w.inputMutex.Lock()
 	defer w.inputMutex.Unlock()
 
-	old := w.inputMapBuffer[entityID]
-	w.inputMapBuffer[entityID] = Input{
-		MoveVertical:   input.MoveVertical,
-		MoveHorizontal: input.MoveHorizontal,
-		LookHorizontal: input.LookHorizontal,
-		MovementType:   input.MovementType,
-		Fire:           old.Fire || input.Fire,
-		SwitchWeapon:   old.SwitchWeapon || input.SwitchWeapon,
-		Reload:         old.Reload || input.Reload,
-		FastReload:     old.FastReload || input.FastReload,
-		Timestamp:      input.Timestamp,
+	queue, exist := w.inputQueues[entityID]
+	if !exist {
+		queue = &inputQueue{}
+		w.inputQueues[entityID] = queue
+	}
+	if input.Sequence != 0 {
+		if input.Sequence <= queue.last {
+			return
+		}
+		queue.last = input.Sequence
+	}
+
+	queue.inputs = append(queue.inputs, input)
+	if len(queue.inputs) > MaxQueuedInputs {
+		queue.inputs[1] = queue.inputs[1].withTriggersOf(queue.inputs[0])
+		queue.inputs = append(queue.inputs[:0], queue.inputs[1:]...)
 	}
 }
 
-// SyncInputBuffer flushes the input buffer into the main Input component manager.
+// SyncInputBuffer applies the next queued input of every entity to the main
+// Input component manager. Entities without a queued input keep their last
+// one, except for the one-shot actions, which only last for the tick they
+// arrived in.
 // Should be called at the start of each simulation tick.
 func (w *World) SyncInputBuffer() {
 	w.inputMutex.Lock()
 	defer w.inputMutex.Unlock()
 
-	for entityID, input := range w.inputMapBuffer {
-		w.Input.Upsert(entityID, input)
+	for entityID, input := range w.Input.All() {
+		if queue, exist := w.inputQueues[entityID]; exist && len(queue.inputs) > 0 {
+			continue
+		}
+		w.Input.Set(entityID, input.ClearTriggers())
+	}
 
-		// Clear the buffer after syncing
-		delete(w.inputMapBuffer, entityID)
+	for entityID, queue := range w.inputQueues {
+		if len(queue.inputs) == 0 {
+			continue
+		}
+		w.Input.Upsert(entityID, queue.inputs[0])
+		queue.inputs = append(queue.inputs[:0], queue.inputs[1:]...)
 	}
 }
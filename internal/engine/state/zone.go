@@ -0,0 +1,28 @@
+package state
+
+// ZoneState is the safe zone of a battle royale game, a circle players have
+// to stay in. Phase counts the shrink stages from 1 and Damage is what players
+// outside take every second during it. Timer is the time left until the zone
+// starts shrinking towards NextCenter and NextRadius, or while Shrinking until
+// it gets there. After the last stage the zone stays put with Timer 0.
+type ZoneState struct {
+	Active     bool
+	Phase      int
+	Center     Position
+	Radius     float64
+	NextCenter Position
+	NextRadius float64
+	Timer      float64
+	Shrinking  bool
+	Damage     int
+}
+
+// Contains reports whether a position is inside an active zone, everything
+// is inside while no zone is active.
+func (z ZoneState) Contains(pos Position) bool {
+	if !z.Active {
+		return true
+	}
+	dx, dy := pos.X-z.Center.X, pos.Y-z.Center.Y
+	return dx*dx+dy*dy <= z.Radius*z.Radius
+}
@@ -0,0 +1,54 @@
+package system
+
+import (
+	"testing"
+
+	"survival/internal/engine/pathfinding"
+	"survival/internal/engine/state"
+)
+
+// benchWaveEnemies is the size of the last wave of a default survival game.
+const benchWaveEnemies = 11
+
+// setupBenchWorld builds a hall with rows of pillars, like the office map, and
+// two players walking along it.
+func setupBenchWorld() (*state.World, []state.EntityID) {
+	world := state.NewWorld(5, 40, 40)
+	world.Width = 200
+	world.Height = 200
+	for x := 20.0; x < 180; x += 15 {
+		for y := 40.0; y < 180; y += 35 {
+			addWall(world, x, y, 5, 10)
+		}
+	}
+
+	var playerIDs []state.EntityID
+	for _, pos := range []state.Position{{X: 10, Y: 10}, {X: 190, Y: 20}} {
+		playerIDs = append(playerIDs, addPlayer(world, pos))
+	}
+	return world, playerIDs
+}
+
+func BenchmarkEnemySystem_UpdateFullWave(b *testing.B) {
+	world, playerIDs := setupBenchWorld()
+	for i := range benchWaveEnemies {
+		cfg := testWaveConfig.Enemy
+		cfg.Position = state.Position{X: 12 + float64(i)*16, Y: 190}
+		world.CreateEnemy(cfg)
+	}
+	world.ApplyCommands()
+	es := NewEnemySystem(world, pathfinding.NewPathfinder(world))
+
+	b.ResetTimer()
+	for i := range b.N {
+		// the players keep walking so their flow fields go out of date
+		for j, playerID := range playerIDs {
+			x := 10 + float64((i+j*90)%180)
+			world.UpdatePlayer(playerID, state.UpdatePlayer{UpdateMeta: state.ComponentPosition, Position: state.Position{X: x, Y: 10}})
+		}
+		world.ApplyCommands()
+		es.Update(1.0 / 60.0)
+		world.ApplyCommands()
+		world.SyncInputBuffer()
+	}
+}
@@ -0,0 +1,232 @@
+package system
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"survival/internal/engine/pathfinding"
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+const (
+	botRepathInterval = 1.0  // average seconds between path searches of a bot
+	botWaypointReach  = 1.0  // distance at which a waypoint counts as reached
+	botAimTolerance   = 0.01 // radians off target a bot still pulls the trigger
+	botPatrolAttempts = 8    // random cells tried when picking a patrol destination
+	botPatrolSpread   = 40.0 // maximum distance of a patrol destination on each axis
+	// botPathSearches caps the path searches of all bots in one tick, a
+	// search taking milliseconds on large maps
+	botPathSearches = 2
+)
+
+// BotSystem drives server controlled players. Bots patrol to random nearby
+// walkable cells, chase the closest player they can see and shoot once the
+// target is in weapon range. Decisions are turned into state.Input through
+// World.SetInput, so bots move and fire exactly like human players. Path
+// searches are spread over the ticks: each bot searches at a randomized
+// interval, and bots over the budget of a tick wait for the next one.
+type BotSystem struct {
+	world      *state.World
+	pathfinder *pathfinding.Pathfinder
+	rng        *rand.Rand
+	// searches counts the path searches of the current tick
+	searches int
+}
+
+func NewBotSystem(world *state.World, pathfinder *pathfinding.Pathfinder) *BotSystem {
+	return &BotSystem{
+		world:      world,
+		pathfinder: pathfinder,
+		rng:        rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+func (bs *BotSystem) ReadMeta() state.Meta {
+	return state.ComponentBot | state.ComponentPosition | state.ComponentDirection |
+		state.ComponentRotationSpeed | state.ComponentPlayerHitbox | state.ComponentViewIDs | state.ComponentWeapon
+}
+
+func (bs *BotSystem) WriteMeta() state.Meta {
+	return state.ComponentBot | state.ComponentInput
+}
+
+func (bs *BotSystem) Update(dt float64) {
+	world := bs.world
+	requiredMeta := bs.ReadMeta()
+	bs.searches = 0
+
+	for botID, meta := range world.EntityMeta.All() {
+		if !meta.Has(requiredMeta) {
+			continue
+		}
+
+		bot, exist := world.Bot.Get(botID)
+		if !exist {
+			continue
+		}
+
+		if world.IsDead(botID) {
+			world.SetInput(botID, state.Input{})
+			if bot.State != state.BotPatrol || len(bot.Path) > 0 {
+				world.UpdatePlayer(botID, state.UpdatePlayer{
+					UpdateMeta: state.ComponentBot,
+					Bot:        state.Bot{State: state.BotPatrol},
+				})
+			}
+			continue
+		}
+
+		bot, input := bs.think(botID, bot, dt)
+
+		world.SetInput(botID, input)
+		world.UpdatePlayer(botID, state.UpdatePlayer{
+			UpdateMeta: state.ComponentBot,
+			Bot:        bot,
+		})
+	}
+}
+
+// think advances the bot state machine and returns the input for the next tick.
+func (bs *BotSystem) think(botID state.EntityID, bot state.Bot, dt float64) (state.Bot, state.Input) {
+	world := bs.world
+
+	pos, _ := world.Position.Get(botID)
+	dir, _ := world.Direction.Get(botID)
+	rotSpeed, _ := world.RotationSpeed.Get(botID)
+	hitbox, _ := world.PlayerHitbox.Get(botID)
+	weapon, _ := world.Weapon.Get(botID)
+
+	input := state.Input{MovementType: state.MovementTypeAbsolute}
+	bot.RepathIn -= dt
+
+	targetID, targetPos, found := bs.closestVisibleTarget(botID, pos)
+	switch {
+	case found && vector.Vector2D(pos).DistanceTo(vector.Vector2D(targetPos)) <= weapon.Range:
+		bot.State = state.BotAttack
+		bot.Target = targetID
+		bot.Path = nil
+
+		diff := angleTo(dir, pos, targetPos)
+		input.LookHorizontal = turnInput(diff, rotSpeed, dt)
+		// the turn is applied before the shot, so fire once it lands on target this tick
+		input.Fire = math.Abs(diff) <= float64(rotSpeed)*dt+botAimTolerance
+		return bot, input
+
+	case found:
+		if bot.State != state.BotChase || bot.Target != targetID {
+			// the path leads elsewhere, stand until there is one to the target
+			bot.Path = nil
+		}
+		if (bot.RepathIn <= 0 || len(bot.Path) == 0) && bs.reserveSearch() {
+			bot.Path, _ = bs.pathfinder.FindPath(pos, targetPos, hitbox.Radius)
+			bot.RepathIn = bs.repathDelay()
+		}
+		bot.State = state.BotChase
+		bot.Target = targetID
+
+	case len(bot.Path) == 0:
+		// lost sight of the target, or arrived at the patrol point
+		bot.State = state.BotPatrol
+		bot.Target = 0
+		if bot.RepathIn <= 0 && bs.reserveSearch() {
+			bot.Path = bs.patrolPath(pos, hitbox.Radius)
+			bot.RepathIn = bs.repathDelay()
+		}
+	}
+
+	bot.Path = followPath(pos, dir, rotSpeed, bot.Path, dt, &input)
+	return bot, input
+}
+
+// followPath drops the waypoints already reached and steers the input towards
+// the next one, turning to face it. It returns the remaining path.
+func followPath(pos state.Position, dir state.Direction, rotSpeed state.RotationSpeed, path []state.Position, dt float64, input *state.Input) []state.Position {
+	for len(path) > 0 && vector.Vector2D(pos).DistanceTo(vector.Vector2D(path[0])) <= botWaypointReach {
+		path = path[1:]
+	}
+	if len(path) == 0 {
+		return path
+	}
+
+	waypoint := path[0]
+	input.MovementType = state.MovementTypeAbsolute
+	input.MoveHorizontal = waypoint.X - pos.X
+	input.MoveVertical = waypoint.Y - pos.Y
+	input.LookHorizontal = turnInput(angleTo(dir, pos, waypoint), rotSpeed, dt)
+	return path
+}
+
+// closestVisibleTarget returns the nearest living human player of another team in the bot's view.
+func (bs *BotSystem) closestVisibleTarget(botID state.EntityID, pos state.Position) (state.EntityID, state.Position, bool) {
+	world := bs.world
+	viewIDs, _ := world.ViewIDs.Get(botID)
+
+	var (
+		closestID  state.EntityID
+		closestPos state.Position
+		found      bool
+	)
+	closestDist := math.Inf(1)
+
+	for _, targetID := range viewIDs {
+		meta, exist := world.EntityMeta.Get(targetID)
+		if !exist || meta.Has(state.ComponentBot) || world.IsDead(targetID) || world.Teammates(botID, targetID) {
+			continue
+		}
+
+		targetPos, exist := world.Position.Get(targetID)
+		if !exist {
+			continue
+		}
+
+		if dist := vector.Vector2D(pos).DistanceTo(vector.Vector2D(targetPos)); dist < closestDist {
+			closestID, closestPos, closestDist, found = targetID, targetPos, dist, true
+		}
+	}
+	return closestID, closestPos, found
+}
+
+// reserveSearch counts a path search against the budget of the tick, false
+// once it is used up.
+func (bs *BotSystem) reserveSearch() bool {
+	if bs.searches >= botPathSearches {
+		return false
+	}
+	bs.searches++
+	return true
+}
+
+// repathDelay returns the time until the next path search of a bot, varied
+// so that bots spawned together do not keep searching in the same tick.
+func (bs *BotSystem) repathDelay() float64 {
+	return botRepathInterval * (0.5 + bs.rng.Float64())
+}
+
+// patrolPath picks a random walkable position and returns the path to it,
+// nil when it cannot be reached. A single path is searched, the bot tries
+// another destination after its repath delay.
+func (bs *BotSystem) patrolPath(pos state.Position, radius float64) []state.Position {
+	destination, ok := bs.pathfinder.RandomWalkable(pos, botPatrolSpread, radius, botPatrolAttempts, bs.rng)
+	if !ok {
+		return nil
+	}
+	path, _ := bs.pathfinder.FindPath(pos, destination, radius)
+	return path
+}
+
+// angleTo returns the signed rotation from the current direction towards a
+// point, normalized to [-π, π]. Positive values turn clockwise.
+func angleTo(dir state.Direction, from, to state.Position) float64 {
+	target := math.Atan2(to.X-from.X, -(to.Y - from.Y))
+	diff := target - float64(dir)
+	return math.Atan2(math.Sin(diff), math.Cos(diff))
+}
+
+// turnInput returns the LookHorizontal input that turns by diff without overshooting in one tick.
+func turnInput(diff float64, speed state.RotationSpeed, dt float64) float64 {
+	if speed <= 0 || dt <= 0 {
+		return 0
+	}
+	return math.Max(-1, math.Min(1, diff/(float64(speed)*dt)))
+}
@@ -0,0 +1,128 @@
+package system
+
+import (
+	"testing"
+
+	"survival/internal/engine/pathfinding"
+	"survival/internal/engine/state"
+)
+
+func addBot(world *state.World, pos state.Position) state.EntityID {
+	id, _ := world.CreateBot(state.CreatePlayer{
+		Position:      pos,
+		MovementSpeed: 5.0,
+		RotationSpeed: 2.0,
+		Radius:        0.5,
+		Health:        100,
+		Weapon:        testWeapon,
+	})
+	world.ApplyCommands()
+	return id
+}
+
+func updateBots(world *state.World, bs *BotSystem) {
+	updateVisibility(world)
+	bs.Update(1.0 / 60.0)
+	world.ApplyCommands()
+	world.SyncInputBuffer()
+}
+
+func TestBot_PatrolsWithoutTarget(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 5, Y: 5}, 0)
+	addWall(world, 50, 30, 40, 1)
+	botID := addBot(world, state.Position{X: 50, Y: 80})
+	bs := NewBotSystem(world, pathfinding.NewPathfinder(world))
+
+	updateBots(world, bs)
+
+	bot, _ := world.Bot.Get(botID)
+	if bot.State != state.BotPatrol {
+		t.Errorf("Expected patrol, got state %d", bot.State)
+	}
+	if len(bot.Path) == 0 {
+		t.Errorf("Expected a patrol path")
+	}
+}
+
+func TestBot_PathSearchesSpreadOverTicks(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 5, Y: 5}, 0)
+	var botIDs []state.EntityID
+	for i := range 5 {
+		botIDs = append(botIDs, addBot(world, state.Position{X: 20 + float64(i)*15, Y: 80}))
+	}
+	bs := NewBotSystem(world, pathfinding.NewPathfinder(world))
+
+	withPath := func() int {
+		n := 0
+		for _, botID := range botIDs {
+			if bot, _ := world.Bot.Get(botID); len(bot.Path) > 0 {
+				n++
+			}
+		}
+		return n
+	}
+
+	updateBots(world, bs)
+	if n := withPath(); n != botPathSearches {
+		t.Fatalf("Expected %d bots to search a path in the first tick, got %d", botPathSearches, n)
+	}
+	updateBots(world, bs)
+	updateBots(world, bs)
+	if n := withPath(); n != len(botIDs) {
+		t.Errorf("Expected every bot to have a path after three ticks, got %d", n)
+	}
+}
+
+func TestBot_AttacksVisiblePlayerInRange(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 20}, 0)
+	// facing the player straight up
+	botID := addBot(world, state.Position{X: 50, Y: 50})
+	bs := NewBotSystem(world, pathfinding.NewPathfinder(world))
+
+	updateBots(world, bs)
+
+	bot, _ := world.Bot.Get(botID)
+	if bot.State != state.BotAttack || bot.Target != playerID {
+		t.Fatalf("Expected bot to attack player %d, got state %d target %d", playerID, bot.State, bot.Target)
+	}
+	input, _ := world.Input.Get(botID)
+	if !input.Fire {
+		t.Errorf("Expected bot aimed at the player to fire")
+	}
+}
+
+func TestBot_ChasesPlayerOutOfRange(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 10}, 0)
+	botID := addBot(world, state.Position{X: 50, Y: 50})
+	weapon := testWeapon
+	weapon.Range = 20
+	armPlayer(world, botID, weapon)
+	bs := NewBotSystem(world, pathfinding.NewPathfinder(world))
+
+	updateBots(world, bs)
+
+	bot, _ := world.Bot.Get(botID)
+	if bot.State != state.BotChase || bot.Target != playerID {
+		t.Fatalf("Expected bot to chase player %d, got state %d target %d", playerID, bot.State, bot.Target)
+	}
+	input, _ := world.Input.Get(botID)
+	if input.MoveHorizontal >= 0 || input.MoveVertical >= 0 {
+		t.Errorf("Expected bot to move towards the player, got input %+v", input)
+	}
+	if input.Fire {
+		t.Errorf("Bot should not fire out of range")
+	}
+}
+
+func TestBot_IgnoresOtherBots(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 5, Y: 95}, 0)
+	addBot(world, state.Position{X: 50, Y: 20})
+	botID := addBot(world, state.Position{X: 50, Y: 50})
+	bs := NewBotSystem(world, pathfinding.NewPathfinder(world))
+
+	updateBots(world, bs)
+
+	if bot, _ := world.Bot.Get(botID); bot.State == state.BotAttack {
+		t.Errorf("Bot should not attack another bot")
+	}
+}
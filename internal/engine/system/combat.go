@@ -0,0 +1,343 @@
+package system
+
+import (
+	"math"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+// CombatSystem fires hitscan weapons for entities pulling the trigger,
+// applies damage to the first player hitbox in the line of fire on the
+// shooter's floor and records shots, hits and kills as combat events. Shots
+// stop at the first wall in the way, wearing destructible walls down until
+// they are removed from the map. Damage queued on the world, such as bleeding
+// or explosions, is applied here as well. Landed hits knock the victim back
+// and every attack pushes the attacker back with the weapon recoil, each as an
+// impulse on the Velocity of the pushed entity. Invulnerable entities take no
+// damage, and damage between teammates follows the friendly fire rule. Shots
+// of players are resolved against the hitboxes as of the tick the shooter
+// saw, see rewoundHitboxes.
+type CombatSystem struct {
+	world        *state.World
+	friendlyFire FriendlyFire
+}
+
+// FriendlyFire decides what happens to damage dealt to a teammate.
+type FriendlyFire string
+
+// maxRewindTicks caps how far back shots are resolved, 200 ms, so players
+// with a high latency cannot hit others long after they took cover.
+const maxRewindTicks = 12
+
+const (
+	// FriendlyFireOff ignores damage to teammates, shots still stop at them.
+	FriendlyFireOff FriendlyFire = "off"
+	// FriendlyFireOn hurts teammates like anyone else.
+	FriendlyFireOn FriendlyFire = "on"
+	// FriendlyFireReflected turns the damage back on the attacker.
+	FriendlyFireReflected FriendlyFire = "reflected"
+)
+
+func NewCombatSystem(world *state.World) *CombatSystem {
+	return &CombatSystem{world: world, friendlyFire: FriendlyFireOff}
+}
+
+func (cs *CombatSystem) SetFriendlyFire(rule FriendlyFire) {
+	cs.friendlyFire = rule
+}
+
+func (cs *CombatSystem) ReadMeta() state.Meta {
+	return state.ComponentInput | state.ComponentPosition | state.ComponentDirection | state.ComponentWeapon
+}
+
+func (cs *CombatSystem) WriteMeta() state.Meta {
+	return state.ComponentWeapon | state.ComponentHealth | state.ComponentDestructible | state.ComponentVelocity
+}
+
+func (cs *CombatSystem) Update(dt float64) {
+	world := cs.world
+	requiredMeta := cs.ReadMeta()
+
+	// health changes of this tick, so several hits on one victim add up
+	pendingHealth := make(map[state.EntityID]state.Health)
+	pendingWalls := make(map[state.EntityID]state.Health)
+
+	for _, damage := range world.QueuedDamage() {
+		if meta, _ := world.EntityMeta.Get(damage.Victim); meta.Has(state.ComponentDestructible) {
+			cs.damageWall(damage.Victim, damage.Amount, pendingWalls)
+			continue
+		}
+		if cs.damage(damage.Attacker, damage.Victim, damage.Amount, false, pendingHealth) {
+			world.ApplyImpulse(damage.Victim, damage.Impulse)
+		}
+	}
+	world.ClearQueuedDamage()
+
+	for shooterID, meta := range world.EntityMeta.All() {
+		if !meta.Has(requiredMeta) {
+			continue
+		}
+
+		weapon, exist := world.Weapon.Get(shooterID)
+		if !exist {
+			continue
+		}
+		input, _ := world.Input.Get(shooterID)
+
+		cooling := weapon.Cooldown > 0
+		weapon.Cooldown = math.Max(weapon.Cooldown-dt, 0)
+
+		// sprinting keeps the weapon lowered, stuns keep it from being raised
+		canFire := input.Fire && weapon.Cooldown <= 0 && !isSprinting(world, shooterID, input) &&
+			!world.HasEffect(shooterID, state.EffectStun) &&
+			!world.IsDead(shooterID) && !isPendingDead(pendingHealth, shooterID)
+		if canFire {
+			weapon.Cooldown = weapon.FireInterval
+			cs.fire(shooterID, weapon, input.ViewTick, pendingHealth, pendingWalls)
+		}
+
+		if cooling || canFire {
+			world.UpdatePlayer(shooterID, state.UpdatePlayer{
+				UpdateMeta: state.ComponentWeapon,
+				Weapon:     weapon,
+			})
+		}
+	}
+
+	for victimID, health := range pendingHealth {
+		world.UpdatePlayer(victimID, state.UpdatePlayer{
+			UpdateMeta: state.ComponentHealth,
+			Health:     health,
+		})
+	}
+
+	for wallID, health := range pendingWalls {
+		if health <= 0 {
+			world.RemoveStatic(wallID)
+			continue
+		}
+		destructible, _ := world.Destructible.Get(wallID)
+		destructible.Health = health
+		world.UpdatePlayer(wallID, state.UpdatePlayer{
+			UpdateMeta:   state.ComponentDestructible,
+			Destructible: destructible,
+		})
+	}
+}
+
+func (cs *CombatSystem) fire(shooterID state.EntityID, weapon state.Weapon, viewTick uint64, pendingHealth, pendingWalls map[state.EntityID]state.Health) {
+	world := cs.world
+
+	pos, _ := world.Position.Get(shooterID)
+	dir, _ := world.Direction.Get(shooterID)
+
+	world.EmitCombatEvent(state.CombatEvent{Kind: state.CombatShot, Attacker: shooterID})
+	// enemies attack in melee, only guns are heard
+	if meta, _ := world.EntityMeta.Get(shooterID); !meta.Has(state.ComponentEnemy) {
+		world.EmitNoise(state.NoiseEvent{
+			Source:   shooterID,
+			Kind:     state.NoiseGunshot,
+			Position: pos,
+			Loudness: gunshotLoudness,
+		})
+	}
+
+	aim := vector.Vector2D{X: math.Sin(float64(dir)), Y: -math.Cos(float64(dir))}
+	world.ApplyImpulse(shooterID, aim.Scale(-weapon.Recoil))
+
+	hitboxes := rewoundHitboxes(world, viewTick)
+	victimID, wall, hit := hitscan(world, shooterID, vector.Vector2D(pos), float64(dir), weapon.Range, hitboxes, pendingHealth)
+	if !hit {
+		return
+	}
+	if wall {
+		if meta, _ := world.EntityMeta.Get(victimID); meta.Has(state.ComponentDestructible) {
+			cs.damageWall(victimID, weapon.Damage, pendingWalls)
+		}
+		return
+	}
+
+	if !cs.damage(shooterID, victimID, weapon.Damage, true, pendingHealth) {
+		return
+	}
+	world.ApplyImpulse(victimID, aim.Scale(weapon.Knockback))
+	if weapon.HitEffect.Kind != state.EffectNone {
+		effect := weapon.HitEffect
+		effect.Source = shooterID
+		world.ApplyEffect(victimID, effect)
+	}
+}
+
+// damage takes health from a living victim that is not invulnerable and
+// records the hit, and the kill when it dies. shot marks the damage of a fired
+// weapon. It reports whether the damage landed on the victim, reflected
+// friendly fire does not.
+func (cs *CombatSystem) damage(attackerID, victimID state.EntityID, amount int, shot bool, pendingHealth map[state.EntityID]state.Health) bool {
+	world := cs.world
+	if attackerID != victimID && world.Teammates(attackerID, victimID) {
+		switch cs.friendlyFire {
+		case FriendlyFireOn:
+		case FriendlyFireReflected:
+			cs.damage(attackerID, attackerID, amount, false, pendingHealth)
+			return false
+		default:
+			return false
+		}
+	}
+
+	if world.IsDead(victimID) || isPendingDead(pendingHealth, victimID) || world.HasEffect(victimID, state.EffectInvulnerable) {
+		return false
+	}
+
+	health, exist := pendingHealth[victimID]
+	if !exist {
+		if health, exist = world.Health.Get(victimID); !exist {
+			return false
+		}
+	}
+
+	damage := min(amount, int(health))
+	health -= state.Health(damage)
+	pendingHealth[victimID] = health
+
+	world.EmitCombatEvent(state.CombatEvent{Kind: state.CombatHit, Attacker: attackerID, Victim: victimID, Damage: damage, Shot: shot})
+	if health <= 0 {
+		world.EmitCombatEvent(state.CombatEvent{Kind: state.CombatKill, Attacker: attackerID, Victim: victimID, Shot: shot})
+	}
+	return true
+}
+
+// damageWall wears a destructible wall down, walls at zero health are removed
+// at the end of the update and take no more damage.
+func (cs *CombatSystem) damageWall(wallID state.EntityID, amount int, pendingWalls map[state.EntityID]state.Health) {
+	health, exist := pendingWalls[wallID]
+	if !exist {
+		destructible, exist := cs.world.Destructible.Get(wallID)
+		if !exist {
+			return
+		}
+		health = destructible.Health
+	}
+	if health <= 0 {
+		return
+	}
+	pendingWalls[wallID] = max(health-state.Health(amount), 0)
+}
+
+// rewoundHitboxes returns the player hitboxes as of the tick the shooter saw,
+// at most maxRewindTicks back, or nil to resolve the shot against the live
+// ones. Only the targets are rewound, the shooter fires from where it is and
+// walls stop the shot as they are now.
+func rewoundHitboxes(world *state.World, viewTick uint64) map[state.EntityID]state.PlayerHitbox {
+	now := world.Tick()
+	if viewTick == 0 || viewTick >= now {
+		return nil
+	}
+	tick := max(viewTick, now-min(now, maxRewindTicks))
+	hitboxes, _ := world.HitboxesAt(tick)
+	return hitboxes
+}
+
+// hitscan returns the closest living player hitbox along the ray, or the wall
+// stopping the shot before it. wall reports that a wall was hit. Targets are
+// taken where hitboxes has them when given, players missing from it are
+// skipped.
+func hitscan(world *state.World, shooterID state.EntityID, origin vector.Vector2D, dir, maxRange float64, hitboxes map[state.EntityID]state.PlayerHitbox, pendingHealth map[state.EntityID]state.Health) (targetID state.EntityID, wall bool, hit bool) {
+	rayDir := vector.Vector2D{X: math.Sin(dir), Y: -math.Cos(dir)}
+
+	var closestID state.EntityID
+	closestDist := maxRange
+	found := false
+
+	shooterMeta, _ := world.EntityMeta.Get(shooterID)
+
+	for targetID, hitbox := range world.PlayerHitbox.All() {
+		if targetID == shooterID || world.IsDead(targetID) || isPendingDead(pendingHealth, targetID) || !world.SameFloor(shooterID, targetID) {
+			continue
+		}
+		// enemies do not hurt each other
+		if targetMeta, _ := world.EntityMeta.Get(targetID); shooterMeta.Has(state.ComponentEnemy) && targetMeta.Has(state.ComponentEnemy) {
+			continue
+		}
+
+		targetPos, exist := world.Position.Get(targetID)
+		if hitboxes != nil {
+			hitbox, exist = hitboxes[targetID]
+			targetPos = hitbox.Center
+		}
+		if !exist {
+			continue
+		}
+
+		dist, hit := rayCircleIntersect(origin, rayDir, vector.Vector2D(targetPos), hitbox.Radius)
+		if hit && dist <= closestDist {
+			closestID = targetID
+			closestDist = dist
+			found = true
+		}
+	}
+
+	if wallID, blocked := firstWall(world, world.FloorOf(shooterID), origin, origin.Add(rayDir.Scale(closestDist))); blocked {
+		return wallID, true, true
+	}
+	if !found {
+		return 0, false, false
+	}
+	return closestID, false, true
+}
+
+// firstWall returns the static collider of a floor the segment enters first.
+func firstWall(world *state.World, floor state.Floor, from, to vector.Vector2D) (state.EntityID, bool) {
+	grid := world.FloorGrid(floor)
+	if grid == nil {
+		return 0, false
+	}
+	bounds := state.Bounds{
+		MinX: math.Min(from.X, to.X), MinY: math.Min(from.Y, to.Y),
+		MaxX: math.Max(from.X, to.X), MaxY: math.Max(from.Y, to.Y),
+	}
+
+	var wallID state.EntityID
+	closest := math.Inf(1)
+	for _, cell := range grid.CellsInBounds(bounds) {
+		for _, entry := range cell.Entries {
+			if !entry.Layer.Has(state.LayerStatic) {
+				continue
+			}
+			collider, exist := world.Collider.Get(entry.EntityID)
+			if !exist {
+				continue
+			}
+
+			boxMin, boxMax := collider.BoundingBox()
+			if t, hit := segmentEntryAABB(from, to, boxMin, boxMax); hit && t < closest {
+				wallID, closest = entry.EntityID, t
+			}
+		}
+	}
+	return wallID, !math.IsInf(closest, 1)
+}
+
+func isPendingDead(pendingHealth map[state.EntityID]state.Health, id state.EntityID) bool {
+	health, exist := pendingHealth[id]
+	return exist && health <= 0
+}
+
+// rayCircleIntersect returns the distance along a normalized ray to the first point on the circle.
+func rayCircleIntersect(origin, dir, center vector.Vector2D, radius float64) (float64, bool) {
+	m := origin.Sub(center)
+	b := m.Dot(dir)
+	c := m.Dot(m) - radius*radius
+
+	if c > 0 && b > 0 {
+		return 0, false
+	}
+
+	discriminant := b*b - c
+	if discriminant < 0 {
+		return 0, false
+	}
+
+	return math.Max(-b-math.Sqrt(discriminant), 0), true
+}
@@ -0,0 +1,203 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/state"
+)
+
+var testWeapon = state.Weapon{Damage: 40, Range: 50, FireInterval: 0.5}
+
+func armPlayer(world *state.World, id state.EntityID, weapon state.Weapon) {
+	world.UpdatePlayer(id, state.UpdatePlayer{
+		UpdateMeta: state.ComponentWeapon,
+		Weapon:     weapon,
+	})
+	world.ApplyCommands()
+}
+
+func fire(world *state.World, cs *CombatSystem, shooterID state.EntityID) {
+	world.ClearCombatEvents()
+	world.SetInput(shooterID, state.Input{Fire: true})
+	world.SyncInputBuffer()
+	cs.Update(1.0 / 60.0)
+	world.ApplyCommands()
+}
+
+func countEvents(world *state.World, kind state.CombatEventKind) int {
+	count := 0
+	for _, event := range world.CombatEvents() {
+		if event.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCombat_HitscanDamagesTargetInFront(t *testing.T) {
+	// facing east (+X)
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	armPlayer(world, shooterID, testWeapon)
+	cs := NewCombatSystem(world)
+
+	fire(world, cs, shooterID)
+
+	health, _ := world.Health.Get(targetID)
+	if health != 60 {
+		t.Errorf("Expected target health 60, got %d", health)
+	}
+	if countEvents(world, state.CombatShot) != 1 || countEvents(world, state.CombatHit) != 1 {
+		t.Errorf("Expected one shot and one hit, got %v", world.CombatEvents())
+	}
+}
+
+func TestCombat_MissesWhenAimingAway(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	armPlayer(world, shooterID, testWeapon)
+
+	fire(world, NewCombatSystem(world), shooterID)
+
+	if health, _ := world.Health.Get(targetID); health != 100 {
+		t.Errorf("Expected target untouched, got health %d", health)
+	}
+}
+
+func TestCombat_WallBlocksShot(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	addWall(world, 25, 50, 1, 5)
+	armPlayer(world, shooterID, testWeapon)
+
+	fire(world, NewCombatSystem(world), shooterID)
+
+	if health, _ := world.Health.Get(targetID); health != 100 {
+		t.Errorf("Expected wall to block the shot, got health %d", health)
+	}
+}
+
+func TestCombat_CooldownAndKill(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	armPlayer(world, shooterID, testWeapon)
+	cs := NewCombatSystem(world)
+
+	fire(world, cs, shooterID)
+	fire(world, cs, shooterID)
+	if health, _ := world.Health.Get(targetID); health != 60 {
+		t.Fatalf("Expected cooldown to block the second shot, got health %d", health)
+	}
+
+	// three more shots after the cooldown, the last one overkills
+	for range 3 {
+		weapon, _ := world.Weapon.Get(shooterID)
+		weapon.Cooldown = 0
+		armPlayer(world, shooterID, weapon)
+		fire(world, cs, shooterID)
+	}
+
+	if !world.IsDead(targetID) {
+		t.Fatalf("Expected target to be dead")
+	}
+	if health, _ := world.Health.Get(targetID); health != 0 {
+		t.Errorf("Expected health clamped at 0, got %d", health)
+	}
+}
+
+func TestCombat_RewindsTargetsToViewTick(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	armPlayer(world, shooterID, state.Weapon{Damage: 10, Range: 50})
+	cs := NewCombatSystem(world)
+	world.RecordHitboxes()
+	seen := world.Tick()
+
+	// the target steps out of the line of fire after the shooter saw it
+	world.Position.Set(targetID, state.Position{X: 40, Y: 60})
+	for range 3 {
+		world.RecordHitboxes()
+	}
+
+	shoot := func(viewTick uint64) {
+		world.SetInput(shooterID, state.Input{Fire: true, ViewTick: viewTick})
+		world.SyncInputBuffer()
+		cs.Update(1.0 / 60.0)
+		world.ApplyCommands()
+	}
+
+	shoot(0)
+	if health, _ := world.Health.Get(targetID); health != 100 {
+		t.Fatalf("Expected a live shot to miss, got health %d", health)
+	}
+	shoot(seen)
+	if health, _ := world.Health.Get(targetID); health != 90 {
+		t.Fatalf("Expected the shot rewound to hit, got health %d", health)
+	}
+	if pos, _ := world.Position.Get(targetID); pos != (state.Position{X: 40, Y: 60}) {
+		t.Errorf("Expected the live position untouched, got %v", pos)
+	}
+
+	// too far back, the rewind stops at the limit where the target was gone
+	for range maxRewindTicks {
+		world.RecordHitboxes()
+	}
+	shoot(seen)
+	if health, _ := world.Health.Get(targetID); health != 90 {
+		t.Errorf("Expected the rewind clamped, got health %d", health)
+	}
+}
+
+func TestRespawn_RestoresDeadPlayerAfterDelay(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	world.UpdatePlayer(playerID, state.UpdatePlayer{UpdateMeta: state.ComponentHealth, Health: 0})
+	world.ApplyCommands()
+
+	spawn := state.Position{X: 80, Y: 80}
+	rs := NewRespawnSystem(world, []SpawnPoint{{Position: spawn}}, 1, 100)
+
+	rs.Update(0.5)
+	world.ApplyCommands()
+	if !world.IsDead(playerID) {
+		t.Fatalf("Player should still be dead before the delay")
+	}
+
+	rs.Update(0.5)
+	world.ApplyCommands()
+	if world.IsDead(playerID) {
+		t.Fatalf("Player should have respawned")
+	}
+	if pos, _ := world.Position.Get(playerID); pos != spawn {
+		t.Errorf("Expected respawn at %v, got %v", spawn, pos)
+	}
+}
+
+func TestRespawn_DisabledKeepsDeadUntilRespawnAll(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	otherID := addPlayer(world, state.Position{X: 20, Y: 50})
+	world.UpdatePlayer(playerID, state.UpdatePlayer{UpdateMeta: state.ComponentHealth, Health: 0})
+	world.UpdatePlayer(otherID, state.UpdatePlayer{UpdateMeta: state.ComponentHealth, Health: 30})
+	world.ApplyCommands()
+
+	spawn := state.Position{X: 80, Y: 80}
+	rs := NewRespawnSystem(world, []SpawnPoint{{Position: spawn}}, 1, 100)
+	rs.SetEnabled(false)
+
+	rs.Update(5)
+	world.ApplyCommands()
+	if !world.IsDead(playerID) {
+		t.Fatalf("Player should stay dead while respawning is disabled")
+	}
+
+	rs.RespawnAll()
+	world.ApplyCommands()
+	for _, id := range []state.EntityID{playerID, otherID} {
+		if health, _ := world.Health.Get(id); health != 100 {
+			t.Errorf("Expected entity %d back at full health, got %d", id, health)
+		}
+		if pos, _ := world.Position.Get(id); pos != spawn {
+			t.Errorf("Expected entity %d at the spawn point, got %v", id, pos)
+		}
+	}
+}
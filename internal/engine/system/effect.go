@@ -0,0 +1,182 @@
+package system
+
+import (
+	"maps"
+	"math"
+
+	"survival/internal/engine/state"
+)
+
+// SpawnProtection is how long players are invulnerable after spawning.
+const SpawnProtection = 2.0
+
+// EffectStacking decides how an effect merges with an active one of the same kind.
+type EffectStacking uint8
+
+const (
+	// StackRefresh keeps the longer duration and the stronger magnitude.
+	StackRefresh EffectStacking = iota
+	// StackExtend adds the new duration to the remaining one.
+	StackExtend
+	// StackIntensity adds the stacks up to MaxStacks and refreshes the duration.
+	StackIntensity
+)
+
+// EffectRule describes how effects of one kind behave. OnTick is called every
+// TickInterval seconds while the effect is active, effects without a tick
+// leave both zero.
+type EffectRule struct {
+	Stacking     EffectStacking
+	MaxStacks    int
+	TickInterval float64
+	OnTick       func(world *state.World, id state.EntityID, effect state.Effect)
+}
+
+var defaultEffectRules = map[state.EffectKind]EffectRule{
+	state.EffectBleed:        {Stacking: StackIntensity, MaxStacks: 5, TickInterval: 1, OnTick: bleedTick},
+	state.EffectSlow:         {Stacking: StackRefresh},
+	state.EffectStun:         {Stacking: StackRefresh},
+	state.EffectInvulnerable: {Stacking: StackRefresh},
+}
+
+// bleedTick queues the bleed damage, credited to whoever caused it.
+func bleedTick(world *state.World, id state.EntityID, effect state.Effect) {
+	world.QueueDamage(state.Damage{
+		Attacker: effect.Source,
+		Victim:   id,
+		Amount:   int(math.Round(effect.Magnitude * float64(effect.Stacks))),
+	})
+}
+
+// EffectSystem counts down the timed effects of entities, runs their tick
+// callbacks and merges newly applied effects by the stacking rule of their
+// kind. Dead entities lose all their effects. It has to run before the combat
+// system, which applies the damage queued by ticks.
+type EffectSystem struct {
+	world *state.World
+	rules map[state.EffectKind]EffectRule
+}
+
+func NewEffectSystem(world *state.World) *EffectSystem {
+	return &EffectSystem{
+		world: world,
+		rules: maps.Clone(defaultEffectRules),
+	}
+}
+
+// SetRule replaces the behaviour of an effect kind.
+func (es *EffectSystem) SetRule(kind state.EffectKind, rule EffectRule) {
+	es.rules[kind] = rule
+}
+
+func (es *EffectSystem) ReadMeta() state.Meta {
+	return state.ComponentEffects
+}
+
+func (es *EffectSystem) WriteMeta() state.Meta {
+	return state.ComponentEffects
+}
+
+func (es *EffectSystem) Update(dt float64) {
+	world := es.world
+
+	applied := make(map[state.EntityID][]state.Effect)
+	for _, application := range world.PendingEffects() {
+		applied[application.Target] = append(applied[application.Target], application.Effect)
+	}
+	world.ClearPendingEffects()
+
+	for id, effects := range world.Effects.All() {
+		if world.IsDead(id) {
+			if len(effects) > 0 {
+				es.write(id, nil)
+			}
+			continue
+		}
+		if len(effects) == 0 && len(applied[id]) == 0 {
+			continue
+		}
+
+		next := make(state.Effects, 0, len(effects)+len(applied[id]))
+		for _, effect := range effects {
+			if effect, active := es.advance(id, effect, dt); active {
+				next = append(next, effect)
+			}
+		}
+		for _, effect := range applied[id] {
+			next = es.merge(next, effect)
+		}
+		es.write(id, next)
+	}
+}
+
+// advance counts an effect down, running its ticks, and reports whether it is still active.
+func (es *EffectSystem) advance(id state.EntityID, effect state.Effect, dt float64) (state.Effect, bool) {
+	rule := es.rules[effect.Kind]
+	effect.Remaining -= dt
+
+	if rule.TickInterval > 0 && rule.OnTick != nil {
+		effect.TickIn -= dt
+		for effect.TickIn <= 0 {
+			rule.OnTick(es.world, id, effect)
+			effect.TickIn += rule.TickInterval
+		}
+	}
+	return effect, effect.Remaining > 0
+}
+
+// merge adds an applied effect to the active ones following the stacking rule of its kind.
+func (es *EffectSystem) merge(effects state.Effects, applied state.Effect) state.Effects {
+	if applied.Kind == state.EffectNone || applied.Remaining <= 0 {
+		return effects
+	}
+
+	rule := es.rules[applied.Kind]
+	maxStacks := max(rule.MaxStacks, 1)
+	applied.Stacks = min(max(applied.Stacks, 1), maxStacks)
+
+	for i, effect := range effects {
+		if effect.Kind != applied.Kind {
+			continue
+		}
+
+		switch rule.Stacking {
+		case StackExtend:
+			effect.Remaining += applied.Remaining
+		case StackIntensity:
+			effect.Stacks = min(effect.Stacks+applied.Stacks, maxStacks)
+			effect.Remaining = math.Max(effect.Remaining, applied.Remaining)
+		default:
+			effect.Remaining = math.Max(effect.Remaining, applied.Remaining)
+		}
+		effect.Magnitude = math.Max(effect.Magnitude, applied.Magnitude)
+		effect.Source = applied.Source
+		effects[i] = effect
+		return effects
+	}
+
+	applied.TickIn = rule.TickInterval
+	return append(effects, applied)
+}
+
+func (es *EffectSystem) write(id state.EntityID, effects state.Effects) {
+	es.world.UpdatePlayer(id, state.UpdatePlayer{
+		UpdateMeta: state.ComponentEffects,
+		Effects:    effects,
+	})
+}
+
+// ProtectSpawn makes a freshly spawned player invulnerable for SpawnProtection seconds.
+func ProtectSpawn(world *state.World, id state.EntityID) {
+	world.ApplyEffect(id, state.Effect{Kind: state.EffectInvulnerable, Remaining: SpawnProtection})
+}
+
+// movementFactor scales the movement speed of an entity by its effects,
+// 0 while stunned.
+func movementFactor(world *state.World, id state.EntityID) float64 {
+	effects, exist := world.Effects.Get(id)
+	if !exist {
+		return 1
+	}
+	return effects.MovementFactor()
+}
@@ -0,0 +1,172 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/state"
+)
+
+// tickEffects runs the effect and combat systems for the given seconds in steps of dt.
+func tickEffects(world *state.World, es *EffectSystem, cs *CombatSystem, seconds, dt float64) {
+	for range int(math.Round(seconds / dt)) {
+		world.ClearCombatEvents()
+		es.Update(dt)
+		cs.Update(dt)
+		world.ApplyCommands()
+	}
+}
+
+func TestEffect_BleedDealsDamageEveryTick(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	attackerID := addPlayer(world, state.Position{X: 10, Y: 10})
+	es, cs := NewEffectSystem(world), NewCombatSystem(world)
+
+	world.ApplyEffect(playerID, state.Effect{Kind: state.EffectBleed, Remaining: 3, Magnitude: 5, Source: attackerID})
+	tickEffects(world, es, cs, 0.5, 0.5)
+	if !world.HasEffect(playerID, state.EffectBleed) {
+		t.Fatalf("Expected the bleed to be active")
+	}
+
+	tickEffects(world, es, cs, 1, 0.5)
+	if health, _ := world.Health.Get(playerID); health != 95 {
+		t.Errorf("Expected one bleed tick after a second, got health %d", health)
+	}
+	if countEvents(world, state.CombatHit) != 1 || world.CombatEvents()[0].Attacker != attackerID {
+		t.Errorf("Expected the bleed hit credited to the attacker, got %v", world.CombatEvents())
+	}
+
+	tickEffects(world, es, cs, 3, 0.5)
+	if health, _ := world.Health.Get(playerID); health != 85 {
+		t.Errorf("Expected three bleed ticks in total, got health %d", health)
+	}
+	if world.HasEffect(playerID, state.EffectBleed) {
+		t.Errorf("Expected the bleed to expire")
+	}
+}
+
+func TestEffect_StackingRules(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	es, cs := NewEffectSystem(world), NewCombatSystem(world)
+	es.SetRule(state.EffectInvulnerable, EffectRule{Stacking: StackExtend})
+
+	for range 7 {
+		world.ApplyEffect(playerID, state.Effect{Kind: state.EffectBleed, Remaining: 2, Magnitude: 1})
+	}
+	world.ApplyEffect(playerID, state.Effect{Kind: state.EffectSlow, Remaining: 4, Magnitude: 0.2})
+	world.ApplyEffect(playerID, state.Effect{Kind: state.EffectSlow, Remaining: 1, Magnitude: 0.5})
+	world.ApplyEffect(playerID, state.Effect{Kind: state.EffectInvulnerable, Remaining: 1})
+	world.ApplyEffect(playerID, state.Effect{Kind: state.EffectInvulnerable, Remaining: 2})
+	tickEffects(world, es, cs, 0.5, 0.5)
+
+	effects, _ := world.Effects.Get(playerID)
+	if len(effects) != 3 {
+		t.Fatalf("Expected one effect per kind, got %v", effects)
+	}
+	if bleed, _ := effects.Get(state.EffectBleed); bleed.Stacks != 5 {
+		t.Errorf("Expected bleed stacks capped at 5, got %d", bleed.Stacks)
+	}
+	if slow, _ := effects.Get(state.EffectSlow); slow.Remaining != 4 || slow.Magnitude != 0.5 {
+		t.Errorf("Expected the refreshed slow to keep the longer duration and stronger magnitude, got %+v", slow)
+	}
+	if invulnerable, _ := effects.Get(state.EffectInvulnerable); invulnerable.Remaining != 3 {
+		t.Errorf("Expected the extended duration to add up to 3, got %.2f", invulnerable.Remaining)
+	}
+}
+
+func TestEffect_StunAndSlowLimitMovement(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	es, ms := NewEffectSystem(world), NewBasicMovementSystem(world)
+
+	walked := moveFor(world, ms, playerID, walkInput, 0.5)
+
+	world.ApplyEffect(playerID, state.Effect{Kind: state.EffectSlow, Remaining: 10, Magnitude: 0.5})
+	es.Update(testTick)
+	world.ApplyCommands()
+	if slowed := moveFor(world, ms, playerID, walkInput, 0.5); !floatEquals(slowed, walked/2, 0.01) {
+		t.Errorf("Expected half the distance while slowed, moved %.2f instead of %.2f", slowed, walked/2)
+	}
+
+	world.ApplyEffect(playerID, state.Effect{Kind: state.EffectStun, Remaining: 10})
+	es.Update(testTick)
+	world.ApplyCommands()
+	dir, _ := world.Direction.Get(playerID)
+	stunnedInput := walkInput
+	stunnedInput.LookHorizontal = 1
+	if moved := moveFor(world, ms, playerID, stunnedInput, 0.5); moved != 0 {
+		t.Errorf("Expected no movement while stunned, moved %.2f", moved)
+	}
+	if turned, _ := world.Direction.Get(playerID); turned != dir {
+		t.Errorf("Expected no turning while stunned")
+	}
+}
+
+func TestEffect_StunPreventsFiring(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	armPlayer(world, shooterID, testWeapon)
+	es, cs := NewEffectSystem(world), NewCombatSystem(world)
+
+	world.ApplyEffect(shooterID, state.Effect{Kind: state.EffectStun, Remaining: 1})
+	es.Update(testTick)
+	world.ApplyCommands()
+
+	fire(world, cs, shooterID)
+	if health, _ := world.Health.Get(targetID); health != 100 {
+		t.Errorf("Expected a stunned shooter not to fire, target health %d", health)
+	}
+}
+
+func TestEffect_InvulnerableIgnoresDamage(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	armPlayer(world, shooterID, testWeapon)
+	es, cs := NewEffectSystem(world), NewCombatSystem(world)
+
+	ProtectSpawn(world, targetID)
+	es.Update(testTick)
+	world.ApplyCommands()
+
+	fire(world, cs, shooterID)
+	if health, _ := world.Health.Get(targetID); health != 100 {
+		t.Errorf("Expected no damage while invulnerable, got health %d", health)
+	}
+	if countEvents(world, state.CombatHit) != 0 {
+		t.Errorf("Expected no hit events, got %v", world.CombatEvents())
+	}
+
+	// protection wears off
+	tickEffects(world, es, cs, SpawnProtection, 0.5)
+	world.UpdatePlayer(shooterID, state.UpdatePlayer{UpdateMeta: state.ComponentWeapon, Weapon: testWeapon})
+	world.ApplyCommands()
+	fire(world, cs, shooterID)
+	if health, _ := world.Health.Get(targetID); health != 60 {
+		t.Errorf("Expected damage after the protection ended, got health %d", health)
+	}
+}
+
+func TestEffect_HitEffectAppliedAndClearedOnDeath(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	weapon := testWeapon
+	weapon.HitEffect = state.Effect{Kind: state.EffectBleed, Remaining: 5, Magnitude: 1}
+	armPlayer(world, shooterID, weapon)
+	es, cs := NewEffectSystem(world), NewCombatSystem(world)
+
+	fire(world, cs, shooterID)
+	es.Update(testTick)
+	world.ApplyCommands()
+
+	bleed, _ := world.Effects.Get(targetID)
+	if effect, ok := bleed.Get(state.EffectBleed); !ok || effect.Source != shooterID {
+		t.Fatalf("Expected a bleed caused by the shooter, got %v", bleed)
+	}
+
+	world.UpdatePlayer(targetID, state.UpdatePlayer{UpdateMeta: state.ComponentHealth, Health: 0})
+	world.ApplyCommands()
+	es.Update(testTick)
+	world.ApplyCommands()
+	if effects, _ := world.Effects.Get(targetID); len(effects) != 0 {
+		t.Errorf("Expected the dead to lose their effects, got %v", effects)
+	}
+}
@@ -0,0 +1,168 @@
+package system
+
+import (
+	"math"
+
+	"survival/internal/engine/pathfinding"
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+// enemyRepathInterval is how many seconds enemies follow a flow field before
+// it is rebuilt towards where its player moved since.
+const enemyRepathInterval = 0.5
+
+// EnemySystem drives hostile NPCs. Enemies always know where the players are:
+// each one follows the flow field towards the nearest living player, shared by
+// every enemy chasing that player, and attacks with its weapon once the player
+// is in range and not behind a wall. A player's field is rebuilt at most once
+// per enemyRepathInterval, or right away when the navigation grid changed.
+type EnemySystem struct {
+	world      *state.World
+	pathfinder *pathfinding.Pathfinder
+	fields     map[enemyFieldKey]*enemyField
+	time       float64
+}
+
+type enemyFieldKey struct {
+	target state.EntityID
+	radius float64
+}
+
+type enemyField struct {
+	field   *pathfinding.FlowField
+	builtAt float64
+	usedAt  float64
+}
+
+func NewEnemySystem(world *state.World, pathfinder *pathfinding.Pathfinder) *EnemySystem {
+	return &EnemySystem{
+		world:      world,
+		pathfinder: pathfinder,
+		fields:     make(map[enemyFieldKey]*enemyField),
+	}
+}
+
+func (es *EnemySystem) ReadMeta() state.Meta {
+	return state.ComponentEnemy | state.ComponentPosition | state.ComponentDirection |
+		state.ComponentRotationSpeed | state.ComponentPlayerHitbox | state.ComponentWeapon
+}
+
+func (es *EnemySystem) WriteMeta() state.Meta {
+	return state.ComponentEnemy | state.ComponentInput
+}
+
+func (es *EnemySystem) Update(dt float64) {
+	world := es.world
+	requiredMeta := es.ReadMeta()
+	es.time += dt
+
+	for enemyID, meta := range world.EntityMeta.All() {
+		if !meta.Has(requiredMeta) || world.IsDead(enemyID) {
+			continue
+		}
+
+		enemy, exist := world.Enemy.Get(enemyID)
+		if !exist {
+			continue
+		}
+
+		enemy, input := es.think(enemyID, enemy, dt)
+
+		world.SetInput(enemyID, input)
+		world.UpdatePlayer(enemyID, state.UpdatePlayer{
+			UpdateMeta: state.ComponentEnemy,
+			Enemy:      enemy,
+		})
+	}
+
+	// drop the fields of players nobody chases anymore
+	for key, cached := range es.fields {
+		if cached.usedAt < es.time {
+			delete(es.fields, key)
+		}
+	}
+}
+
+func (es *EnemySystem) think(enemyID state.EntityID, enemy state.Enemy, dt float64) (state.Enemy, state.Input) {
+	world := es.world
+
+	pos, _ := world.Position.Get(enemyID)
+	dir, _ := world.Direction.Get(enemyID)
+	rotSpeed, _ := world.RotationSpeed.Get(enemyID)
+	hitbox, _ := world.PlayerHitbox.Get(enemyID)
+	weapon, _ := world.Weapon.Get(enemyID)
+
+	input := state.Input{MovementType: state.MovementTypeAbsolute}
+
+	floor := world.FloorOf(enemyID)
+	targetID, targetPos, found := nearestPlayer(world, floor, pos)
+	if !found {
+		enemy.Target = 0
+		return enemy, input
+	}
+
+	enemy.Target = targetID
+
+	dist := vector.Vector2D(pos).DistanceTo(vector.Vector2D(targetPos))
+	if dist <= weapon.Range && hasLineOfSight(world, floor, vector.Vector2D(pos), vector.Vector2D(targetPos)) {
+		diff := angleTo(dir, pos, targetPos)
+		input.LookHorizontal = turnInput(diff, rotSpeed, dt)
+		input.Fire = math.Abs(diff) <= float64(rotSpeed)*dt+botAimTolerance
+		return enemy, input
+	}
+
+	field, ok := es.flowField(targetID, targetPos, hitbox.Radius)
+	if !ok {
+		return enemy, input
+	}
+	if waypoint, ok := field.Next(pos); ok {
+		followPath(pos, dir, rotSpeed, []state.Position{waypoint}, dt, &input)
+	}
+	return enemy, input
+}
+
+// flowField returns the field towards a player for enemies of the given
+// radius, reusing the one built for it within the last enemyRepathInterval.
+func (es *EnemySystem) flowField(targetID state.EntityID, targetPos state.Position, radius float64) (*pathfinding.FlowField, bool) {
+	key := enemyFieldKey{target: targetID, radius: radius}
+	if cached, exist := es.fields[key]; exist && !cached.field.Stale() && es.time-cached.builtAt < enemyRepathInterval {
+		cached.usedAt = es.time
+		return cached.field, true
+	}
+
+	field, ok := es.pathfinder.FlowField(targetPos, radius)
+	if !ok {
+		delete(es.fields, key)
+		return nil, false
+	}
+	es.fields[key] = &enemyField{field: field, builtAt: es.time, usedAt: es.time}
+	return field, true
+}
+
+// nearestPlayer returns the closest living non-enemy player on floor, regardless of line of sight.
+func nearestPlayer(world *state.World, floor state.Floor, pos state.Position) (state.EntityID, state.Position, bool) {
+	var (
+		closestID  state.EntityID
+		closestPos state.Position
+		found      bool
+	)
+	closestDist := math.Inf(1)
+
+	for targetID, meta := range world.EntityMeta.All() {
+		if !meta.Has(state.ComponentPosition|state.ComponentPlayerHitbox) || meta.Has(state.ComponentEnemy) || world.IsDead(targetID) ||
+			world.FloorOf(targetID) != floor {
+			continue
+		}
+
+		targetPos, exist := world.Position.Get(targetID)
+		if !exist {
+			continue
+		}
+
+		if dist := vector.Vector2D(pos).DistanceTo(vector.Vector2D(targetPos)); dist < closestDist {
+			closestID, closestPos, closestDist, found = targetID, targetPos, dist, true
+		}
+	}
+	return closestID, closestPos, found
+}
@@ -0,0 +1,77 @@
+package system
+
+import "survival/internal/engine/state"
+
+// ladderClimbDuration is how long climbing a ladder holds the player in place.
+const ladderClimbDuration = 0.8
+
+// FloorSystem moves players to another floor when they walk into a stair
+// leading there. Only walking in counts, so a player that just arrived has to
+// step off the stair and back on to return. Ladders stun the climber for a
+// moment. Bots and enemies stay on the ground floor, the only one their
+// navigation covers.
+type FloorSystem struct {
+	world *state.World
+}
+
+func NewFloorSystem(world *state.World) *FloorSystem {
+	return &FloorSystem{world: world}
+}
+
+func (fs *FloorSystem) ReadMeta() state.Meta {
+	return state.ComponentPosition | state.ComponentPrePosition | state.ComponentInput
+}
+
+func (fs *FloorSystem) WriteMeta() state.Meta {
+	return state.ComponentFloor
+}
+
+func (fs *FloorSystem) Update(dt float64) {
+	world := fs.world
+	if len(world.Stairs) == 0 {
+		return
+	}
+	requiredMeta := fs.ReadMeta()
+
+	for entityID, meta := range world.EntityMeta.All() {
+		if !meta.Has(requiredMeta) || meta.Has(state.ComponentBot) || meta.Has(state.ComponentEnemy) || world.IsDead(entityID) {
+			continue
+		}
+
+		pos, posExist := world.Position.Get(entityID)
+		prePos, prePosExist := world.PrePosition.Get(entityID)
+		if !posExist || !prePosExist {
+			continue
+		}
+
+		floor := world.FloorOf(entityID)
+		for _, stair := range world.Stairs {
+			to, connected := stair.Destination(floor)
+			if !connected || !stair.Contains(pos) || stair.Contains(state.Position(prePos)) {
+				continue
+			}
+
+			world.UpdatePlayer(entityID, state.UpdatePlayer{
+				UpdateMeta: state.ComponentFloor,
+				Floor:      to,
+			})
+			world.RecordFloorChange(state.FloorChange{ID: entityID, From: floor, To: to})
+			if stair.Kind == state.LadderKind {
+				world.ApplyEffect(entityID, state.Effect{Kind: state.EffectStun, Remaining: ladderClimbDuration})
+			}
+			break
+		}
+	}
+}
+
+// spawnOnGroundFloor adds moving back to the ground floor, where the spawn
+// points are, to the update of a spawning entity.
+func spawnOnGroundFloor(world *state.World, entityID state.EntityID, update *state.UpdatePlayer) {
+	floor := world.FloorOf(entityID)
+	if floor == state.GroundFloor {
+		return
+	}
+	update.UpdateMeta |= state.ComponentFloor
+	update.Floor = state.GroundFloor
+	world.RecordFloorChange(state.FloorChange{ID: entityID, From: floor, To: state.GroundFloor})
+}
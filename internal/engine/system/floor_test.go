@@ -0,0 +1,128 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+func addFloorWall(world *state.World, floor state.Floor, centerX, centerY, halfW, halfH float64) state.EntityID {
+	wallID, _ := world.Entity.Alloc()
+	world.Collider.Upsert(wallID, state.Collider{
+		Center:    state.Position{X: centerX, Y: centerY},
+		HalfSize:  vector.Vector2D{X: halfW, Y: halfH},
+		ShapeType: state.ColliderBox,
+	})
+	world.EntityMeta.Upsert(wallID, state.WallMeta)
+	world.Floor.Upsert(wallID, floor)
+	world.AddStatic(wallID)
+	return wallID
+}
+
+func moveTo(world *state.World, id state.EntityID, from, to state.Position) {
+	world.UpdatePlayer(id, state.UpdatePlayer{
+		UpdateMeta:  state.ComponentPosition | state.ComponentPrePosition,
+		Position:    to,
+		PrePosition: state.PrePosition(from),
+	})
+	world.ApplyCommands()
+}
+
+func TestFloor_StairMovesWalkerOnce(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	upstairs := world.AddFloor()
+	world.Stairs = []state.Stair{{Bounds: state.Bounds{MinX: 45, MinY: 58, MaxX: 55, MaxY: 62}, From: state.GroundFloor, To: upstairs}}
+	fs := NewFloorSystem(world)
+
+	moveTo(world, playerID, state.Position{X: 50, Y: 57}, state.Position{X: 50, Y: 59})
+	fs.Update(1.0 / 60.0)
+	world.ApplyCommands()
+	if world.FloorOf(playerID) != upstairs {
+		t.Fatalf("Expected the player upstairs, got floor %d", world.FloorOf(playerID))
+	}
+	if changes := world.FloorChanges(); len(changes) != 1 || changes[0].From != state.GroundFloor || changes[0].To != upstairs {
+		t.Fatalf("Expected the floor change recorded, got %+v", changes)
+	}
+
+	// standing on the stair after arriving does not take it back down
+	world.ClearFloorChanges()
+	moveTo(world, playerID, state.Position{X: 50, Y: 59}, state.Position{X: 50, Y: 60})
+	fs.Update(1.0 / 60.0)
+	world.ApplyCommands()
+	if world.FloorOf(playerID) != upstairs || len(world.FloorChanges()) != 0 {
+		t.Errorf("Expected the player to stay upstairs, got floor %d", world.FloorOf(playerID))
+	}
+}
+
+func TestFloor_BotsAndEnemiesStayOnGroundFloor(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 10, Y: 10}, 0)
+	upstairs := world.AddFloor()
+	world.Stairs = []state.Stair{{Bounds: state.Bounds{MinX: 45, MinY: 58, MaxX: 55, MaxY: 62}, From: state.GroundFloor, To: upstairs}}
+	fs := NewFloorSystem(world)
+
+	cfg := testWaveConfig.Enemy
+	cfg.Floor = upstairs
+	enemyID, _ := world.CreateEnemy(cfg)
+	world.ApplyCommands()
+	botID := addBot(world, state.Position{X: 50, Y: 57})
+	if world.FloorOf(enemyID) != state.GroundFloor {
+		t.Fatalf("Expected the enemy created on the ground floor, got floor %d", world.FloorOf(enemyID))
+	}
+
+	for _, id := range []state.EntityID{botID, enemyID} {
+		moveTo(world, id, state.Position{X: 50, Y: 57}, state.Position{X: 50, Y: 59})
+	}
+	fs.Update(1.0 / 60.0)
+	world.ApplyCommands()
+	if world.FloorOf(botID) != state.GroundFloor || world.FloorOf(enemyID) != state.GroundFloor {
+		t.Errorf("Expected the stairs to leave bots and enemies on the ground floor, got floors %d and %d",
+			world.FloorOf(botID), world.FloorOf(enemyID))
+	}
+}
+
+func TestFloor_ShotsStayOnTheirFloor(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	upstairs := world.AddFloor()
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	world.Floor.Upsert(targetID, upstairs)
+	armPlayer(world, shooterID, testWeapon)
+	cs := NewCombatSystem(world)
+
+	fire(world, cs, shooterID)
+	if health, _ := world.Health.Get(targetID); health != 100 {
+		t.Fatalf("Expected a shot not to reach another floor, got health %d", health)
+	}
+
+	// upstairs a wall stands in between
+	addFloorWall(world, upstairs, 25, 50, 1, 5)
+	world.Floor.Upsert(shooterID, upstairs)
+
+	weapon, _ := world.Weapon.Get(shooterID)
+	weapon.Cooldown = 0
+	armPlayer(world, shooterID, weapon)
+	fire(world, cs, shooterID)
+	if health, _ := world.Health.Get(targetID); health != 100 {
+		t.Errorf("Expected the wall upstairs to stop the shot, got health %d", health)
+	}
+	if !hasLineOfSight(world, state.GroundFloor, vector.Vector2D{X: 10, Y: 50}, vector.Vector2D{X: 40, Y: 50}) {
+		t.Error("Expected the ground floor to stay open")
+	}
+}
+
+func TestFloor_RespawnReturnsToGroundFloor(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	upstairs := world.AddFloor()
+	world.Floor.Upsert(playerID, upstairs)
+	rs := NewRespawnSystem(world, []SpawnPoint{{Position: state.Position{X: 10, Y: 10}}}, 3, 100)
+
+	rs.Respawn(playerID)
+	world.ApplyCommands()
+	if world.FloorOf(playerID) != state.GroundFloor {
+		t.Errorf("Expected the player back on the ground floor, got %d", world.FloorOf(playerID))
+	}
+	if changes := world.FloorChanges(); len(changes) != 1 || changes[0].To != state.GroundFloor {
+		t.Errorf("Expected the floor change recorded, got %+v", changes)
+	}
+}
@@ -0,0 +1,229 @@
+package system
+
+import (
+	"math"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+// LightingSystem keeps light sources registered in the grid on LayerLight and
+// computes the light level of every grid cell from the ambient light plus all
+// lights registered in that cell. Only the cells of lights that were added,
+// removed, moved or turned since the last update are computed again, the
+// whole map only when the ambient light changes. Walls do not occlude light
+// yet.
+type LightingSystem struct {
+	world      *state.World
+	registered map[state.EntityID]registeredLight
+	levels     []float64
+	// dirty marks the cells to compute again, listed in dirtyCells
+	dirty      []bool
+	dirtyCells []int
+	ambient    float64
+	computed   bool
+}
+
+// registeredLight is a light as the levels of its cells were computed with.
+type registeredLight struct {
+	minGX, minGY int
+	maxGX, maxGY int
+	indexes      []int
+	pos          state.Position
+	dir          state.Direction
+	light        state.Light
+}
+
+func NewLightingSystem(world *state.World) *LightingSystem {
+	width, height := world.Grid.Size()
+	return &LightingSystem{
+		world:      world,
+		registered: make(map[state.EntityID]registeredLight),
+		levels:     make([]float64, width*height),
+		dirty:      make([]bool, width*height),
+	}
+}
+
+func (ls *LightingSystem) ReadMeta() state.Meta {
+	return state.ComponentPosition | state.ComponentLight
+}
+
+func (ls *LightingSystem) WriteMeta() state.Meta {
+	return state.ComponentLight
+}
+
+func (ls *LightingSystem) Update(dt float64) {
+	ls.toggleFlashlights()
+	ls.syncGrid()
+	ls.computeLevels()
+}
+
+// toggleFlashlights flips lights whose owner requested a toggle this tick.
+func (ls *LightingSystem) toggleFlashlights() {
+	world := ls.world
+	for entityID, input := range world.Input.All() {
+		if !input.ToggleFlashlight {
+			continue
+		}
+
+		light, exist := world.Light.Get(entityID)
+		if !exist || light.Radius <= 0 {
+			continue
+		}
+
+		light.Enabled = !light.Enabled
+		world.UpdatePlayer(entityID, state.UpdatePlayer{
+			UpdateMeta: state.ComponentLight,
+			Light:      light,
+		})
+	}
+}
+
+// syncGrid registers enabled lights in the cells their radius covers and
+// unregisters lights that were disabled, moved or destroyed. The cells of
+// every light that changed are marked to be computed again.
+func (ls *LightingSystem) syncGrid() {
+	world := ls.world
+	grid := &world.Grid
+
+	for entityID, reg := range ls.registered {
+		light, exist := world.Light.Get(entityID)
+		if world.Entity.IsAlive(entityID) && exist && light.Enabled {
+			continue
+		}
+		grid.Remove(reg.indexes, entityID)
+		ls.markDirty(reg.indexes)
+		delete(ls.registered, entityID)
+	}
+
+	for entityID, light := range world.Light.All() {
+		if !light.Enabled || light.Radius <= 0 {
+			continue
+		}
+
+		pos, exist := world.Position.Get(entityID)
+		if !exist {
+			continue
+		}
+
+		dir, _ := world.Direction.Get(entityID)
+		minGX, minGY := grid.GridCoord(pos.X-light.Radius, pos.Y-light.Radius)
+		maxGX, maxGY := grid.GridCoord(pos.X+light.Radius, pos.Y+light.Radius)
+
+		reg, exist := ls.registered[entityID]
+		if exist && reg.pos == pos && reg.dir == dir && reg.light == light {
+			continue
+		}
+		if exist {
+			ls.markDirty(reg.indexes)
+		}
+		if exist && reg.minGX == minGX && reg.minGY == minGY && reg.maxGX == maxGX && reg.maxGY == maxGY {
+			// still covers the same cells, only their levels change
+			reg.pos, reg.dir, reg.light = pos, dir, light
+			ls.registered[entityID] = reg
+			continue
+		}
+		if exist {
+			grid.Remove(reg.indexes, entityID)
+		}
+
+		indexes := grid.Add(entityID, state.Bounds{
+			MinX: pos.X - light.Radius, MinY: pos.Y - light.Radius,
+			MaxX: pos.X + light.Radius, MaxY: pos.Y + light.Radius,
+		}, state.LayerLight)
+		ls.markDirty(indexes)
+
+		ls.registered[entityID] = registeredLight{
+			minGX: minGX, minGY: minGY,
+			maxGX: maxGX, maxGY: maxGY,
+			indexes: indexes,
+			pos:     pos,
+			dir:     dir,
+			light:   light,
+		}
+	}
+}
+
+func (ls *LightingSystem) markDirty(indexes []int) {
+	for _, index := range indexes {
+		if !ls.dirty[index] {
+			ls.dirty[index] = true
+			ls.dirtyCells = append(ls.dirtyCells, index)
+		}
+	}
+}
+
+// computeLevels computes the levels of the dirty cells, or of all of them on
+// the first update and when the ambient light changed.
+func (ls *LightingSystem) computeLevels() {
+	world := ls.world
+	if !ls.computed || ls.ambient != world.AmbientLight {
+		ls.computed, ls.ambient = true, world.AmbientLight
+		for index := range ls.levels {
+			ls.levels[index] = ls.computeLevel(index)
+		}
+	} else if len(ls.dirtyCells) > 0 {
+		for _, index := range ls.dirtyCells {
+			ls.levels[index] = ls.computeLevel(index)
+		}
+	} else {
+		return
+	}
+
+	for _, index := range ls.dirtyCells {
+		ls.dirty[index] = false
+	}
+	ls.dirtyCells = ls.dirtyCells[:0]
+	world.LightMap.SetLevels(ls.levels)
+}
+
+func (ls *LightingSystem) computeLevel(index int) float64 {
+	world := ls.world
+	grid := &world.Grid
+	level := world.AmbientLight
+
+	gx, gy := grid.GridCoordFromIndex(index)
+	cx, cy := grid.WorldCoordOfCellCenter(gx, gy)
+	cellCenter := vector.Vector2D{X: cx, Y: cy}
+
+	for _, entry := range grid.Cell(index).Entries {
+		if !entry.Layer.Has(state.LayerLight) {
+			continue
+		}
+		level += ls.contribution(entry.EntityID, cellCenter, grid.CellSize())
+	}
+
+	return math.Min(level, 1)
+}
+
+// contribution returns how much a light brightens a point, falling off
+// linearly with distance. Cone lights only reach points inside their cone,
+// except for the cell the light itself stands in.
+func (ls *LightingSystem) contribution(entityID state.EntityID, point vector.Vector2D, cellSize float64) float64 {
+	world := ls.world
+
+	light, exist := world.Light.Get(entityID)
+	if !exist || !light.Enabled {
+		return 0
+	}
+	pos, exist := world.Position.Get(entityID)
+	if !exist {
+		return 0
+	}
+
+	toPoint := point.Sub(vector.Vector2D(pos))
+	dist := toPoint.Magnitude()
+	if dist > light.Radius {
+		return 0
+	}
+
+	if light.Cone > 0 && dist > cellSize/2 {
+		dir, _ := world.Direction.Get(entityID)
+		forward := vector.Vector2D{X: math.Sin(float64(dir)), Y: -math.Cos(float64(dir))}
+		if forward.Dot(toPoint.Normalize()) < math.Cos(light.Cone) {
+			return 0
+		}
+	}
+
+	return light.Intensity * (1 - dist/light.Radius)
+}
@@ -0,0 +1,137 @@
+package system
+
+import (
+	"slices"
+	"testing"
+
+	"survival/internal/engine/state"
+)
+
+func addLight(world *state.World, x, y float64, light state.Light) state.EntityID {
+	lightID, _ := world.Entity.Alloc()
+	world.Position.Upsert(lightID, state.Position{X: x, Y: y})
+	world.Direction.Upsert(lightID, 0)
+	world.Light.Upsert(lightID, light)
+	world.EntityMeta.Upsert(lightID, state.LightMeta)
+	return lightID
+}
+
+func TestLighting_AmbientOnly(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	world.AmbientLight = 0.2
+	ls := NewLightingSystem(world)
+
+	ls.Update(1.0 / 60.0)
+
+	if level := world.LightMap.LevelAt(10, 10); !floatEquals(level, 0.2, 1e-6) {
+		t.Errorf("Expected ambient level 0.2, got %f", level)
+	}
+}
+
+func TestLighting_PointLightBrightensNearbyCells(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	world.AmbientLight = 0
+	addLight(world, 22.5, 22.5, state.Light{Radius: 20, Intensity: 1, Enabled: true})
+	ls := NewLightingSystem(world)
+
+	ls.Update(1.0 / 60.0)
+
+	near := world.LightMap.LevelAt(22.5, 22.5)
+	mid := world.LightMap.LevelAt(32.5, 22.5)
+	far := world.LightMap.LevelAt(90, 90)
+
+	if !floatEquals(near, 1, 1e-6) {
+		t.Errorf("Expected full light at the light position, got %f", near)
+	}
+	if mid <= 0 || mid >= near {
+		t.Errorf("Expected light to fall off with distance, near=%f mid=%f", near, mid)
+	}
+	if far != 0 {
+		t.Errorf("Expected darkness outside the light radius, got %f", far)
+	}
+}
+
+func TestLighting_FlashlightToggle(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	world.AmbientLight = 0
+	world.Light.Upsert(playerID, state.Light{Radius: 20, Intensity: 1, Cone: 0.5})
+	ls := NewLightingSystem(world)
+
+	ls.Update(1.0 / 60.0)
+	world.ApplyCommands()
+	if level := world.LightMap.LevelAt(50, 40); level != 0 {
+		t.Fatalf("Expected darkness with flashlight off, got %f", level)
+	}
+
+	world.SetInput(playerID, state.Input{ToggleFlashlight: true})
+	world.SyncInputBuffer()
+	ls.Update(1.0 / 60.0)
+	world.ApplyCommands()
+
+	light, _ := world.Light.Get(playerID)
+	if !light.Enabled {
+		t.Fatal("Flashlight should be enabled after toggle")
+	}
+
+	// the toggle must not repeat on the following ticks
+	world.SyncInputBuffer()
+	ls.Update(1.0 / 60.0)
+	world.ApplyCommands()
+
+	light, _ = world.Light.Get(playerID)
+	if !light.Enabled {
+		t.Fatal("Flashlight should stay enabled without a new toggle")
+	}
+
+	// direction 0 faces negative Y
+	ahead := world.LightMap.LevelAt(50, 40)
+	behind := world.LightMap.LevelAt(50, 60)
+	if ahead <= 0 {
+		t.Errorf("Expected light ahead of the player, got %f", ahead)
+	}
+	if behind != 0 {
+		t.Errorf("Expected no light behind the player, got %f", behind)
+	}
+}
+
+func TestLighting_MovingLightOnlyRecomputesItsCells(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	world.AmbientLight = 0.1
+	world.Light.Upsert(playerID, state.Light{Radius: 20, Intensity: 1, Cone: 0.5, Enabled: true})
+	addLight(world, 80, 80, state.Light{Radius: 10, Intensity: 0.5, Enabled: true})
+	ls := NewLightingSystem(world)
+	ls.Update(1.0 / 60.0)
+
+	// the flashlight turns and moves a little, then further, into other cells
+	for _, move := range []struct {
+		pos state.Position
+		dir state.Direction
+	}{{state.Position{X: 51, Y: 50}, 0.3}, {state.Position{X: 30, Y: 35}, 2}} {
+		world.UpdatePlayer(playerID, state.UpdatePlayer{
+			UpdateMeta: state.ComponentPosition | state.ComponentDirection,
+			Position:   move.pos,
+			Direction:  move.dir,
+		})
+		world.ApplyCommands()
+		version := world.LightMap.Version()
+		ls.Update(1.0 / 60.0)
+		if world.LightMap.Version() == version {
+			t.Fatalf("Expected the levels to change once the flashlight moved to %+v", move.pos)
+		}
+
+		full := make([]float64, len(ls.levels))
+		for index := range full {
+			full[index] = ls.computeLevel(index)
+		}
+		if !slices.Equal(ls.levels, full) {
+			t.Fatalf("Expected the levels of a full computation after moving to %+v", move.pos)
+		}
+	}
+
+	// nothing moved, nothing to compute
+	version := world.LightMap.Version()
+	ls.Update(1.0 / 60.0)
+	if world.LightMap.Version() != version || len(ls.dirtyCells) != 0 {
+		t.Errorf("Expected no change without moving lights")
+	}
+}
@@ -1,8 +1,9 @@
 package system
 
 import (
-	"math"
+	"iter"
 
+	"survival/internal/engine/movement"
 	"survival/internal/engine/state"
 	"survival/internal/engine/vector"
 )
@@ -21,7 +22,8 @@ func (ms *BasicMovementSystem) ReadMeta() state.Meta {
 }
 
 func (ms *BasicMovementSystem) WriteMeta() state.Meta {
-	return state.ComponentPosition | state.ComponentDirection | state.ComponentPrePosition | state.ComponentPlayerHitbox
+	return state.ComponentPosition | state.ComponentDirection | state.ComponentPrePosition | state.ComponentPlayerHitbox |
+		state.ComponentStamina
 }
 
 func (ms *BasicMovementSystem) Update(dt float64) {
@@ -29,7 +31,7 @@ func (ms *BasicMovementSystem) Update(dt float64) {
 	requiredMeta := ms.ReadMeta()
 
 	for entityID, meta := range world.EntityMeta.All() {
-		if !meta.Has(requiredMeta) {
+		if !meta.Has(requiredMeta) || world.IsDead(entityID) {
 			continue
 		}
 
@@ -54,16 +56,38 @@ func (ms *BasicMovementSystem) Update(dt float64) {
 
 		var updateMeta state.Meta
 
-		prePos := state.PrePosition(pos)
+		// stunned entities neither move nor turn
+		if world.HasEffect(entityID, state.EffectStun) {
+			input = state.Input{}
+		}
 
-		newPos := ms.resolvePlayerCollisions(
-			ms.calculatePlayerNewPosition(pos, dir, moveSpeed, input, dt),
-			playerShape.Radius,
-			world,
-		)
-		newDir := ms.calculatePlayerNewDirection(dir, rotSpeed, input, dt)
+		// sprinting speeds up movement at the cost of stamina
+		sprinting := isSprinting(world, entityID, input)
+		speed := inputSpeed(world, entityID, moveSpeed, input)
+
+		stamina, staminaExist := world.Stamina.Get(entityID)
+		newStamina := updateStamina(stamina, sprinting, isMoving(input), dt)
+		if staminaExist && newStamina != stamina {
+			updateMeta = updateMeta.Set(state.ComponentStamina)
+		}
 
-		updateMeta = updateMeta.Set(state.ComponentPrePosition)
+		prePos := state.PrePosition(pos)
+		in := movementInput(input)
+
+		// entities with a velocity are moved by the PhysicsSystem
+		newPos := pos
+		if !meta.Has(state.ComponentVelocity) {
+			body := movement.Move(
+				movement.Body{Position: vector.Vector2D(pos), Direction: float64(dir)},
+				in,
+				movement.Stats{Speed: float64(speed), Radius: playerShape.Radius},
+				floorWalls(world, world.FloorGrid(world.FloorOf(entityID))),
+				dt,
+			)
+			newPos = state.Position(body.Position)
+			updateMeta = updateMeta.Set(state.ComponentPrePosition)
+		}
+		newDir := state.Direction(movement.Turn(float64(dir), float64(rotSpeed), in, dt))
 
 		if newPos != pos {
 			updateMeta = updateMeta.Set(state.ComponentPosition)
@@ -80,130 +104,60 @@ func (ms *BasicMovementSystem) Update(dt float64) {
 			RotationSpeed: rotSpeed,
 			PlayerHitbox:  state.PlayerHitbox{Center: newPos, Radius: playerShape.Radius},
 			PrePosition:   prePos,
+			Stamina:       newStamina,
 		})
 	}
 }
 
-// calculatePlayerNewPosition computes new position based on analog input.
-// Uses screen coordinates: Y increases downward.
-// MoveVertical: Positive = down, Negative = up
-// MoveHorizontal: Positive = right, Negative = left
-func (ms *BasicMovementSystem) calculatePlayerNewPosition(pos state.Position, dir state.Direction, speed state.MovementSpeed, input state.Input, dt float64) state.Position {
-	var moveX, moveY float64
-
-	switch input.MovementType {
-	case state.MovementTypeRelative:
-		forward := input.MoveVertical
-		strafe := input.MoveHorizontal
-
-		dirRad := float64(dir)
-		cosDir := math.Cos(dirRad)
-		sinDir := math.Sin(dirRad)
-
-		fwdX := sinDir
-		fwdY := -cosDir
-		rightX := cosDir
-		rightY := sinDir
-
-		moveX = (forward * fwdX) + (strafe * rightX)
-		moveY = (forward * fwdY) + (strafe * rightY)
-
-	default:
-		moveX = input.MoveHorizontal
-		moveY = input.MoveVertical
-	}
-
-	movement := vector.Vector2D{X: moveX, Y: moveY}
-	if movement.X != 0 || movement.Y != 0 {
-		movement = movement.Normalize().Scale(float64(speed) * dt)
+// inputSpeed is the speed input moves an entity at, slowed down by effects
+// and sped up by sprinting.
+func inputSpeed(world *state.World, entityID state.EntityID, moveSpeed state.MovementSpeed, input state.Input) state.MovementSpeed {
+	speed := moveSpeed * state.MovementSpeed(movementFactor(world, entityID))
+	if isSprinting(world, entityID, input) {
+		speed *= sprintSpeedMultiplier
 	}
-
-	return state.Position(vector.Vector2D(pos).Add(movement))
+	return speed
 }
 
-// calculatePlayerNewDirection computes new direction based on rotation input.
-// LookHorizontal: Positive = clockwise (right), Negative = counter-clockwise (left).
-func (ms *BasicMovementSystem) calculatePlayerNewDirection(dir state.Direction, speed state.RotationSpeed, input state.Input, dt float64) state.Direction {
-	rotationDelta := input.LookHorizontal * float64(speed) * dt
-	return state.Direction(float64(dir) + rotationDelta)
+// movementInput is the part of the input the movement package moves by.
+func movementInput(input state.Input) movement.Input {
+	return movement.Input{
+		MoveHorizontal: input.MoveHorizontal,
+		MoveVertical:   input.MoveVertical,
+		LookHorizontal: input.LookHorizontal,
+		Relative:       input.MovementType == state.MovementTypeRelative,
+	}
 }
 
-// resolvePlayerCollisions checks for wall collisions and adjusts position.
-// Assumes circular player hitbox.
-// Uses Circle-AABB collision detection.
-func (ms *BasicMovementSystem) resolvePlayerCollisions(pos state.Position, radius float64, world *state.World) state.Position {
-	result := vector.Vector2D(pos)
-
-	playerBounds := state.Bounds{
-		MinX: result.X - radius,
-		MinY: result.Y - radius,
-		MaxX: result.X + radius,
-		MaxY: result.Y + radius,
+// floorWalls yields the static colliders of the floor grid around a hitbox.
+// Assumes the colliders are boxes aligned with the axes.
+func floorWalls(world *state.World, grid *state.Grid) movement.Walls {
+	if grid == nil {
+		return nil
 	}
-
-	for _, cell := range world.Grid.CellsInBounds(playerBounds) {
-		for _, entry := range cell.Entries {
-			if !entry.Layer.Has(state.LayerStatic) {
-				continue
-			}
-
-			wallShape, exist := world.Collider.Get(entry.EntityID)
-			if !exist {
-				continue
-			}
-			wallMin, wallMax := wallShape.BoundingBox() // assume box aligned with axis
-			collides, pushOut := circleAABBCollision(state.Position(result), radius, wallMin, wallMax)
-			if collides {
-				result = result.Add(pushOut)
+	return func(center vector.Vector2D, radius float64) iter.Seq[movement.Box] {
+		bounds := state.Bounds{
+			MinX: center.X - radius,
+			MinY: center.Y - radius,
+			MaxX: center.X + radius,
+			MaxY: center.Y + radius,
+		}
+		return func(yield func(movement.Box) bool) {
+			for _, cell := range grid.CellsInBounds(bounds) {
+				for _, entry := range cell.Entries {
+					if !entry.Layer.Has(state.LayerStatic) {
+						continue
+					}
+					wallShape, exist := world.Collider.Get(entry.EntityID)
+					if !exist {
+						continue
+					}
+					wallMin, wallMax := wallShape.BoundingBox()
+					if !yield(movement.Box{Min: wallMin, Max: wallMax}) {
+						return
+					}
+				}
 			}
 		}
 	}
-
-	return state.Position(result)
-}
-
-// circleAABBCollision detects collision between a circle and an AABB.
-// Returns whether collision occurred and the push-out vector to resolve it.
-func circleAABBCollision(circleCenter state.Position, radius float64, wallMin, wallMax vector.Vector2D) (collides bool, pushOut vector.Vector2D) {
-	center := vector.Vector2D(circleCenter)
-
-	closest := vector.Vector2D{
-		X: math.Max(wallMin.X, math.Min(center.X, wallMax.X)),
-		Y: math.Max(wallMin.Y, math.Min(center.Y, wallMax.Y)),
-	}
-
-	diff := center.Sub(closest)
-	dist := diff.Magnitude()
-
-	if dist >= radius {
-		return false, vector.Vector2D{}
-	}
-
-	if dist > 0 {
-		penetration := radius - dist
-		return true, diff.Normalize().Scale(penetration)
-	}
-
-	// Circle center inside AABB - find nearest edge and push out
-	distToLeft := center.X - wallMin.X
-	distToRight := wallMax.X - center.X
-	distToTop := center.Y - wallMin.Y
-	distToBottom := wallMax.Y - center.Y
-
-	minDist := distToLeft
-	pushOut = vector.Vector2D{X: -(distToLeft + radius), Y: 0}
-
-	if distToRight < minDist {
-		minDist = distToRight
-		pushOut = vector.Vector2D{X: distToRight + radius, Y: 0}
-	}
-	if distToTop < minDist {
-		minDist = distToTop
-		pushOut = vector.Vector2D{X: 0, Y: -(distToTop + radius)}
-	}
-	if distToBottom < minDist {
-		pushOut = vector.Vector2D{X: 0, Y: distToBottom + radius}
-	}
-
-	return true, pushOut
 }
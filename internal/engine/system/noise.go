@@ -0,0 +1,176 @@
+package system
+
+import (
+	"math"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+const (
+	footstepStride   = 1.5   // distance walked between two footsteps
+	footstepLoudness = 15.0  // footsteps carry this far in open space
+	gunshotLoudness  = 150.0 // gunshots carry this far in open space
+
+	// wallDampening scales the remaining range of a sound for every wall it passes through.
+	wallDampening = 0.4
+	// directionStep quantizes the reported direction so players only get an approximate bearing.
+	directionStep = math.Pi / 4
+)
+
+// NoiseSystem emits footstep noises from player movement, then propagates
+// every noise emitted this tick, including gunshots from the CombatSystem,
+// to the players in earshot on the floor of the source.
+// Walls between the source and a listener dampen the sound.
+type NoiseSystem struct {
+	world    *state.World
+	walkedBy map[state.EntityID]float64
+}
+
+func NewNoiseSystem(world *state.World) *NoiseSystem {
+	return &NoiseSystem{
+		world:    world,
+		walkedBy: make(map[state.EntityID]float64),
+	}
+}
+
+func (ns *NoiseSystem) ReadMeta() state.Meta {
+	return state.ComponentPosition | state.ComponentPrePosition | state.ComponentInput | state.ComponentHearing
+}
+
+func (ns *NoiseSystem) WriteMeta() state.Meta {
+	return state.ComponentHearing
+}
+
+func (ns *NoiseSystem) Update(dt float64) {
+	ns.emitPlayerNoises()
+	ns.propagate()
+	ns.world.ClearNoises()
+}
+
+func (ns *NoiseSystem) emitPlayerNoises() {
+	world := ns.world
+
+	for entityID, meta := range world.EntityMeta.All() {
+		if !meta.Has(state.ComponentPosition | state.ComponentPrePosition) {
+			continue
+		}
+
+		pos, posExist := world.Position.Get(entityID)
+		prePos, prePosExist := world.PrePosition.Get(entityID)
+		if !posExist || !prePosExist {
+			continue
+		}
+
+		walked := ns.walkedBy[entityID] + vector.Vector2D(pos).DistanceTo(vector.Vector2D(prePos))
+		if walked >= footstepStride {
+			walked = 0
+			world.EmitNoise(state.NoiseEvent{
+				Source:   entityID,
+				Kind:     state.NoiseFootstep,
+				Position: pos,
+				Loudness: footstepLoudness,
+			})
+		}
+		ns.walkedBy[entityID] = walked
+	}
+}
+
+func (ns *NoiseSystem) propagate() {
+	world := ns.world
+	noises := world.Noises()
+
+	for listenerID, meta := range world.EntityMeta.All() {
+		if !meta.Has(state.ComponentPosition | state.ComponentHearing) {
+			continue
+		}
+
+		listenerPos, exist := world.Position.Get(listenerID)
+		if !exist {
+			continue
+		}
+
+		heard := make(state.HeardSounds, 0)
+		for _, noise := range noises {
+			if noise.Source == listenerID || !world.SameFloor(noise.Source, listenerID) {
+				continue
+			}
+
+			loudness := perceivedLoudness(world, noise, listenerPos)
+			if loudness <= 0 {
+				continue
+			}
+
+			heard = append(heard, state.HeardSound{
+				Kind:      noise.Kind,
+				Direction: approximateDirection(listenerPos, noise.Position),
+				Loudness:  loudness,
+			})
+		}
+
+		world.UpdatePlayer(listenerID, state.UpdatePlayer{
+			UpdateMeta:  state.ComponentHearing,
+			HeardSounds: heard,
+		})
+	}
+}
+
+// perceivedLoudness returns the strength of a noise at the listener in [0, 1],
+// falling off linearly with distance over a range reduced by every wall in between.
+func perceivedLoudness(world *state.World, noise state.NoiseEvent, listener state.Position) float64 {
+	from := vector.Vector2D(noise.Position)
+	to := vector.Vector2D(listener)
+
+	dist := from.DistanceTo(to)
+	if dist > noise.Loudness {
+		return 0
+	}
+
+	walls := countWallsBetween(world, world.FloorOf(noise.Source), from, to)
+	reach := noise.Loudness * math.Pow(wallDampening, float64(walls))
+
+	return math.Max(1-dist/reach, 0)
+}
+
+// countWallsBetween counts the distinct static colliders of a floor crossed by the segment.
+func countWallsBetween(world *state.World, floor state.Floor, from, to vector.Vector2D) int {
+	grid := world.FloorGrid(floor)
+	if grid == nil {
+		return 0
+	}
+	bounds := state.Bounds{
+		MinX: math.Min(from.X, to.X), MinY: math.Min(from.Y, to.Y),
+		MaxX: math.Max(from.X, to.X), MaxY: math.Max(from.Y, to.Y),
+	}
+
+	crossed := make(map[state.EntityID]struct{})
+	for _, cell := range grid.CellsInBounds(bounds) {
+		for _, entry := range cell.Entries {
+			if !entry.Layer.Has(state.LayerStatic) {
+				continue
+			}
+			if _, counted := crossed[entry.EntityID]; counted {
+				continue
+			}
+
+			collider, exist := world.Collider.Get(entry.EntityID)
+			if !exist {
+				continue
+			}
+
+			boxMin, boxMax := collider.BoundingBox()
+			if segmentIntersectsAABB(from, to, boxMin, boxMax) {
+				crossed[entry.EntityID] = struct{}{}
+			}
+		}
+	}
+	return len(crossed)
+}
+
+// approximateDirection returns the world angle from one point towards another,
+// using the same convention as Direction (0 faces negative Y, clockwise positive),
+// rounded to the nearest directionStep.
+func approximateDirection(from, to state.Position) state.Direction {
+	angle := math.Atan2(to.X-from.X, -(to.Y - from.Y))
+	return state.Direction(math.Round(angle/directionStep) * directionStep)
+}
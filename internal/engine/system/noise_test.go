@@ -0,0 +1,102 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/state"
+)
+
+func updateNoise(world *state.World, ns *NoiseSystem) {
+	ns.Update(1.0 / 60.0)
+	world.ApplyCommands()
+}
+
+func TestNoise_GunshotHeardWithDirection(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	listenerID := addPlayer(world, state.Position{X: 50, Y: 80})
+	ns := NewNoiseSystem(world)
+
+	world.SetInput(shooterID, state.Input{Fire: true})
+	world.SyncInputBuffer()
+	// gunshots are emitted by the combat system
+	NewCombatSystem(world).Update(1.0 / 60.0)
+	updateNoise(world, ns)
+
+	heard, _ := world.Hearing.Get(listenerID)
+	if len(heard) != 1 {
+		t.Fatalf("Expected 1 heard sound, got %d", len(heard))
+	}
+	if heard[0].Kind != state.NoiseGunshot {
+		t.Errorf("Expected gunshot, got %s", heard[0].Kind)
+	}
+	// the shooter is straight up (negative Y) from the listener
+	if !floatEquals(float64(heard[0].Direction), 0, 1e-6) {
+		t.Errorf("Expected direction 0, got %f", heard[0].Direction)
+	}
+
+	own, _ := world.Hearing.Get(shooterID)
+	if len(own) != 0 {
+		t.Errorf("Shooter should not hear its own gunshot, got %v", own)
+	}
+}
+
+func TestNoise_WallsDampen(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	listenerID := addPlayer(world, state.Position{X: 60, Y: 50})
+	ns := NewNoiseSystem(world)
+
+	emit := func() float64 {
+		world.EmitNoise(state.NoiseEvent{Kind: state.NoiseGunshot, Position: state.Position{X: 10, Y: 50}, Loudness: 100})
+		updateNoise(world, ns)
+		heard, _ := world.Hearing.Get(listenerID)
+		if len(heard) == 0 {
+			return 0
+		}
+		return heard[0].Loudness
+	}
+
+	open := emit()
+	addWall(world, 35, 50, 1, 5)
+	dampened := emit()
+
+	if open <= 0 {
+		t.Fatalf("Expected the sound to be heard in open space")
+	}
+	if dampened >= open {
+		t.Errorf("Expected the wall to dampen the sound: open=%f dampened=%f", open, dampened)
+	}
+}
+
+func TestNoise_OutOfRange(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 10, Y: 10}, 0)
+	listenerID := addPlayer(world, state.Position{X: 90, Y: 90})
+	ns := NewNoiseSystem(world)
+
+	world.EmitNoise(state.NoiseEvent{Kind: state.NoiseFootstep, Position: state.Position{X: 10, Y: 10}, Loudness: footstepLoudness})
+	updateNoise(world, ns)
+
+	heard, _ := world.Hearing.Get(listenerID)
+	if len(heard) != 0 {
+		t.Errorf("Expected nothing heard out of range, got %v", heard)
+	}
+}
+
+func TestApproximateDirection(t *testing.T) {
+	from := state.Position{X: 0, Y: 0}
+	tests := []struct {
+		to   state.Position
+		want float64
+	}{
+		{state.Position{X: 0, Y: -10}, 0},
+		{state.Position{X: 10, Y: 0}, math.Pi / 2},
+		{state.Position{X: 10, Y: -9}, math.Pi / 4},
+		{state.Position{X: -10, Y: 0}, -math.Pi / 2},
+	}
+	for _, tt := range tests {
+		got := approximateDirection(from, tt.to)
+		if !floatEquals(float64(got), tt.want, 1e-6) {
+			t.Errorf("approximateDirection(%v) = %f, want %f", tt.to, got, tt.want)
+		}
+	}
+}
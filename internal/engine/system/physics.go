@@ -0,0 +1,101 @@
+package system
+
+import (
+	"survival/internal/engine/movement"
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+// PhysicsSystem moves the entities with a Velocity. Movement input
+// accelerates them towards the speed the BasicMovementSystem would move them
+// at, friction slows them down without input, and the velocity is integrated
+// into the position. Walls stop the part of the velocity pushing into them,
+// so entities slide along. Impulses are added to the velocity through the
+// command buffer with World.ApplyImpulse.
+type PhysicsSystem struct {
+	world *state.World
+}
+
+func NewPhysicsSystem(world *state.World) *PhysicsSystem {
+	return &PhysicsSystem{world: world}
+}
+
+func (ps *PhysicsSystem) ReadMeta() state.Meta {
+	return state.ComponentVelocity | state.ComponentInput | state.ComponentPosition | state.ComponentDirection |
+		state.ComponentMovementSpeed | state.ComponentPlayerHitbox
+}
+
+func (ps *PhysicsSystem) WriteMeta() state.Meta {
+	return state.ComponentVelocity | state.ComponentPosition | state.ComponentPrePosition | state.ComponentPlayerHitbox
+}
+
+func (ps *PhysicsSystem) Update(dt float64) {
+	world := ps.world
+	requiredMeta := ps.ReadMeta()
+
+	for entityID, meta := range world.EntityMeta.All() {
+		if !meta.Has(requiredMeta) || world.IsDead(entityID) {
+			continue
+		}
+
+		velocity, velocityExist := world.Velocity.Get(entityID)
+		pos, posExist := world.Position.Get(entityID)
+		dir, dirExist := world.Direction.Get(entityID)
+		moveSpeed, moveSpeedExist := world.MovementSpeed.Get(entityID)
+		hitbox, hitboxExist := world.PlayerHitbox.Get(entityID)
+		if !velocityExist || !posExist || !dirExist || !moveSpeedExist || !hitboxExist {
+			continue
+		}
+
+		input, _ := world.Input.Get(entityID)
+		if world.HasEffect(entityID, state.EffectStun) {
+			input = state.Input{}
+		}
+
+		body := movement.Move(
+			movement.Body{Position: vector.Vector2D(pos), Velocity: velocity.Linear, Direction: float64(dir)},
+			movementInput(input),
+			movement.Stats{
+				Speed:   float64(inputSpeed(world, entityID, moveSpeed, input)),
+				Radius:  hitbox.Radius,
+				Inertia: velocityInertia(velocity),
+			},
+			floorWalls(world, world.FloorGrid(world.FloorOf(entityID))),
+			dt,
+		)
+		velocity.Linear = body.Velocity
+		newPos := state.Position(body.Position)
+
+		updateMeta := state.ComponentVelocity | state.ComponentPrePosition
+		if newPos != pos {
+			updateMeta = updateMeta.Set(state.ComponentPosition | state.ComponentPlayerHitbox)
+		}
+		world.UpdatePlayer(entityID, state.UpdatePlayer{
+			UpdateMeta:   updateMeta,
+			Velocity:     velocity,
+			Position:     newPos,
+			PrePosition:  state.PrePosition(pos),
+			PlayerHitbox: state.PlayerHitbox{Center: newPos, Radius: hitbox.Radius},
+		})
+	}
+}
+
+// velocityInertia is how the velocity accelerates and slows down.
+func velocityInertia(velocity state.Velocity) movement.Inertia {
+	return movement.Inertia{
+		Acceleration: velocity.Acceleration,
+		Friction:     velocity.Friction,
+		MaxSpeed:     velocity.MaxSpeed,
+	}
+}
+
+// spawnAtRest adds stopping the entity to the update of a spawning entity.
+func spawnAtRest(world *state.World, entityID state.EntityID, update *state.UpdatePlayer) {
+	velocity, exist := world.Velocity.Get(entityID)
+	if !exist {
+		return
+	}
+	velocity.Linear = vector.Vector2D{}
+	update.UpdateMeta |= state.ComponentVelocity
+	update.Velocity = velocity
+}
@@ -0,0 +1,116 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+var testVelocity = state.Velocity{Acceleration: 60, Friction: 30, MaxSpeed: 20}
+
+func addVelocity(world *state.World, id state.EntityID) {
+	meta, _ := world.EntityMeta.Get(id)
+	world.EntityMeta.Upsert(id, meta.Set(state.ComponentVelocity))
+	world.Velocity.Upsert(id, testVelocity)
+}
+
+func tickPhysics(world *state.World, ps *PhysicsSystem, id state.EntityID, input state.Input, ticks int) {
+	for range ticks {
+		world.SetInput(id, input)
+		world.SyncInputBuffer()
+		ps.Update(1.0 / 60.0)
+		world.ApplyCommands()
+	}
+}
+
+func TestPhysics_AcceleratesAndStopsWithFriction(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	addVelocity(world, playerID)
+	ps := NewPhysicsSystem(world)
+
+	tickPhysics(world, ps, playerID, state.Input{MoveHorizontal: 1}, 1)
+	velocity, _ := world.Velocity.Get(playerID)
+	if !floatEquals(velocity.Linear.X, 1, 1e-9) {
+		t.Fatalf("Expected one tick of acceleration, got %.3f", velocity.Linear.X)
+	}
+
+	tickPhysics(world, ps, playerID, state.Input{MoveHorizontal: 1}, 30)
+	velocity, _ = world.Velocity.Get(playerID)
+	if !floatEquals(velocity.Linear.X, 5, 1e-9) {
+		t.Fatalf("Expected the movement speed reached and kept, got %.3f", velocity.Linear.X)
+	}
+
+	stopped, _ := world.Position.Get(playerID)
+	tickPhysics(world, ps, playerID, state.Input{}, 60)
+	velocity, _ = world.Velocity.Get(playerID)
+	pos, _ := world.Position.Get(playerID)
+	if velocity.Linear.Magnitude() != 0 {
+		t.Errorf("Expected friction to stop the player, got %+v", velocity.Linear)
+	}
+	// sliding to a halt from 5 at 30 takes 25/60 units
+	if slid := pos.X - stopped.X; !floatEquals(slid, 25.0/60.0, 0.05) {
+		t.Errorf("Expected the player to slide to a halt, slid %.3f", slid)
+	}
+}
+
+func TestPhysics_ImpulsePushesUpToMaxSpeed(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	addVelocity(world, playerID)
+	ps := NewPhysicsSystem(world)
+
+	world.ApplyImpulse(playerID, vector.Vector2D{X: 0, Y: 100})
+	world.ApplyCommands()
+	tickPhysics(world, ps, playerID, state.Input{}, 1)
+
+	velocity, _ := world.Velocity.Get(playerID)
+	if !floatEquals(velocity.Linear.Magnitude(), testVelocity.MaxSpeed, 1e-9) {
+		t.Errorf("Expected the push capped at %.0f, got %.3f", testVelocity.MaxSpeed, velocity.Linear.Magnitude())
+	}
+	if pos, _ := world.Position.Get(playerID); pos.Y <= 50 || pos.X != 50 {
+		t.Errorf("Expected the player pushed south, got %+v", pos)
+	}
+}
+
+func TestPhysics_SlidesAlongWalls(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	addVelocity(world, playerID)
+	addWall(world, 52, 50, 1, 20)
+	ps := NewPhysicsSystem(world)
+
+	world.ApplyImpulse(playerID, vector.Vector2D{X: 10, Y: 10})
+	world.ApplyCommands()
+	tickPhysics(world, ps, playerID, state.Input{}, 10)
+
+	pos, _ := world.Position.Get(playerID)
+	velocity, _ := world.Velocity.Get(playerID)
+	if pos.X > 50.5+1e-9 {
+		t.Errorf("Expected the wall to stop the player, got x %.3f", pos.X)
+	}
+	if pos.Y <= 51 {
+		t.Errorf("Expected the player to slide along the wall, got y %.3f", pos.Y)
+	}
+	if velocity.Linear.X > 1e-9 {
+		t.Errorf("Expected no velocity left into the wall, got %+v", velocity.Linear)
+	}
+}
+
+func TestPhysics_HitKnocksTargetBackAndRecoilsShooter(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	addVelocity(world, shooterID)
+	addVelocity(world, targetID)
+	weapon := testWeapon
+	weapon.Knockback, weapon.Recoil = 8, 2
+	armPlayer(world, shooterID, weapon)
+
+	fire(world, NewCombatSystem(world), shooterID)
+
+	if velocity, _ := world.Velocity.Get(targetID); !floatEquals(velocity.Linear.X, 8, 1e-9) {
+		t.Errorf("Expected the target knocked away from the shooter, got %+v", velocity.Linear)
+	}
+	if velocity, _ := world.Velocity.Get(shooterID); !floatEquals(velocity.Linear.X, -2, 1e-9) {
+		t.Errorf("Expected the shooter pushed back by the recoil, got %+v", velocity.Linear)
+	}
+}
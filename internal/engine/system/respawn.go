@@ -0,0 +1,149 @@
+package system
+
+import (
+	"math/rand/v2"
+
+	"survival/internal/engine/state"
+)
+
+// SpawnPoint is where players spawn. Points tagged with a team are used by its
+// players only, untagged points by everyone.
+type SpawnPoint struct {
+	Position state.Position
+	Team     state.Team
+}
+
+// RespawnSystem brings dead players back at a random spawn point of their team
+// with full health once they have been dead for the respawn delay. Enemies never respawn.
+// Game modes without respawns disable it and bring everyone back at once with
+// RespawnAll when a round starts.
+type RespawnSystem struct {
+	world       *state.World
+	spawnPoints []SpawnPoint
+	delay       float64
+	health      state.Health
+	deadFor     map[state.EntityID]float64
+	disabled    bool
+}
+
+func NewRespawnSystem(world *state.World, spawnPoints []SpawnPoint, delay float64, health state.Health) *RespawnSystem {
+	return &RespawnSystem{
+		world:       world,
+		spawnPoints: spawnPoints,
+		delay:       delay,
+		health:      health,
+		deadFor:     make(map[state.EntityID]float64),
+	}
+}
+
+func (rs *RespawnSystem) ReadMeta() state.Meta {
+	return state.ComponentHealth | state.ComponentPlayerHitbox
+}
+
+func (rs *RespawnSystem) WriteMeta() state.Meta {
+	return state.ComponentHealth | state.ComponentPosition | state.ComponentPrePosition | state.ComponentPlayerHitbox |
+		state.ComponentStamina | state.ComponentEffects | state.ComponentFloor | state.ComponentVelocity
+}
+
+// SetEnabled turns respawning after the delay on or off. Dead players stay dead while it is off.
+func (rs *RespawnSystem) SetEnabled(enabled bool) {
+	rs.disabled = !enabled
+	clear(rs.deadFor)
+}
+
+func (rs *RespawnSystem) Update(dt float64) {
+	world := rs.world
+
+	for entityID := range rs.deadFor {
+		if !world.IsDead(entityID) {
+			delete(rs.deadFor, entityID)
+		}
+	}
+
+	if rs.disabled || len(rs.spawnPoints) == 0 {
+		return
+	}
+
+	for entityID := range world.PlayerHitbox.All() {
+		if !world.IsDead(entityID) {
+			continue
+		}
+		if meta, _ := world.EntityMeta.Get(entityID); meta.Has(state.ComponentEnemy) {
+			continue
+		}
+
+		rs.deadFor[entityID] += dt
+		if rs.deadFor[entityID] < rs.delay {
+			continue
+		}
+
+		rs.respawn(entityID)
+		delete(rs.deadFor, entityID)
+	}
+}
+
+// RespawnAll queues every player, dead or alive, to start over at a random
+// spawn point, as at the beginning of a round.
+func (rs *RespawnSystem) RespawnAll() {
+	if len(rs.spawnPoints) == 0 {
+		return
+	}
+
+	for entityID := range rs.world.PlayerHitbox.All() {
+		if meta, _ := rs.world.EntityMeta.Get(entityID); meta.Has(state.ComponentEnemy) {
+			continue
+		}
+		rs.respawn(entityID)
+	}
+	clear(rs.deadFor)
+}
+
+// Respawn queues a single player to start over at a spawn point of its team,
+// used when a player changes team.
+func (rs *RespawnSystem) Respawn(entityID state.EntityID) {
+	if len(rs.spawnPoints) > 0 {
+		rs.respawn(entityID)
+	}
+}
+
+func (rs *RespawnSystem) respawn(entityID state.EntityID) {
+	world := rs.world
+	hitbox, _ := world.PlayerHitbox.Get(entityID)
+
+	spawn := rs.spawnPoint(entityID)
+	update := state.UpdatePlayer{
+		UpdateMeta:   state.ComponentHealth | state.ComponentPosition | state.ComponentPrePosition | state.ComponentPlayerHitbox | state.ComponentEffects,
+		Health:       rs.health,
+		Position:     spawn,
+		PrePosition:  state.PrePosition(spawn),
+		PlayerHitbox: state.PlayerHitbox{Center: spawn, Radius: hitbox.Radius},
+	}
+	// players come back rested
+	if stamina, exist := world.Stamina.Get(entityID); exist {
+		update.UpdateMeta |= state.ComponentStamina
+		update.Stamina = state.Stamina{Current: stamina.Max, Max: stamina.Max}
+	}
+	spawnOnGroundFloor(world, entityID, &update)
+	spawnAtRest(world, entityID, &update)
+	world.UpdatePlayer(entityID, update)
+	ProtectSpawn(world, entityID)
+}
+
+// spawnPoint picks a random spawn point of the entity's team, falling back to
+// untagged points and then to any point when the map has none for the team.
+func (rs *RespawnSystem) spawnPoint(entityID state.EntityID) state.Position {
+	team, _ := rs.world.Team.Get(entityID)
+
+	for _, wanted := range []state.Team{team, state.NoTeam} {
+		var candidates []state.Position
+		for _, point := range rs.spawnPoints {
+			if point.Team == wanted {
+				candidates = append(candidates, point.Position)
+			}
+		}
+		if len(candidates) > 0 {
+			return candidates[rand.IntN(len(candidates))]
+		}
+	}
+	return rs.spawnPoints[rand.IntN(len(rs.spawnPoints))].Position
+}
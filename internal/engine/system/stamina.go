@@ -0,0 +1,53 @@
+package system
+
+import (
+	"math"
+
+	"survival/internal/engine/movement"
+	"survival/internal/engine/state"
+)
+
+const (
+	sprintSpeedMultiplier = movement.SprintSpeedMultiplier
+	staminaDrainRate      = 25.0 // stamina spent per second of sprinting
+	staminaRegenRate      = 12.0 // stamina regained per second while walking
+	staminaIdleRegenRate  = 20.0 // stamina regained per second while standing still
+	// staminaRecoverRatio is the share of the maximum an exhausted entity has
+	// to regain before it can sprint again.
+	staminaRecoverRatio = 0.3
+)
+
+// isSprinting reports whether the input makes the entity sprint this tick. It
+// has to hold sprint while moving, with stamina left and not exhausted.
+// Entities without stamina never sprint.
+func isSprinting(world *state.World, entityID state.EntityID, input state.Input) bool {
+	if !input.Sprint || !isMoving(input) {
+		return false
+	}
+	stamina, exist := world.Stamina.Get(entityID)
+	return exist && stamina.CanSprint()
+}
+
+func isMoving(input state.Input) bool {
+	return input.MoveHorizontal != 0 || input.MoveVertical != 0
+}
+
+// updateStamina drains stamina spent sprinting or regenerates it while resting.
+// Running out exhausts the entity until staminaRecoverRatio of it is back.
+func updateStamina(stamina state.Stamina, sprinting, moving bool, dt float64) state.Stamina {
+	switch {
+	case sprinting:
+		stamina.Current = math.Max(stamina.Current-staminaDrainRate*dt, 0)
+	case moving:
+		stamina.Current = math.Min(stamina.Current+staminaRegenRate*dt, stamina.Max)
+	default:
+		stamina.Current = math.Min(stamina.Current+staminaIdleRegenRate*dt, stamina.Max)
+	}
+
+	if stamina.Current <= 0 {
+		stamina.Exhausted = true
+	} else if stamina.Current >= stamina.Max*staminaRecoverRatio {
+		stamina.Exhausted = false
+	}
+	return stamina
+}
@@ -0,0 +1,139 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+const testTick = 1.0 / 60.0
+
+func giveStamina(world *state.World, id state.EntityID, stamina state.Stamina) {
+	world.UpdatePlayer(id, state.UpdatePlayer{
+		UpdateMeta: state.ComponentStamina,
+		Stamina:    stamina,
+	})
+	world.ApplyCommands()
+}
+
+// moveFor runs the movement system with the same input and returns the distance covered.
+func moveFor(world *state.World, ms *BasicMovementSystem, id state.EntityID, input state.Input, seconds float64) float64 {
+	start, _ := world.Position.Get(id)
+	for range int(math.Round(seconds / testTick)) {
+		world.SetInput(id, input)
+		world.SyncInputBuffer()
+		ms.Update(testTick)
+		world.ApplyCommands()
+	}
+	end, _ := world.Position.Get(id)
+	return vector.Vector2D(start).DistanceTo(vector.Vector2D(end))
+}
+
+var (
+	walkInput   = state.Input{MovementType: state.MovementTypeAbsolute, MoveHorizontal: 1}
+	sprintInput = state.Input{MovementType: state.MovementTypeAbsolute, MoveHorizontal: 1, Sprint: true}
+)
+
+func TestStamina_SprintIsFasterAndDrains(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	giveStamina(world, playerID, state.Stamina{Current: 100, Max: 100})
+	ms := NewBasicMovementSystem(world)
+
+	walked := moveFor(world, ms, playerID, walkInput, 1)
+	sprinted := moveFor(world, ms, playerID, sprintInput, 1)
+
+	if !floatEquals(sprinted, walked*sprintSpeedMultiplier, 0.01) {
+		t.Errorf("Expected sprint distance %.2f, got %.2f", walked*sprintSpeedMultiplier, sprinted)
+	}
+	if stamina, _ := world.Stamina.Get(playerID); !floatEquals(stamina.Current, 100-staminaDrainRate, 0.01) {
+		t.Errorf("Expected stamina %.2f after one second of sprint, got %.2f", 100-staminaDrainRate, stamina.Current)
+	}
+}
+
+func TestStamina_ExhaustionForcesWalkUntilRecovered(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	giveStamina(world, playerID, state.Stamina{Current: 5, Max: 100})
+	ms := NewBasicMovementSystem(world)
+
+	moveFor(world, ms, playerID, sprintInput, 0.5)
+	stamina, _ := world.Stamina.Get(playerID)
+	if !stamina.Exhausted {
+		t.Fatalf("Expected exhausted after running out of stamina, got %+v", stamina)
+	}
+
+	// still holding sprint, but only walking and regenerating
+	sprinted := moveFor(world, ms, playerID, sprintInput, 1)
+	walked := moveFor(world, ms, playerID, walkInput, 1)
+	if !floatEquals(sprinted, walked, 0.01) {
+		t.Errorf("Expected walking speed while exhausted, moved %.2f instead of %.2f", sprinted, walked)
+	}
+	if stamina, _ := world.Stamina.Get(playerID); !stamina.Exhausted {
+		t.Errorf("Expected exhaustion to last below the recover threshold, got %+v", stamina)
+	}
+
+	// standing still regains the rest quickly
+	moveFor(world, ms, playerID, state.Input{}, 1)
+	if stamina, _ := world.Stamina.Get(playerID); stamina.Exhausted {
+		t.Errorf("Expected to recover after resting, got %+v", stamina)
+	}
+}
+
+func TestStamina_IdleRegeneratesFasterThanWalking(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	idleID := addPlayer(world, state.Position{X: 10, Y: 20})
+	giveStamina(world, playerID, state.Stamina{Current: 50, Max: 100})
+	giveStamina(world, idleID, state.Stamina{Current: 50, Max: 100})
+	ms := NewBasicMovementSystem(world)
+
+	for range 30 {
+		world.SetInput(playerID, walkInput)
+		world.SyncInputBuffer()
+		ms.Update(testTick)
+		world.ApplyCommands()
+	}
+
+	walking, _ := world.Stamina.Get(playerID)
+	idle, _ := world.Stamina.Get(idleID)
+	if idle.Current <= walking.Current {
+		t.Errorf("Expected idle regen above walking regen, got idle %.2f walking %.2f", idle.Current, walking.Current)
+	}
+	if idle.Current > idle.Max || walking.Current > walking.Max {
+		t.Errorf("Expected stamina capped at the maximum")
+	}
+}
+
+func TestStamina_NoSprintWithoutStamina(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	ms := NewBasicMovementSystem(world)
+
+	walked := moveFor(world, ms, playerID, walkInput, 0.5)
+	sprinted := moveFor(world, ms, playerID, sprintInput, 0.5)
+	if !floatEquals(sprinted, walked, 0.01) {
+		t.Errorf("Expected entities without stamina to walk, moved %.2f instead of %.2f", sprinted, walked)
+	}
+}
+
+func TestCombat_CannotFireWhileSprinting(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	armPlayer(world, shooterID, testWeapon)
+	giveStamina(world, shooterID, state.Stamina{Current: 100, Max: 100})
+	cs := NewCombatSystem(world)
+
+	world.ClearCombatEvents()
+	world.SetInput(shooterID, state.Input{MovementType: state.MovementTypeRelative, MoveVertical: 1, Sprint: true, Fire: true})
+	world.SyncInputBuffer()
+	cs.Update(testTick)
+	world.ApplyCommands()
+
+	if health, _ := world.Health.Get(targetID); health != 100 {
+		t.Errorf("Expected no shot while sprinting, target health %d", health)
+	}
+
+	fire(world, cs, shooterID)
+	if health, _ := world.Health.Get(targetID); health == 100 {
+		t.Errorf("Expected the shot to land once walking")
+	}
+}
@@ -0,0 +1,107 @@
+package system
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"survival/internal/engine/state"
+)
+
+func setTeam(world *state.World, id state.EntityID, team state.Team) {
+	meta, _ := world.EntityMeta.Get(id)
+	world.UpdatePlayer(id, state.UpdatePlayer{
+		UpdateMeta: state.ComponentMeta | state.ComponentTeam,
+		Meta:       meta.Set(state.ComponentTeam),
+		Team:       team,
+	})
+	world.ApplyCommands()
+}
+
+func TestCombat_FriendlyFireRules(t *testing.T) {
+	tests := []struct {
+		rule          FriendlyFire
+		targetHealth  state.Health
+		shooterHealth state.Health
+	}{
+		{FriendlyFireOff, 100, 100},
+		{FriendlyFireOn, 60, 100},
+		{FriendlyFireReflected, 100, 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rule), func(t *testing.T) {
+			world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+			targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+			setTeam(world, shooterID, 1)
+			setTeam(world, targetID, 1)
+			armPlayer(world, shooterID, testWeapon)
+			cs := NewCombatSystem(world)
+			cs.SetFriendlyFire(tt.rule)
+
+			fire(world, cs, shooterID)
+
+			if health, _ := world.Health.Get(targetID); health != tt.targetHealth {
+				t.Errorf("Expected teammate health %d, got %d", tt.targetHealth, health)
+			}
+			if health, _ := world.Health.Get(shooterID); health != tt.shooterHealth {
+				t.Errorf("Expected shooter health %d, got %d", tt.shooterHealth, health)
+			}
+		})
+	}
+}
+
+func TestCombat_FriendlyFireOffStillHitsOtherTeam(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	setTeam(world, shooterID, 1)
+	setTeam(world, targetID, 2)
+	armPlayer(world, shooterID, testWeapon)
+
+	fire(world, NewCombatSystem(world), shooterID)
+
+	if health, _ := world.Health.Get(targetID); health != 60 {
+		t.Errorf("Expected the other team to take damage, got health %d", health)
+	}
+}
+
+func TestVisibility_TeammatesAlwaysVisible(t *testing.T) {
+	world, viewerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	teammateID := addPlayer(world, state.Position{X: 40, Y: 50})
+	enemyID := addPlayer(world, state.Position{X: 40, Y: 60})
+	addWall(world, 25, 55, 1, 20)
+	setTeam(world, viewerID, 1)
+	setTeam(world, teammateID, 1)
+	setTeam(world, enemyID, 2)
+
+	updateVisibility(world)
+
+	viewIDs, _ := world.ViewIDs.Get(viewerID)
+	if !slices.Contains(viewIDs, teammateID) {
+		t.Errorf("Expected teammate %d behind the wall to be visible, got %v", teammateID, viewIDs)
+	}
+	if slices.Contains(viewIDs, enemyID) {
+		t.Errorf("Expected enemy %d behind the wall to be hidden, got %v", enemyID, viewIDs)
+	}
+}
+
+func TestRespawn_UsesTeamSpawnPoints(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	setTeam(world, playerID, 2)
+	world.UpdatePlayer(playerID, state.UpdatePlayer{UpdateMeta: state.ComponentHealth, Health: 0})
+	world.ApplyCommands()
+
+	teamSpawn := state.Position{X: 80, Y: 20}
+	rs := NewRespawnSystem(world, []SpawnPoint{
+		{Position: state.Position{X: 20, Y: 20}, Team: 1},
+		{Position: state.Position{X: 50, Y: 50}},
+		{Position: teamSpawn, Team: 2},
+	}, 1, 100)
+
+	rs.Update(1)
+	world.ApplyCommands()
+
+	if pos, _ := world.Position.Get(playerID); pos != teamSpawn {
+		t.Errorf("Expected respawn at the team spawn %v, got %v", teamSpawn, pos)
+	}
+}
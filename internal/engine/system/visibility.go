@@ -0,0 +1,157 @@
+package system
+
+import (
+	"math"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+const (
+	defaultViewDistance = 60.0
+	// minDarkVisibility is the fraction of the view distance a target standing
+	// in complete darkness can still be seen from.
+	minDarkVisibility = 0.25
+)
+
+// VisibilitySystem fills the ViewIDs of every player with the other players it
+// can see. Only targets on the viewer's floor are considered. They must be in
+// line of sight, and the distance they can be seen from shrinks with the light
+// level at their position. Living teammates are always visible.
+type VisibilitySystem struct {
+	world        *state.World
+	viewDistance float64
+}
+
+func NewVisibilitySystem(world *state.World) *VisibilitySystem {
+	return &VisibilitySystem{
+		world:        world,
+		viewDistance: defaultViewDistance,
+	}
+}
+
+func (vs *VisibilitySystem) ReadMeta() state.Meta {
+	return state.ComponentPosition | state.ComponentPlayerHitbox | state.ComponentViewIDs
+}
+
+func (vs *VisibilitySystem) WriteMeta() state.Meta {
+	return state.ComponentViewIDs
+}
+
+func (vs *VisibilitySystem) Update(dt float64) {
+	world := vs.world
+	requiredMeta := vs.ReadMeta()
+
+	for viewerID, meta := range world.EntityMeta.All() {
+		if !meta.Has(requiredMeta) {
+			continue
+		}
+
+		viewerPos, exist := world.Position.Get(viewerID)
+		if !exist {
+			continue
+		}
+
+		viewIDs := make(state.ViewIDs, 0)
+		for targetID, targetMeta := range world.EntityMeta.All() {
+			if targetID == viewerID || !targetMeta.Has(state.ComponentPosition|state.ComponentPlayerHitbox) || world.IsDead(targetID) ||
+				!world.SameFloor(viewerID, targetID) {
+				continue
+			}
+
+			targetPos, exist := world.Position.Get(targetID)
+			if !exist {
+				continue
+			}
+
+			if world.Teammates(viewerID, targetID) || vs.canSee(world.FloorOf(viewerID), viewerPos, targetPos) {
+				viewIDs = append(viewIDs, targetID)
+			}
+		}
+
+		world.UpdatePlayer(viewerID, state.UpdatePlayer{
+			UpdateMeta: state.ComponentViewIDs,
+			ViewIDs:    viewIDs,
+		})
+	}
+}
+
+func (vs *VisibilitySystem) canSee(floor state.Floor, from, to state.Position) bool {
+	light := vs.world.LightMap.LevelAt(to.X, to.Y)
+	maxDist := vs.viewDistance * (minDarkVisibility + (1-minDarkVisibility)*light)
+
+	if vector.Vector2D(from).DistanceTo(vector.Vector2D(to)) > maxDist {
+		return false
+	}
+
+	return hasLineOfSight(vs.world, floor, vector.Vector2D(from), vector.Vector2D(to))
+}
+
+// hasLineOfSight reports whether the segment between two points is free of
+// the static colliders of a floor.
+func hasLineOfSight(world *state.World, floor state.Floor, from, to vector.Vector2D) bool {
+	grid := world.FloorGrid(floor)
+	if grid == nil {
+		return true
+	}
+	bounds := state.Bounds{
+		MinX: math.Min(from.X, to.X), MinY: math.Min(from.Y, to.Y),
+		MaxX: math.Max(from.X, to.X), MaxY: math.Max(from.Y, to.Y),
+	}
+
+	for _, cell := range grid.CellsInBounds(bounds) {
+		for _, entry := range cell.Entries {
+			if !entry.Layer.Has(state.LayerStatic) {
+				continue
+			}
+
+			collider, exist := world.Collider.Get(entry.EntityID)
+			if !exist {
+				continue
+			}
+
+			boxMin, boxMax := collider.BoundingBox()
+			if segmentIntersectsAABB(from, to, boxMin, boxMax) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// segmentIntersectsAABB uses the slab method to test a segment against an axis aligned box.
+func segmentIntersectsAABB(from, to, boxMin, boxMax vector.Vector2D) bool {
+	_, hit := segmentEntryAABB(from, to, boxMin, boxMax)
+	return hit
+}
+
+// segmentEntryAABB returns where along the segment, from 0 at from to 1 at to,
+// it enters an axis aligned box, 0 when it starts inside.
+func segmentEntryAABB(from, to, boxMin, boxMax vector.Vector2D) (float64, bool) {
+	d := to.Sub(from)
+	tMin, tMax := 0.0, 1.0
+
+	for _, axis := range [2]struct{ origin, delta, min, max float64 }{
+		{from.X, d.X, boxMin.X, boxMax.X},
+		{from.Y, d.Y, boxMin.Y, boxMax.Y},
+	} {
+		if math.Abs(axis.delta) < 1e-12 {
+			if axis.origin < axis.min || axis.origin > axis.max {
+				return 0, false
+			}
+			continue
+		}
+
+		t1 := (axis.min - axis.origin) / axis.delta
+		t2 := (axis.max - axis.origin) / axis.delta
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = math.Max(tMin, t1)
+		tMax = math.Min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
@@ -0,0 +1,74 @@
+package system
+
+import (
+	"slices"
+	"testing"
+
+	"survival/internal/engine/state"
+)
+
+func addPlayer(world *state.World, pos state.Position) state.EntityID {
+	id, _ := world.CreatePlayer(state.CreatePlayer{
+		Position:      pos,
+		MovementSpeed: 5.0,
+		RotationSpeed: 2.0,
+		Radius:        0.5,
+		Health:        100,
+	})
+	world.ApplyCommands()
+	return id
+}
+
+func updateVisibility(world *state.World) {
+	ls := NewLightingSystem(world)
+	vs := NewVisibilitySystem(world)
+	ls.Update(1.0 / 60.0)
+	vs.Update(1.0 / 60.0)
+	world.ApplyCommands()
+}
+
+func TestVisibility_SeesLitPlayer(t *testing.T) {
+	world, viewerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+
+	updateVisibility(world)
+
+	viewIDs, _ := world.ViewIDs.Get(viewerID)
+	if !slices.Contains(viewIDs, targetID) {
+		t.Errorf("Expected target %d to be visible, got %v", targetID, viewIDs)
+	}
+}
+
+func TestVisibility_BlockedByWall(t *testing.T) {
+	world, viewerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	addWall(world, 25, 50, 1, 5)
+
+	updateVisibility(world)
+
+	viewIDs, _ := world.ViewIDs.Get(viewerID)
+	if slices.Contains(viewIDs, targetID) {
+		t.Errorf("Expected target %d to be hidden behind the wall, got %v", targetID, viewIDs)
+	}
+}
+
+func TestVisibility_DarknessReducesRange(t *testing.T) {
+	world, viewerID := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	world.AmbientLight = 0
+
+	updateVisibility(world)
+
+	viewIDs, _ := world.ViewIDs.Get(viewerID)
+	if slices.Contains(viewIDs, targetID) {
+		t.Errorf("Expected target %d in darkness to be hidden, got %v", targetID, viewIDs)
+	}
+
+	addLight(world, 40, 50, state.Light{Radius: 20, Intensity: 1, Enabled: true})
+	updateVisibility(world)
+
+	viewIDs, _ = world.ViewIDs.Get(viewerID)
+	if !slices.Contains(viewIDs, targetID) {
+		t.Errorf("Expected lit target %d to be visible, got %v", targetID, viewIDs)
+	}
+}
@@ -0,0 +1,80 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+func makeDestructible(world *state.World, wallID state.EntityID, health state.Health) {
+	world.Destructible.Upsert(wallID, state.Destructible{Health: health, MaxHealth: health})
+	world.EntityMeta.Upsert(wallID, state.DestructibleWallMeta)
+}
+
+func TestCombat_ShotsBreakDestructibleWall(t *testing.T) {
+	world, shooterID := setupTestWorld(state.Position{X: 10, Y: 50}, math.Pi/2)
+	targetID := addPlayer(world, state.Position{X: 40, Y: 50})
+	wallID := addWall(world, 25, 50, 1, 5)
+	makeDestructible(world, wallID, 60)
+	armPlayer(world, shooterID, testWeapon)
+	cs := NewCombatSystem(world)
+
+	world.ClearStaticChanges()
+	fire(world, cs, shooterID)
+	if wall, _ := world.Destructible.Get(wallID); wall.Health != 20 {
+		t.Fatalf("Expected the wall at 20 health, got %d", wall.Health)
+	}
+	if health, _ := world.Health.Get(targetID); health != 100 || countEvents(world, state.CombatHit) != 0 {
+		t.Errorf("Expected the wall to take the shot, got health %d", health)
+	}
+
+	weapon, _ := world.Weapon.Get(shooterID)
+	weapon.Cooldown = 0
+	armPlayer(world, shooterID, weapon)
+	fire(world, cs, shooterID)
+
+	if world.Entity.IsAlive(wallID) {
+		t.Fatal("Expected the broken wall removed")
+	}
+	changes := world.StaticChanges()
+	if len(changes) != 1 || changes[0].ID != wallID || !changes[0].Removed {
+		t.Errorf("Expected the wall removal recorded, got %+v", changes)
+	}
+	if !hasLineOfSight(world, state.GroundFloor, vector.Vector2D{X: 10, Y: 50}, vector.Vector2D{X: 40, Y: 50}) {
+		t.Error("Expected the wall gone from the grid")
+	}
+
+	weapon, _ = world.Weapon.Get(shooterID)
+	weapon.Cooldown = 0
+	armPlayer(world, shooterID, weapon)
+	fire(world, cs, shooterID)
+	if health, _ := world.Health.Get(targetID); health != 60 {
+		t.Errorf("Expected the shot through the gap to hit, got health %d", health)
+	}
+}
+
+func TestCombat_QueuedDamageBreaksWallButNotPlainWalls(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 10, Y: 50}, 0)
+	destructibleID := addWall(world, 25, 50, 1, 5)
+	makeDestructible(world, destructibleID, 50)
+	plainID := addWall(world, 75, 50, 1, 5)
+	cs := NewCombatSystem(world)
+
+	// an explosion catching both walls
+	world.QueueDamage(state.Damage{Victim: destructibleID, Amount: 80})
+	world.QueueDamage(state.Damage{Victim: plainID, Amount: 80})
+	cs.Update(1.0 / 60.0)
+	world.ApplyCommands()
+
+	if world.Entity.IsAlive(destructibleID) {
+		t.Error("Expected the destructible wall removed")
+	}
+	if !world.Entity.IsAlive(plainID) {
+		t.Error("Expected the plain wall to stay")
+	}
+	if len(world.CombatEvents()) != 0 {
+		t.Errorf("Expected walls not to count as hits, got %v", world.CombatEvents())
+	}
+}
@@ -0,0 +1,213 @@
+package system
+
+import (
+	"math/rand/v2"
+
+	"survival/internal/engine/state"
+)
+
+// WaveConfig describes the waves of a survival game.
+type WaveConfig struct {
+	TotalWaves     int
+	Countdown      float64 // seconds between the end of a wave and the next one
+	EndDelay       float64 // seconds the won or lost result is shown before the game resets
+	BaseEnemies    int
+	EnemiesPerWave int     // enemies added every wave
+	HealthPerWave  float64 // fraction of the base enemy health added every wave
+	PlayerHealth   state.Health
+
+	// Enemy is the body of a spawned enemy. Position is replaced by a spawn point
+	// and Health is the base health scaled by the wave.
+	Enemy state.CreatePlayer
+}
+
+// WaveSystem runs the survival game loop. Enemies spawn in waves from the enemy
+// spawn points after a countdown, growing in number and health every wave.
+// Players win after the last wave is cleared and lose when all of them are dead.
+// Either way the game resets after a short delay, reviving every player.
+type WaveSystem struct {
+	world        *state.World
+	config       WaveConfig
+	enemySpawns  []state.Position
+	playerSpawns []state.Position
+	enemies      map[state.EntityID]struct{}
+}
+
+func NewWaveSystem(world *state.World, config WaveConfig, enemySpawns, playerSpawns []state.Position) *WaveSystem {
+	world.Survival = state.SurvivalState{TotalWaves: config.TotalWaves}
+
+	return &WaveSystem{
+		world:        world,
+		config:       config,
+		enemySpawns:  enemySpawns,
+		playerSpawns: playerSpawns,
+		enemies:      make(map[state.EntityID]struct{}),
+	}
+}
+
+func (ws *WaveSystem) ReadMeta() state.Meta {
+	return state.ComponentHealth | state.ComponentPlayerHitbox | state.ComponentEnemy
+}
+
+func (ws *WaveSystem) WriteMeta() state.Meta {
+	return state.ComponentHealth | state.ComponentPosition | state.ComponentPrePosition | state.ComponentPlayerHitbox |
+		state.ComponentFloor | state.ComponentVelocity
+}
+
+func (ws *WaveSystem) Update(dt float64) {
+	survival := &ws.world.Survival
+	ws.removeDeadEnemies()
+
+	alive, total := ws.countPlayers()
+	if total == 0 && survival.Phase != state.SurvivalWaiting {
+		ws.reset()
+		return
+	}
+
+	switch survival.Phase {
+	case state.SurvivalWaiting:
+		if total > 0 {
+			ws.startCountdown(1)
+		}
+
+	case state.SurvivalCountdown, state.SurvivalWave:
+		if alive == 0 {
+			survival.Phase = state.SurvivalLost
+			survival.Countdown = ws.config.EndDelay
+			break
+		}
+
+		if survival.Phase == state.SurvivalCountdown {
+			survival.Countdown -= dt
+			if survival.Countdown <= 0 {
+				ws.spawnWave(survival.Wave)
+			}
+			break
+		}
+
+		if len(ws.enemies) == 0 {
+			if survival.Wave >= survival.TotalWaves {
+				survival.Phase = state.SurvivalWon
+				survival.Countdown = ws.config.EndDelay
+			} else {
+				ws.startCountdown(survival.Wave + 1)
+			}
+		}
+
+	case state.SurvivalWon, state.SurvivalLost:
+		survival.Countdown -= dt
+		if survival.Countdown <= 0 {
+			ws.reset()
+		}
+	}
+
+	survival.EnemiesRemaining = len(ws.enemies)
+}
+
+func (ws *WaveSystem) startCountdown(wave int) {
+	survival := &ws.world.Survival
+	survival.Phase = state.SurvivalCountdown
+	survival.Wave = wave
+	survival.Countdown = ws.config.Countdown
+}
+
+func (ws *WaveSystem) spawnWave(wave int) {
+	survival := &ws.world.Survival
+	survival.Phase = state.SurvivalWave
+	survival.Countdown = 0
+
+	spawns := ws.enemySpawns
+	if len(spawns) == 0 {
+		spawns = ws.playerSpawns
+	}
+	if len(spawns) == 0 {
+		return
+	}
+
+	count, health := ws.WaveSize(wave)
+	for i := range count {
+		cfg := ws.config.Enemy
+		cfg.Position = spawns[i%len(spawns)]
+		cfg.Health = health
+
+		id, ok := ws.world.CreateEnemy(cfg)
+		if !ok {
+			// TODO: log error
+			continue
+		}
+		ws.enemies[id] = struct{}{}
+	}
+}
+
+// WaveSize returns the number of enemies of a wave and the health each of them spawns with.
+func (ws *WaveSystem) WaveSize(wave int) (int, state.Health) {
+	growth := float64(max(wave-1, 0))
+	count := ws.config.BaseEnemies + ws.config.EnemiesPerWave*int(growth)
+	health := float64(ws.config.Enemy.Health) * (1 + ws.config.HealthPerWave*growth)
+	return count, state.Health(health)
+}
+
+// removeDeadEnemies destroys killed enemies and forgets the ones removed elsewhere.
+func (ws *WaveSystem) removeDeadEnemies() {
+	world := ws.world
+	for id := range ws.enemies {
+		if !world.Entity.IsAlive(id) {
+			delete(ws.enemies, id)
+			continue
+		}
+		if world.IsDead(id) {
+			world.QueueDestroy(id)
+			delete(ws.enemies, id)
+		}
+	}
+}
+
+// countPlayers counts the living and all non-enemy players.
+func (ws *WaveSystem) countPlayers() (alive, total int) {
+	world := ws.world
+	for id, meta := range world.EntityMeta.All() {
+		if !meta.Has(state.ComponentPlayerHitbox|state.ComponentHealth) || meta.Has(state.ComponentEnemy) {
+			continue
+		}
+		total++
+		if !world.IsDead(id) {
+			alive++
+		}
+	}
+	return alive, total
+}
+
+// reset removes all enemies and revives every player at a spawn point.
+func (ws *WaveSystem) reset() {
+	world := ws.world
+
+	for id := range ws.enemies {
+		world.QueueDestroy(id)
+		delete(ws.enemies, id)
+	}
+
+	for id, meta := range world.EntityMeta.All() {
+		if !meta.Has(state.ComponentPlayerHitbox|state.ComponentHealth) || meta.Has(state.ComponentEnemy) {
+			continue
+		}
+
+		update := state.UpdatePlayer{
+			UpdateMeta: state.ComponentHealth,
+			Health:     ws.config.PlayerHealth,
+		}
+		if len(ws.playerSpawns) > 0 {
+			hitbox, _ := world.PlayerHitbox.Get(id)
+			spawn := ws.playerSpawns[rand.IntN(len(ws.playerSpawns))]
+			update.UpdateMeta |= state.ComponentPosition | state.ComponentPrePosition | state.ComponentPlayerHitbox
+			update.Position = spawn
+			update.PrePosition = state.PrePosition(spawn)
+			update.PlayerHitbox = state.PlayerHitbox{Center: spawn, Radius: hitbox.Radius}
+			spawnOnGroundFloor(world, id, &update)
+			spawnAtRest(world, id, &update)
+		}
+		world.UpdatePlayer(id, update)
+		ProtectSpawn(world, id)
+	}
+
+	world.Survival = state.SurvivalState{TotalWaves: ws.config.TotalWaves}
+}
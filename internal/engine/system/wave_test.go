@@ -0,0 +1,223 @@
+package system
+
+import (
+	"testing"
+
+	"survival/internal/engine/pathfinding"
+	"survival/internal/engine/state"
+)
+
+var testWaveConfig = WaveConfig{
+	TotalWaves:     2,
+	Countdown:      1,
+	EndDelay:       1,
+	BaseEnemies:    2,
+	EnemiesPerWave: 1,
+	HealthPerWave:  0.5,
+	PlayerHealth:   100,
+	Enemy: state.CreatePlayer{
+		MovementSpeed: 3,
+		RotationSpeed: 3,
+		Radius:        0.5,
+		Health:        40,
+		Weapon:        state.Weapon{Damage: 10, Range: 1.5, FireInterval: 1},
+	},
+}
+
+func updateWaves(world *state.World, ws *WaveSystem, dt float64) {
+	ws.Update(dt)
+	world.ApplyCommands()
+}
+
+func killEnemies(world *state.World) {
+	for id := range world.Enemy.All() {
+		world.UpdatePlayer(id, state.UpdatePlayer{UpdateMeta: state.ComponentHealth, Health: 0})
+	}
+	world.ApplyCommands()
+}
+
+func countEnemies(world *state.World) int {
+	count := 0
+	for range world.Enemy.All() {
+		count++
+	}
+	return count
+}
+
+func TestWave_SizeScalesWithWave(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	ws := NewWaveSystem(world, testWaveConfig, nil, nil)
+
+	if count, health := ws.WaveSize(1); count != 2 || health != 40 {
+		t.Errorf("Wave 1: expected 2 enemies with 40 health, got %d with %d", count, health)
+	}
+	if count, health := ws.WaveSize(3); count != 4 || health != 80 {
+		t.Errorf("Wave 3: expected 4 enemies with 80 health, got %d with %d", count, health)
+	}
+}
+
+func TestWave_CountdownSpawnsEnemies(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	spawns := []state.Position{{X: 10, Y: 10}}
+	ws := NewWaveSystem(world, testWaveConfig, spawns, nil)
+
+	updateWaves(world, ws, 0.1)
+	if world.Survival.Phase != state.SurvivalCountdown || world.Survival.Wave != 1 {
+		t.Fatalf("Expected countdown to wave 1, got %+v", world.Survival)
+	}
+
+	updateWaves(world, ws, 1)
+	if world.Survival.Phase != state.SurvivalWave {
+		t.Fatalf("Expected wave to start, got %+v", world.Survival)
+	}
+	if world.Survival.EnemiesRemaining != 2 {
+		t.Errorf("Expected 2 enemies remaining, got %d", world.Survival.EnemiesRemaining)
+	}
+	for id := range world.Enemy.All() {
+		if pos, _ := world.Position.Get(id); pos != spawns[0] {
+			t.Errorf("Expected enemy %d at the enemy spawn, got %v", id, pos)
+		}
+	}
+}
+
+func TestWave_ClearingLastWaveWins(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	ws := NewWaveSystem(world, testWaveConfig, []state.Position{{X: 10, Y: 10}}, nil)
+
+	for wave := 1; wave <= testWaveConfig.TotalWaves; wave++ {
+		updateWaves(world, ws, 0.1)
+		updateWaves(world, ws, 1)
+		if world.Survival.Phase != state.SurvivalWave || world.Survival.Wave != wave {
+			t.Fatalf("Expected wave %d, got %+v", wave, world.Survival)
+		}
+		killEnemies(world)
+		updateWaves(world, ws, 0.1)
+	}
+
+	if world.Survival.Phase != state.SurvivalWon {
+		t.Fatalf("Expected players to win, got %+v", world.Survival)
+	}
+	if countEnemies(world) != 0 {
+		t.Errorf("Expected dead enemies to be removed")
+	}
+}
+
+func TestWave_AllPlayersDeadResets(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	playerSpawn := state.Position{X: 80, Y: 80}
+	ws := NewWaveSystem(world, testWaveConfig, []state.Position{{X: 10, Y: 10}}, []state.Position{playerSpawn})
+
+	updateWaves(world, ws, 0.1)
+	updateWaves(world, ws, 1)
+
+	world.UpdatePlayer(playerID, state.UpdatePlayer{UpdateMeta: state.ComponentHealth, Health: 0})
+	world.ApplyCommands()
+
+	updateWaves(world, ws, 0.1)
+	if world.Survival.Phase != state.SurvivalLost {
+		t.Fatalf("Expected the game to be lost, got %+v", world.Survival)
+	}
+
+	updateWaves(world, ws, 1)
+	if world.Survival.Phase != state.SurvivalWaiting || world.Survival.Wave != 0 {
+		t.Fatalf("Expected the game to reset, got %+v", world.Survival)
+	}
+	if world.IsDead(playerID) {
+		t.Errorf("Expected the player to be revived")
+	}
+	if pos, _ := world.Position.Get(playerID); pos != playerSpawn {
+		t.Errorf("Expected the player back at %v, got %v", playerSpawn, pos)
+	}
+	if countEnemies(world) != 0 {
+		t.Errorf("Expected enemies to be removed on reset")
+	}
+}
+
+func TestEnemy_ChasesAndAttacksNearestPlayer(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 20}, 0)
+	addPlayer(world, state.Position{X: 90, Y: 90})
+	cfg := testWaveConfig.Enemy
+	cfg.Position = state.Position{X: 50, Y: 60}
+	enemyID, _ := world.CreateEnemy(cfg)
+	world.ApplyCommands()
+	es := NewEnemySystem(world, pathfinding.NewPathfinder(world))
+
+	es.Update(1.0 / 60.0)
+	world.ApplyCommands()
+	world.SyncInputBuffer()
+
+	enemy, _ := world.Enemy.Get(enemyID)
+	if enemy.Target != playerID {
+		t.Fatalf("Expected enemy to target the nearest player %d, got %d", playerID, enemy.Target)
+	}
+	input, _ := world.Input.Get(enemyID)
+	if input.MoveVertical >= 0 || input.Fire {
+		t.Errorf("Expected enemy to walk towards the player without attacking, got %+v", input)
+	}
+
+	// in melee range the enemy attacks instead
+	world.UpdatePlayer(enemyID, state.UpdatePlayer{UpdateMeta: state.ComponentPosition, Position: state.Position{X: 50, Y: 21}})
+	world.ApplyCommands()
+	es.Update(1.0 / 60.0)
+	world.ApplyCommands()
+	world.SyncInputBuffer()
+
+	if input, _ := world.Input.Get(enemyID); !input.Fire {
+		t.Errorf("Expected enemy in range to attack, got %+v", input)
+	}
+}
+
+func TestEnemy_SharesFlowFieldUntilRepath(t *testing.T) {
+	world, playerID := setupTestWorld(state.Position{X: 50, Y: 20}, 0)
+	for _, pos := range []state.Position{{X: 50, Y: 60}, {X: 30, Y: 70}} {
+		cfg := testWaveConfig.Enemy
+		cfg.Position = pos
+		world.CreateEnemy(cfg)
+	}
+	world.ApplyCommands()
+	es := NewEnemySystem(world, pathfinding.NewPathfinder(world))
+	update := func() {
+		es.Update(1.0 / 60.0)
+		world.ApplyCommands()
+	}
+
+	update()
+	if len(es.fields) != 1 {
+		t.Fatalf("Expected both enemies to share one flow field, got %d", len(es.fields))
+	}
+	field := es.fields[enemyFieldKey{target: playerID, radius: testWaveConfig.Enemy.Radius}].field
+
+	moved := state.Position{X: 50, Y: 35}
+	world.UpdatePlayer(playerID, state.UpdatePlayer{UpdateMeta: state.ComponentPosition, Position: moved})
+	world.ApplyCommands()
+	update()
+	if cached := es.fields[enemyFieldKey{target: playerID, radius: testWaveConfig.Enemy.Radius}]; cached.field != field {
+		t.Errorf("Expected the field to be reused within the repath interval")
+	}
+
+	for range int(enemyRepathInterval * 60) {
+		update()
+	}
+	if cached := es.fields[enemyFieldKey{target: playerID, radius: testWaveConfig.Enemy.Radius}]; cached.field.Target() != moved {
+		t.Errorf("Expected the field to lead to %v after the repath interval, got %v", moved, cached.field.Target())
+	}
+}
+
+func TestEnemy_HasOnlyEnemyComponents(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 50, Y: 20}, 0)
+	cfg := testWaveConfig.Enemy
+	cfg.Position = state.Position{X: 50, Y: 60}
+	enemyID, _ := world.CreateEnemy(cfg)
+	world.ApplyCommands()
+
+	if meta, _ := world.EntityMeta.Get(enemyID); meta&^state.ComponentVelocity != state.EnemyMeta {
+		t.Errorf("Expected enemy meta %b, got %b", state.EnemyMeta, meta)
+	}
+	_, hasLight := world.Light.Get(enemyID)
+	_, hasHearing := world.Hearing.Get(enemyID)
+	_, hasInventory := world.Inventory.Get(enemyID)
+	_, hasViewIDs := world.ViewIDs.Get(enemyID)
+	if hasLight || hasHearing || hasInventory || hasViewIDs {
+		t.Errorf("Expected enemy without light, hearing, inventory and view, got %v %v %v %v", hasLight, hasHearing, hasInventory, hasViewIDs)
+	}
+}
@@ -0,0 +1,165 @@
+package system
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"survival/internal/engine/state"
+)
+
+// zoneDamageInterval is how often players outside the safe zone take damage, in seconds.
+const zoneDamageInterval = 1.0
+
+// ZoneStage is one shrink of the safe zone. The zone waits Wait seconds, then
+// shrinks over Shrink seconds to Radius, a fraction of the radius it started
+// the game with. Players outside take Damage every second from the start of
+// the stage, the last stage's damage stays once the zone stopped shrinking.
+type ZoneStage struct {
+	Wait   float64
+	Shrink float64
+	Radius float64
+	Damage int
+}
+
+// ZoneSystem runs the safe zone of a battle royale game. Once started the
+// zone covers the whole map and shrinks in stages, each one towards a random
+// point inside the current zone and the map. Players outside take growing
+// damage, queued for the combat system, so it has to run before it. The zone
+// does nothing until Start is called.
+type ZoneSystem struct {
+	world  *state.World
+	stages []ZoneStage
+	rng    *rand.Rand
+
+	stage      int
+	fromCenter state.Position
+	fromRadius float64
+	damageIn   float64
+}
+
+func NewZoneSystem(world *state.World, stages []ZoneStage) *ZoneSystem {
+	return &ZoneSystem{
+		world:  world,
+		stages: stages,
+		rng:    rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+func (zs *ZoneSystem) ReadMeta() state.Meta {
+	return state.ComponentPosition | state.ComponentHealth | state.ComponentPlayerHitbox
+}
+
+func (zs *ZoneSystem) WriteMeta() state.Meta {
+	return state.ComponentHealth
+}
+
+// Start resets the zone to cover the whole map and begins the first stage.
+func (zs *ZoneSystem) Start() {
+	world := zs.world
+	world.Zone = state.ZoneState{
+		Active: true,
+		Center: state.Position{X: world.Width / 2, Y: world.Height / 2},
+		Radius: math.Hypot(world.Width, world.Height) / 2,
+	}
+	zs.damageIn = zoneDamageInterval
+	zs.beginStage(0)
+}
+
+// Stop removes the zone, nobody takes zone damage until it is started again.
+func (zs *ZoneSystem) Stop() {
+	zs.world.Zone = state.ZoneState{}
+}
+
+func (zs *ZoneSystem) Update(dt float64) {
+	if !zs.world.Zone.Active {
+		return
+	}
+	zs.advance(dt)
+	zs.damageOutside(dt)
+}
+
+func (zs *ZoneSystem) beginStage(stage int) {
+	zone := &zs.world.Zone
+	zs.stage = stage
+	zone.Shrinking = false
+
+	if stage >= len(zs.stages) {
+		zone.Timer = 0
+		zone.NextCenter = zone.Center
+		zone.NextRadius = zone.Radius
+		return
+	}
+
+	startRadius := math.Hypot(zs.world.Width, zs.world.Height) / 2
+	next := zs.stages[stage]
+	zone.Phase = stage + 1
+	zone.Damage = next.Damage
+	zone.Timer = next.Wait
+	zone.NextRadius = math.Min(next.Radius*startRadius, zone.Radius)
+	zone.NextCenter = zs.randomCenter(zone.Center, zone.Radius-zone.NextRadius)
+}
+
+// randomCenter picks a point at most maxOffset away from center that lies inside the map.
+func (zs *ZoneSystem) randomCenter(center state.Position, maxOffset float64) state.Position {
+	angle := zs.rng.Float64() * 2 * math.Pi
+	// the square root spreads the points evenly over the disc
+	offset := math.Sqrt(zs.rng.Float64()) * maxOffset
+
+	return state.Position{
+		X: math.Max(0, math.Min(zs.world.Width, center.X+math.Sin(angle)*offset)),
+		Y: math.Max(0, math.Min(zs.world.Height, center.Y-math.Cos(angle)*offset)),
+	}
+}
+
+func (zs *ZoneSystem) advance(dt float64) {
+	if zs.stage >= len(zs.stages) {
+		return
+	}
+
+	zone := &zs.world.Zone
+	stage := zs.stages[zs.stage]
+	zone.Timer -= dt
+
+	if !zone.Shrinking {
+		if zone.Timer > 0 {
+			return
+		}
+		zone.Shrinking = true
+		zone.Timer += stage.Shrink
+		zs.fromCenter, zs.fromRadius = zone.Center, zone.Radius
+	}
+
+	if zone.Timer > 0 {
+		t := 1 - zone.Timer/stage.Shrink
+		zone.Center = state.Position{
+			X: zs.fromCenter.X + (zone.NextCenter.X-zs.fromCenter.X)*t,
+			Y: zs.fromCenter.Y + (zone.NextCenter.Y-zs.fromCenter.Y)*t,
+		}
+		zone.Radius = zs.fromRadius + (zone.NextRadius-zs.fromRadius)*t
+		return
+	}
+
+	zone.Center, zone.Radius = zone.NextCenter, zone.NextRadius
+	zs.beginStage(zs.stage + 1)
+}
+
+func (zs *ZoneSystem) damageOutside(dt float64) {
+	zs.damageIn -= dt
+	if zs.damageIn > 0 {
+		return
+	}
+	zs.damageIn += zoneDamageInterval
+
+	world := zs.world
+	zone := world.Zone
+	requiredMeta := zs.ReadMeta()
+	for id, meta := range world.EntityMeta.All() {
+		if !meta.Has(requiredMeta) || world.IsDead(id) {
+			continue
+		}
+		if pos, _ := world.Position.Get(id); !zone.Contains(pos) {
+			// the zone is nobody's kill, dying to it counts as self-inflicted
+			world.QueueDamage(state.Damage{Attacker: id, Victim: id, Amount: zone.Damage})
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package system
+
+import (
+	"math"
+	"testing"
+
+	"survival/internal/engine/state"
+)
+
+var testZoneStages = []ZoneStage{
+	{Wait: 2, Shrink: 2, Radius: 0.5, Damage: 5},
+	{Wait: 2, Shrink: 2, Radius: 0.2, Damage: 10},
+}
+
+func updateZone(world *state.World, zs *ZoneSystem, seconds float64) {
+	const dt = 0.1
+	for range int(math.Round(seconds / dt)) {
+		zs.Update(dt)
+	}
+}
+
+func TestZone_IdleUntilStarted(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	zs := NewZoneSystem(world, testZoneStages)
+
+	updateZone(world, zs, 5)
+	if world.Zone.Active || len(world.QueuedDamage()) != 0 {
+		t.Errorf("Expected no zone before Start, got %+v", world.Zone)
+	}
+}
+
+func TestZone_ShrinksInStagesInsideTheMap(t *testing.T) {
+	world, _ := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	zs := NewZoneSystem(world, testZoneStages)
+	zs.Start()
+
+	startRadius := math.Hypot(world.Width, world.Height) / 2
+	zone := world.Zone
+	if !zone.Active || zone.Phase != 1 || zone.Radius != startRadius || zone.Timer != 2 {
+		t.Fatalf("Expected the first stage waiting with the map covered, got %+v", zone)
+	}
+	if zone.NextRadius != startRadius*0.5 {
+		t.Errorf("Expected the next radius at half the start, got %.1f", zone.NextRadius)
+	}
+
+	updateZone(world, zs, 3)
+	if !world.Zone.Shrinking || world.Zone.Radius >= startRadius || world.Zone.Radius <= zone.NextRadius {
+		t.Errorf("Expected the zone halfway through shrinking, got %+v", world.Zone)
+	}
+
+	updateZone(world, zs, 1.1)
+	if world.Zone.Phase != 2 || world.Zone.Shrinking || world.Zone.Radius != zone.NextRadius || world.Zone.Center != zone.NextCenter {
+		t.Fatalf("Expected the second stage waiting at the first target, got %+v", world.Zone)
+	}
+
+	next := world.Zone
+	if next.NextCenter.X < 0 || next.NextCenter.X > world.Width || next.NextCenter.Y < 0 || next.NextCenter.Y > world.Height {
+		t.Errorf("Expected the next center inside the map, got %v", next.NextCenter)
+	}
+	offset := math.Hypot(next.NextCenter.X-next.Center.X, next.NextCenter.Y-next.Center.Y)
+	if offset+next.NextRadius > next.Radius+1e-9 {
+		t.Errorf("Expected the next zone inside the current one, offset %.1f radius %.1f of %.1f", offset, next.NextRadius, next.Radius)
+	}
+
+	updateZone(world, zs, 5)
+	if world.Zone.Phase != 2 || world.Zone.Timer != 0 || world.Zone.Radius != next.NextRadius || world.Zone.Damage != 10 {
+		t.Errorf("Expected the zone to stay after the last stage, got %+v", world.Zone)
+	}
+}
+
+func TestZone_DamagesPlayersOutside(t *testing.T) {
+	world, insideID := setupTestWorld(state.Position{X: 50, Y: 50}, 0)
+	outsideID := addPlayer(world, state.Position{X: 5, Y: 5})
+	zs := NewZoneSystem(world, testZoneStages)
+	zs.Start()
+	world.Zone.Center = state.Position{X: 50, Y: 50}
+	world.Zone.Radius = 20
+
+	updateZone(world, zs, 1.2)
+
+	damage := world.QueuedDamage()
+	if len(damage) != 1 || damage[0].Victim != outsideID || damage[0].Amount != 5 || damage[0].Attacker != outsideID {
+		t.Fatalf("Expected 5 zone damage for the player outside, got %+v", damage)
+	}
+	for _, d := range damage {
+		if d.Victim == insideID {
+			t.Errorf("Expected no damage for the player inside")
+		}
+	}
+
+	zs.Stop()
+	world.ClearQueuedDamage()
+	updateZone(world, zs, 2)
+	if len(world.QueuedDamage()) != 0 {
+		t.Errorf("Expected no damage once the zone stopped")
+	}
+}
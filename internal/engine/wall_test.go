@@ -0,0 +1,56 @@
+package engine_test
+
+import (
+	"testing"
+
+	"survival/internal/engine"
+	"survival/internal/engine/ports"
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+func TestDestructibleWall_ShotOpensPath(t *testing.T) {
+	game, err := engine.NewGame(&engine.MapConfig{
+		Dimensions: vector.Vector2D{X: 100, Y: 100},
+		GridSize:   10,
+		Walls: []engine.WallConfig{
+			// splits the map in a north and a south half
+			{ID: "fence", Center: vector.Vector2D{X: 50, Y: 40}, HalfSize: vector.Vector2D{X: 50, Y: 1}, Destructible: true, Health: 20},
+		},
+		SpawnPoints: []engine.SpawnPoint{
+			{Position: vector.Vector2D{X: 50, Y: 50}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	playerID, err := game.JoinPlayer()
+	if err != nil {
+		t.Fatalf("Failed to join player: %v", err)
+	}
+
+	north := state.Position{X: 50, Y: 20}
+	if _, found := game.Pathfinder().FindPath(state.Position{X: 50, Y: 50}, north, 0.5); found {
+		t.Fatal("Expected the wall to block the way north")
+	}
+
+	// players spawn facing north, one shot of the default weapon breaks the wall
+	game.SetPlayerInput(playerID, ports.PlayerInput{Fire: true})
+	game.Update(1.0 / 60.0)
+
+	changes := game.StaticChanges()
+	if len(changes) != 1 || !changes[0].Removed {
+		t.Fatalf("Expected the wall removal reported, got %+v", changes)
+	}
+	if len(game.Statics()) != 0 {
+		t.Errorf("Expected no static colliders left, got %d", len(game.Statics()))
+	}
+	if _, found := game.Pathfinder().FindPath(state.Position{X: 50, Y: 50}, north, 0.5); !found {
+		t.Error("Expected a path north once the wall broke")
+	}
+
+	game.Update(1.0 / 60.0)
+	if len(game.StaticChanges()) != 0 {
+		t.Error("Expected the changes cleared on the next update")
+	}
+}
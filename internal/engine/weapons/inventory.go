@@ -0,0 +1,192 @@
+package weapons
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInventoryFull = errors.New("inventory is full")
+	ErrItemNotFound  = errors.New("item not found")
+	ErrNotEquippable = errors.New("item cannot be equipped")
+	ErrInvalidSlot   = errors.New("invalid slot")
+)
+
+// ItemKind tells which inventory category an item belongs to.
+type ItemKind string
+
+const (
+	ItemMelee    ItemKind = "melee"
+	ItemRanged   ItemKind = "ranged"
+	ItemMagazine ItemKind = "magazine"
+)
+
+// Item is anything an inventory holds, only the field matching Kind is set.
+// Items are moved between inventories and the ground as a whole.
+type Item struct {
+	Kind     ItemKind
+	Knife    Knife
+	Pistol   Pistol
+	Magazine Magazine
+}
+
+func (i Item) ID() string {
+	switch i.Kind {
+	case ItemMelee:
+		return i.Knife.ID
+	case ItemRanged:
+		return i.Pistol.ID
+	case ItemMagazine:
+		return i.Magazine.ID
+	default:
+		return ""
+	}
+}
+
+// Count returns the number of slots taken, a loaded magazine belongs to its pistol.
+func (inv *Inventory) Count() int {
+	return len(inv.MeleeWeapons) + len(inv.RangedWeapons) + len(inv.Magazines)
+}
+
+// Items lists the items in display order: melee weapons, ranged weapons, magazines.
+func (inv *Inventory) Items() []Item {
+	items := make([]Item, 0, inv.Count())
+	for _, knife := range inv.MeleeWeapons {
+		items = append(items, Item{Kind: ItemMelee, Knife: knife})
+	}
+	for _, pistol := range inv.RangedWeapons {
+		items = append(items, Item{Kind: ItemRanged, Pistol: pistol})
+	}
+	for _, magazine := range inv.Magazines {
+		items = append(items, Item{Kind: ItemMagazine, Magazine: magazine})
+	}
+	return items
+}
+
+func (inv *Inventory) Find(id string) (Item, bool) {
+	for _, item := range inv.Items() {
+		if item.ID() == id {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// Add puts the item at the end of its category. It fails once MaxSlots items
+// are carried, a MaxSlots of 0 means no limit.
+func (inv *Inventory) Add(item Item) error {
+	if inv.MaxSlots > 0 && inv.Count() >= inv.MaxSlots {
+		return ErrInventoryFull
+	}
+
+	switch item.Kind {
+	case ItemMelee:
+		inv.MeleeWeapons = append(inv.MeleeWeapons, item.Knife)
+	case ItemRanged:
+		inv.RangedWeapons = append(inv.RangedWeapons, item.Pistol)
+	case ItemMagazine:
+		inv.Magazines = append(inv.Magazines, item.Magazine)
+	default:
+		return fmt.Errorf("unknown item kind %q", item.Kind)
+	}
+	return nil
+}
+
+// Remove takes the item out of the inventory, unequipping it if needed.
+func (inv *Inventory) Remove(id string) (Item, bool) {
+	item, exist := inv.Find(id)
+	if !exist {
+		return Item{}, false
+	}
+
+	switch item.Kind {
+	case ItemMelee:
+		inv.MeleeWeapons = removeAt(inv.MeleeWeapons, indexOf(inv.MeleeWeapons, id, func(k Knife) string { return k.ID }))
+	case ItemRanged:
+		inv.RangedWeapons = removeAt(inv.RangedWeapons, indexOf(inv.RangedWeapons, id, func(p Pistol) string { return p.ID }))
+	case ItemMagazine:
+		inv.Magazines = removeAt(inv.Magazines, indexOf(inv.Magazines, id, func(m Magazine) string { return m.ID }))
+	}
+	if inv.Equipped == id {
+		inv.Equipped = ""
+	}
+	return item, true
+}
+
+// Move puts the item at slot, counted inside its own category.
+func (inv *Inventory) Move(id string, slot int) error {
+	item, exist := inv.Find(id)
+	if !exist {
+		return ErrItemNotFound
+	}
+
+	var err error
+	switch item.Kind {
+	case ItemMelee:
+		inv.MeleeWeapons, err = moveTo(inv.MeleeWeapons, indexOf(inv.MeleeWeapons, id, func(k Knife) string { return k.ID }), slot)
+	case ItemRanged:
+		inv.RangedWeapons, err = moveTo(inv.RangedWeapons, indexOf(inv.RangedWeapons, id, func(p Pistol) string { return p.ID }), slot)
+	case ItemMagazine:
+		inv.Magazines, err = moveTo(inv.Magazines, indexOf(inv.Magazines, id, func(m Magazine) string { return m.ID }), slot)
+	}
+	return err
+}
+
+// Equip makes the weapon the one in hand, magazines cannot be equipped.
+func (inv *Inventory) Equip(id string) error {
+	item, exist := inv.Find(id)
+	if !exist {
+		return ErrItemNotFound
+	}
+	if item.Kind == ItemMagazine {
+		return ErrNotEquippable
+	}
+	inv.Equipped = id
+	return nil
+}
+
+// EquippedItem returns the weapon in hand, false when empty handed.
+func (inv *Inventory) EquippedItem() (Item, bool) {
+	if inv.Equipped == "" {
+		return Item{}, false
+	}
+	return inv.Find(inv.Equipped)
+}
+
+// Clone returns a deep copy, so the copy can be changed without touching the original.
+func (inv Inventory) Clone() Inventory {
+	clone := inv
+	clone.MeleeWeapons = append([]Knife(nil), inv.MeleeWeapons...)
+	clone.RangedWeapons = append([]Pistol(nil), inv.RangedWeapons...)
+	clone.Magazines = append([]Magazine(nil), inv.Magazines...)
+	for i, pistol := range clone.RangedWeapons {
+		if pistol.CurrentMagazine != nil {
+			magazine := *pistol.CurrentMagazine
+			clone.RangedWeapons[i].CurrentMagazine = &magazine
+		}
+	}
+	return clone
+}
+
+func indexOf[T any](items []T, id string, idOf func(T) string) int {
+	for i, item := range items {
+		if idOf(item) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeAt[T any](items []T, index int) []T {
+	return append(items[:index], items[index+1:]...)
+}
+
+func moveTo[T any](items []T, from, to int) ([]T, error) {
+	if to < 0 || to >= len(items) {
+		return items, ErrInvalidSlot
+	}
+	item := items[from]
+	items = removeAt(items, from)
+	items = append(items[:to], append([]T{item}, items[to:]...)...)
+	return items, nil
+}
@@ -47,4 +47,6 @@ type Inventory struct {
 	RangedWeapons []Pistol
 	Magazines     []Magazine
 	MaxSlots      int
+	// Equipped is the ID of the weapon in hand, empty when empty handed.
+	Equipped string
 }
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidToken       = errors.New("invalid login token")
+	ErrTokenExpired       = errors.New("login token expired")
+)
+
+// DefaultTokenTTL is how long login tokens are valid, long enough for a
+// session of play including its reconnects.
+const DefaultTokenTTL = 12 * time.Hour
+
+// Identity is a user whose credentials were checked. UserID is bound to the
+// sessions of the user in place of a client supplied ID.
+type Identity struct {
+	UserID string
+	Name   string
+}
+
+// UserStore checks the credentials of users.
+type UserStore interface {
+	// Authenticate returns the identity of a user, ErrInvalidCredentials when
+	// the user is unknown or the password wrong.
+	Authenticate(username, password string) (Identity, error)
+}
+
+// tokenClaims are the signed contents of a login token.
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	Name      string `json:"name"`
+	ExpiresAt int64  `json:"exp"` // unix seconds
+}
+
+// TokenAuthority issues login tokens and verifies the ones clients present.
+// A token is its base64url encoded claims and their HMAC-SHA256 under the
+// server secret, joined by a dot, so only a server holding the secret can
+// issue tokens it accepts.
+type TokenAuthority struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewTokenAuthority creates an authority signing with the secret, issuing
+// tokens valid for ttl.
+func NewTokenAuthority(secret []byte, ttl time.Duration) (*TokenAuthority, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("token secret cannot be empty")
+	}
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+	return &TokenAuthority{secret: secret, ttl: ttl}, nil
+}
+
+// Issue returns a token for the identity valid from now on, and the time it expires.
+func (a *TokenAuthority) Issue(identity Identity, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(a.ttl).Truncate(time.Second)
+	claims, _ := json.Marshal(tokenClaims{
+		Subject:   identity.UserID,
+		Name:      identity.Name,
+		ExpiresAt: expiresAt.Unix(),
+	})
+
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(a.sign(payload)), expiresAt
+}
+
+// Verify returns the identity a token was issued for. Tokens not signed with
+// the secret of the authority are ErrInvalidToken, the ones past their expiry
+// ErrTokenExpired.
+func (a *TokenAuthority) Verify(token string, now time.Time) (Identity, error) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found {
+		return Identity{}, ErrInvalidToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, a.sign(payload)) {
+		return Identity{}, ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(data, &claims); err != nil || claims.Subject == "" {
+		return Identity{}, ErrInvalidToken
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return Identity{}, ErrTokenExpired
+	}
+
+	return Identity{UserID: claims.Subject, Name: claims.Name}, nil
+}
+
+func (a *TokenAuthority) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
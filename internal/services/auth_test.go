@@ -0,0 +1,52 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenAuthority_IssueAndVerify(t *testing.T) {
+	authority, err := NewTokenAuthority([]byte("secret"), time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create authority: %v", err)
+	}
+	now := time.Unix(1_000_000, 0)
+
+	token, expiresAt := authority.Issue(Identity{UserID: "alice", Name: "Alice"}, now)
+	if !expiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected the token to expire in an hour, got %v", expiresAt)
+	}
+
+	identity, err := authority.Verify(token, now.Add(59*time.Minute))
+	if err != nil || identity.UserID != "alice" || identity.Name != "Alice" {
+		t.Errorf("Verify() = %+v, %v, want alice", identity, err)
+	}
+	if _, err := authority.Verify(token, now.Add(time.Hour)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Expected the token expired after an hour, got %v", err)
+	}
+}
+
+func TestTokenAuthority_RejectsForgedTokens(t *testing.T) {
+	authority, _ := NewTokenAuthority([]byte("secret"), time.Hour)
+	other, _ := NewTokenAuthority([]byte("other secret"), time.Hour)
+	now := time.Unix(1_000_000, 0)
+
+	token, _ := authority.Issue(Identity{UserID: "alice"}, now)
+	forged, _ := other.Issue(Identity{UserID: "alice"}, now)
+	impostor, _ := authority.Issue(Identity{UserID: "mallory"}, now)
+	payload, signature, _ := strings.Cut(token, ".")
+	impostorPayload, _, _ := strings.Cut(impostor, ".")
+
+	for name, token := range map[string]string{
+		"other secret":    forged,
+		"tampered claims": impostorPayload + "." + signature,
+		"no signature":    payload,
+		"empty":           "",
+	} {
+		if _, err := authority.Verify(token, now); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
@@ -26,6 +26,8 @@ type Client interface {
 	Send(ctx context.Context, envelopeType ports.ResponseEnvelopeType, payload any) error
 	Subscribe(handler func(cmd ports.RequestCommand)) error
 	Errors() <-chan error
+	// RTT returns the round trip times of the connection.
+	RTT() ports.RTT
 	Close() error
 	IsClosed() bool
 }
@@ -105,6 +107,10 @@ func (c *websocketClient) SetSessionID(sessionID string) error {
 	return nil
 }
 
+func (c *websocketClient) RTT() ports.RTT {
+	return c.conn.RTT()
+}
+
 func (c *websocketClient) Errors() <-chan error {
 	return c.errCh
 }
@@ -187,6 +193,18 @@ func (c *websocketClient) getPayloadStruct(envelopeType ports.RequestEnvelopeTyp
 		return &ports.ListRoomsPayload{}, nil
 	case ports.RequestJoinEnvelope:
 		return &ports.RequestJoinPayload{}, nil
+	case ports.ScoreboardRequestEnvelope:
+		return &ports.ScoreboardRequestPayload{}, nil
+	case ports.InventoryEquipEnvelope:
+		return &ports.InventoryEquipPayload{}, nil
+	case ports.InventoryDropEnvelope:
+		return &ports.InventoryDropPayload{}, nil
+	case ports.InventoryPickUpEnvelope:
+		return &ports.InventoryPickUpPayload{}, nil
+	case ports.InventoryMoveEnvelope:
+		return &ports.InventoryMovePayload{}, nil
+	case ports.SnapshotAckEnvelope:
+		return &ports.SnapshotAckPayload{}, nil
 	default:
 		return nil, fmt.Errorf("unknown envelope type: %s", envelopeType)
 	}
@@ -14,6 +14,8 @@ type SessionInfo struct {
 	ClientID  string
 	Client    Client
 	LastSeen  time.Time
+	// RoomID is the room the session joined, a resumed session is put back in it.
+	RoomID string
 }
 
 // ClientRegistry manages the set of active clients.
@@ -67,6 +69,7 @@ func (cr *ClientRegistry) Add(client Client, providedSessionID string) error {
 			// Reconnection: reuse existing session
 			info.Client.Close() // TODO: handle error
 			info.Client = client
+			info.LastSeen = time.Now()
 
 			sessionInfo = info
 		}
@@ -104,6 +107,27 @@ func (cr *ClientRegistry) Remove(clientID string) {
 	// Sessions will be cleaned up by CleanupExpiredSessions
 }
 
+// RemoveClient removes a client like Remove, unless a newer connection of the
+// same client already replaced it.
+func (cr *ClientRegistry) RemoveClient(client Client) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if cr.clients[client.ID()] != client {
+		return
+	}
+	delete(cr.clients, client.ID())
+	delete(cr.clientSessions, client.ID())
+}
+
+// SetRoom records the room a session joined.
+func (cr *ClientRegistry) SetRoom(sessionID, roomID string) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if info, ok := cr.sessions[sessionID]; ok {
+		info.RoomID = roomID
+	}
+}
+
 // Get retrieves a client by its ID.
 func (cr *ClientRegistry) Get(clientID string) (Client, bool) {
 	cr.mu.RLock()
@@ -137,17 +161,47 @@ func (cr *ClientRegistry) SessionInfo(clientID string) (*SessionInfo, bool) {
 	return info, exists
 }
 
-// CleanupExpiredSessions removes sessions that have not been active within the given duration.
-func (cr *ClientRegistry) CleanupExpiredSessions(expiration time.Duration) {
+// Sessions returns a snapshot of all sessions, including the ones kept for
+// reconnection.
+func (cr *ClientRegistry) Sessions() []SessionInfo {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	list := make([]SessionInfo, 0, len(cr.sessions))
+	for _, info := range cr.sessions {
+		list = append(list, *info)
+	}
+	return list
+}
+
+// Touch records activity of a client, its session is seen now.
+func (cr *ClientRegistry) Touch(clientID string) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if info, ok := cr.sessions[cr.clientSessions[clientID]]; ok {
+		info.LastSeen = time.Now()
+	}
+}
+
+// CleanupExpiredSessions removes the sessions of disconnected clients that
+// have not been active within the given duration and returns them. Sessions
+// of connected clients are kept however long they are idle, the heartbeat
+// drops dead connections.
+func (cr *ClientRegistry) CleanupExpiredSessions(expiration time.Duration) []SessionInfo {
 	cr.mu.Lock()
 	defer cr.mu.Unlock()
 	now := time.Now()
+	var expired []SessionInfo
 	for sessionID, info := range cr.sessions {
-		if now.Sub(info.LastSeen) > expiration {
-			delete(cr.sessions, sessionID)
+		if !info.Client.IsClosed() || now.Sub(info.LastSeen) <= expiration {
+			continue
+		}
+		delete(cr.sessions, sessionID)
+		if cr.clientSessions[info.ClientID] == sessionID {
 			delete(cr.clientSessions, info.ClientID)
 		}
+		expired = append(expired, *info)
 	}
+	return expired
 }
 
 // All returns a snapshot of all clients.
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"survival/internal/utils"
+)
+
+// registryClient is a client the registry can hold, open until closed.
+type registryClient struct {
+	Client
+	id     string
+	closed bool
+}
+
+func (c *registryClient) ID() string { return c.id }
+
+func (c *registryClient) IsClosed() bool { return c.closed }
+
+func (c *registryClient) SetSessionID(string) error { return nil }
+
+func (c *registryClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestClientRegistry_CleanupExpiresDisconnectedSessionsOnly(t *testing.T) {
+	registry := NewClientRegistry(utils.NewSequentialIDGenerator("session"))
+	idle := &registryClient{id: "idle"}
+	gone := &registryClient{id: "gone"}
+	for _, client := range []*registryClient{idle, gone} {
+		if err := registry.Add(client, ""); err != nil {
+			t.Fatalf("Failed to add client: %v", err)
+		}
+	}
+	gone.Close()
+	registry.RemoveClient(gone)
+
+	time.Sleep(20 * time.Millisecond)
+	expired := registry.CleanupExpiredSessions(10 * time.Millisecond)
+	if len(expired) != 1 || expired[0].ClientID != "gone" {
+		t.Fatalf("Expected only the disconnected session expired, got %+v", expired)
+	}
+	if _, ok := registry.SessionInfo("idle"); !ok {
+		t.Error("Expected the idle but connected session kept")
+	}
+
+	// activity restarts the grace period
+	registry.Touch("idle")
+	idle.Close()
+	if expired := registry.CleanupExpiredSessions(time.Second); len(expired) != 0 {
+		t.Errorf("Expected a session seen just now kept, got %+v", expired)
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"survival/internal/adapters/repository/maploader"
+	"survival/internal/engine"
 	"survival/internal/engine/ports"
-	"survival/internal/utils"
 )
 
 type IDGenerator interface {
@@ -19,9 +21,13 @@ type IDGenerator interface {
 const (
 	DefaultRoomName = "default_room"
 	LocalUser       = "localhost"
+
+	// sessionSweepInterval is how often the janitor looks for expired sessions.
+	sessionSweepInterval = 5 * time.Second
 )
 
 type Hub struct {
+	roomConfig   RoomConfig
 	rooms        map[string]*Room
 	clients      *ClientRegistry
 	hubCommandCh chan ports.RequestCommand
@@ -30,10 +36,12 @@ type Hub struct {
 	shutdownOnce sync.Once
 }
 
-func NewHub(ctx context.Context, idGen IDGenerator) *Hub {
+// NewHub creates a hub whose rooms are created with the given room config.
+func NewHub(ctx context.Context, idGen IDGenerator, roomConfig RoomConfig) *Hub {
 	hubCtx, cancel := context.WithCancel(ctx)
 
 	return &Hub{
+		roomConfig:   roomConfig,
 		rooms:        make(map[string]*Room),
 		clients:      NewClientRegistry(idGen),
 		hubCommandCh: make(chan ports.RequestCommand, 256),
@@ -45,6 +53,7 @@ func NewHub(ctx context.Context, idGen IDGenerator) *Hub {
 func (h *Hub) Run() error {
 	log.Println("Hub is running...")
 	h.initializeDefaultGame()
+	go h.janitor()
 
 	return h.hubLoop()
 }
@@ -64,6 +73,7 @@ func (h *Hub) hubLoop() error {
 						Name:        room.Name(),
 						PlayerCount: room.PlayerCount(),
 						MaxPlayers:  room.MaxPlayers(),
+						Mode:        room.Mode(),
 					})
 				}
 
@@ -119,9 +129,46 @@ func (h *Hub) hubLoop() error {
 	}
 }
 
+// janitor expires the sessions of clients that stayed disconnected for the
+// grace period, removing their players from their rooms.
+func (h *Hub) janitor() {
+	gracePeriod := h.roomConfig.SessionGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultSessionGracePeriod
+	}
+
+	ticker := time.NewTicker(sessionSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.expireSessions(gracePeriod)
+		case <-h.ctx.Done():
+			return
+		}
+	}
+}
+
+// expireSessions drops the sessions disconnected for longer than the grace period.
+func (h *Hub) expireSessions(gracePeriod time.Duration) {
+	for _, session := range h.clients.CleanupExpiredSessions(gracePeriod) {
+		log.Printf("Session %s of client %s expired", session.SessionID, session.ClientID)
+		if room, ok := h.rooms[session.RoomID]; ok {
+			if err := room.RemovePlayer(session.SessionID); err != nil {
+				log.Printf("Failed to remove expired session %s: %v", session.SessionID, err)
+			}
+		}
+	}
+}
+
 func (h *Hub) handleLeave(client Client) {
-	log.Printf("Client %s leaving hub", client.ID())
-	h.clients.Remove(client.ID())
+	rtt := client.RTT()
+	log.Printf("Client %s leaving hub (rtt %v, jitter %v)", client.ID(), rtt.Smoothed, rtt.Jitter)
+	// the grace period of the session starts now
+	h.clients.Touch(client.ID())
+	// a resumed session may have replaced the connection already
+	h.clients.RemoveClient(client)
 
 	// Note: Don't immediately remove player from room to support reconnection
 	// Players will be removed later by session cleanup or explicit disconnect
@@ -130,6 +177,16 @@ func (h *Hub) handleLeave(client Client) {
 	client.Close() // Ensure client resources are cleaned up
 }
 
+// Sessions returns the sessions of the hub, the connected ones and the ones
+// kept for reconnection, sorted by session ID.
+func (h *Hub) Sessions() []SessionInfo {
+	sessions := h.clients.Sessions()
+	slices.SortFunc(sessions, func(a, b SessionInfo) int {
+		return strings.Compare(a.SessionID, b.SessionID)
+	})
+	return sessions
+}
+
 func (h *Hub) Shutdown(ctx context.Context) error {
 	h.shutdownOnce.Do(func() {
 		log.Println("Hub shutdown initiated.")
@@ -171,16 +228,66 @@ func (h *Hub) JoinRoom(clientID, roomID string) error {
 	if client == nil {
 		return fmt.Errorf("client '%s' is nil in registry", clientID)
 	}
+	if info, ok := h.clients.SessionInfo(clientID); ok && info.RoomID != "" {
+		return fmt.Errorf("session %s already joined room %s", client.SessionID(), info.RoomID)
+	}
 
-	if err := room.AddPlayer(client); err != nil {
-		return fmt.Errorf("failed to add player to room %s: %w", roomID, err)
+	if err := h.enterRoom(client, room); err != nil {
+		return err
 	}
+	h.clients.SetRoom(client.SessionID(), roomID)
+	log.Printf("[JoinRoom] Client %s (session: %s) joined room %s", clientID, client.SessionID(), roomID)
 
-	// Send static data (map, walls, etc.) to the newly joined client
-	room.SendStaticData([]string{client.SessionID()})
-	log.Printf("[JoinRoom] Client %s (session: %s) joined room %s, SendStaticData called", clientID, client.SessionID(), roomID)
+	return nil
+}
+
+// resumeRoom puts a client that resumed its session back in the room the
+// session joined, in control of the same player.
+func (h *Hub) resumeRoom(client Client, roomID string) error {
+	room, ok := h.rooms[roomID]
+	if !ok {
+		return fmt.Errorf("room '%s' not found for client %s", roomID, client.ID())
+	}
+	if err := h.enterRoom(client, room); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(h.ctx, 2*time.Second)
+	defer cancel()
+	if err := client.Send(ctx, ports.JoinRoomSuccessEnvelope, ports.ErrorPayload{}); err != nil {
+		return fmt.Errorf("failed to notify client %s of the resumed room: %w", client.ID(), err)
+	}
+	log.Printf("[ResumeRoom] Client %s (session: %s) resumed room %s", client.ID(), client.SessionID(), roomID)
+	return nil
+}
+
+// enterRoom adds the player of the client to the room, which sends it the
+// static data of the room, and forwards its room requests.
+func (h *Hub) enterRoom(client Client, room *Room) error {
+	if err := room.AddPlayer(client); err != nil {
+		return fmt.Errorf("failed to add player to room %s: %w", room.ID, err)
+	}
 
 	handler := func(cmd ports.RequestCommand) {
+		switch cmd.EnvelopeType {
+		case ports.ScoreboardRequestEnvelope:
+			if err := room.RequestScoreboard(client.SessionID()); err != nil {
+				log.Printf("Failed to request scoreboard for client %s: %v", client.ID(), err)
+			}
+			return
+		case ports.SnapshotAckEnvelope:
+			if ack, valid := cmd.ParsedPayload.(*ports.SnapshotAckPayload); valid {
+				if err := room.AckSnapshot(client.SessionID(), ack.Sequence); err != nil {
+					log.Printf("Failed to ack snapshot for client %s: %v", client.ID(), err)
+				}
+			}
+			return
+		case ports.InventoryEquipEnvelope, ports.InventoryDropEnvelope, ports.InventoryPickUpEnvelope, ports.InventoryMoveEnvelope:
+			if err := room.RequestInventoryAction(client.SessionID(), cmd.ParsedPayload); err != nil {
+				log.Printf("Failed to request inventory action for client %s: %v", client.ID(), err)
+			}
+			return
+		}
 		if cmd.EnvelopeType != ports.PlayerInputEnvelope {
 			log.Printf("Ignoring non-input command from client %s", client.ID())
 			return
@@ -202,14 +309,16 @@ func (h *Hub) JoinRoom(clientID, roomID string) error {
 	}
 
 	if err := client.Subscribe(handler); err != nil {
-		return fmt.Errorf("failed to subscribe client %s to room %s: %w", clientID, roomID, err)
+		return fmt.Errorf("failed to subscribe client %s to room %s: %w", client.ID(), room.ID, err)
 	}
 
 	return nil
 }
 
-func (h *Hub) DispatchConnection(ctx context.Context, conn ports.RawConnection, gameName, clientID, name, sessionID string) error {
-	client := newWebsocketClient(h.ctx, clientID, name, conn, utils.NewJsonCodec())
+// DispatchConnection registers a new connection, encoding and decoding its
+// messages with the codec negotiated for it.
+func (h *Hub) DispatchConnection(ctx context.Context, conn ports.RawConnection, codec ports.Codec, gameName, clientID, name, sessionID string) error {
+	client := newWebsocketClient(h.ctx, clientID, name, conn, codec)
 
 	go func() {
 		defer func() {
@@ -226,21 +335,41 @@ func (h *Hub) DispatchConnection(ctx context.Context, conn ports.RawConnection,
 	}
 
 	if err := client.Subscribe(func(cmd ports.RequestCommand) {
-		// Only forward hub-level commands (not player_input, which is handled by room)
-		if cmd.EnvelopeType != ports.PlayerInputEnvelope {
+		h.clients.Touch(clientID)
+		// Only forward hub-level commands, the rest is handled by the room
+		if !isRoomEnvelope(cmd.EnvelopeType) {
 			h.hubCommandCh <- cmd
 		}
 	}); err != nil {
 		return fmt.Errorf("failed to subscribe to client %s: %w", clientID, err)
 	}
 
+	// the registry keeps the session a client asked for when it still knows
+	// it, resuming the player the session had
+	if info, ok := h.clients.SessionInfo(clientID); ok && sessionID != "" && info.SessionID == sessionID && info.RoomID != "" {
+		if err := h.resumeRoom(client, info.RoomID); err != nil {
+			return fmt.Errorf("failed to resume session %s: %w", sessionID, err)
+		}
+	}
+
 	return nil
 }
 
+// isRoomEnvelope reports whether a request is handled by the room the client joined.
+func isRoomEnvelope(envelopeType ports.RequestEnvelopeType) bool {
+	switch envelopeType {
+	case ports.PlayerInputEnvelope, ports.ScoreboardRequestEnvelope, ports.SnapshotAckEnvelope,
+		ports.InventoryEquipEnvelope, ports.InventoryDropEnvelope, ports.InventoryPickUpEnvelope, ports.InventoryMoveEnvelope:
+		return true
+	default:
+		return false
+	}
+}
+
 func (h *Hub) initializeDefaultGame() {
 	roomID := DefaultRoomName
 
-	room, err := createDefaultRoom(h.ctx, roomID)
+	room, err := createDefaultRoom(h.ctx, roomID, h.roomConfig)
 	if err != nil {
 		log.Printf("Failed to create default room: %v", err)
 		return
@@ -262,8 +391,12 @@ func (h *Hub) initializeDefaultGame() {
 				log.Printf("No client found for session ID %s", sessionID)
 				continue
 			}
+			if client.IsClosed() {
+				// disconnected, the session waits to be resumed
+				continue
+			}
 
-			if err := client.Send(context.Background(), msg.Envelope.EnvelopeType, msg.Envelope.Payload); err != nil {
+			if err := client.Send(context.Background(), msg.EnvelopeType, msg.Payload); err != nil {
 				log.Printf("Failed to send message to client %s: %v", client.ID(), err)
 			}
 		}
@@ -274,13 +407,13 @@ func (h *Hub) initializeDefaultGame() {
 	}
 }
 
-func createDefaultRoom(ctx context.Context, roomID string) (*Room, error) {
+func createDefaultRoom(ctx context.Context, roomID string, config RoomConfig) (*Room, error) {
 	jsonLoader := maploader.NewJSONMapLoader("./maps")
 	mapConfig, err := jsonLoader.LoadMap("office_floor_01")
 	if err != nil {
 		log.Printf("Failed to load office_floor_01 from JSON: %v, using default map", err)
-		return NewRoom(ctx, roomID)
+		mapConfig = engine.DefaultMapConfig()
 	}
 
-	return NewRoomWithMap(ctx, roomID, mapConfig)
+	return NewRoomWithConfig(ctx, roomID, mapConfig, config)
 }
@@ -14,7 +14,7 @@ func TestNewHub_WithMapLoading(t *testing.T) {
 	idGen := utils.NewSequentialIDGenerator("test")
 
 	// Create hub - should try to load office_floor_01, fallback to embedded default
-	hub := NewHub(ctx, idGen)
+	hub := NewHub(ctx, idGen, DefaultRoomConfig())
 	defer hub.Shutdown(ctx)
 
 	// Verify hub was created successfully
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"fmt"
+
+	"survival/internal/engine"
+	"survival/internal/engine/state"
+)
+
+// ModeName identifies a game mode a room can be created with.
+type ModeName string
+
+const (
+	// ModeDeathmatch is a free for all, the first player to reach the frag limit wins.
+	ModeDeathmatch ModeName = "deathmatch"
+	// ModeTeamDeathmatch splits the players into two teams racing to the score limit.
+	ModeTeamDeathmatch ModeName = "team_deathmatch"
+	// ModeLastManStanding has no respawns, the last player alive wins.
+	ModeLastManStanding ModeName = "last_man_standing"
+	// ModeBattleRoyale is last man standing inside a shrinking safe zone.
+	ModeBattleRoyale ModeName = "battle_royale"
+)
+
+// RoundRules are the phase lengths of a round in seconds. A round starts once
+// MinPlayers are in the room and the warmup ran out. TimeLimit 0 means the
+// round only ends when the mode decides it.
+type RoundRules struct {
+	MinPlayers int
+	Warmup     float64
+	TimeLimit  float64
+	PostRound  float64
+}
+
+// RoundResult is the outcome of a round, the zero value is a draw.
+type RoundResult struct {
+	Winner     state.EntityID
+	WinnerTeam int
+}
+
+// Mode is the rule set a room plays its rounds by. The room calls the hooks
+// from its own goroutine, so modes need no locking. Bots join and leave like
+// human players. Kills are only reported while a round is live.
+type Mode interface {
+	Name() ModeName
+	Rules() RoundRules
+	// Respawn reports whether dead players come back during a live round.
+	Respawn() bool
+	// Teams is the number of teams players are balanced into, 0 for free for all modes.
+	Teams() int
+	// OnJoin is called with the team the player was put in, state.NoTeam without teams.
+	OnJoin(id state.EntityID, team state.Team)
+	OnLeave(id state.EntityID)
+	OnKill(killer, victim state.EntityID)
+	// OnRoundStart is called when a round goes live, after everyone respawned.
+	OnRoundStart(game *engine.Game)
+	// OnTick is called every live tick and reports whether the round is decided.
+	OnTick(game *engine.Game, dt float64) bool
+	// OnRoundEnd returns the result of the round and resets the scores for the next one.
+	OnRoundEnd(game *engine.Game) RoundResult
+}
+
+const (
+	defaultRoundMinPlayers = 2
+	defaultRoundWarmup     = 10.0
+	defaultRoundPostRound  = 8.0
+
+	defaultDeathmatchTimeLimit   = 300.0
+	defaultDeathmatchFragLimit   = 20
+	defaultTeamTimeLimit         = 600.0
+	defaultTeamScoreLimit        = 50
+	defaultLastManTimeLimit      = 180.0
+	defaultBattleRoyaleTimeLimit = 300.0
+)
+
+// NewMode creates a mode with its default rules. An empty name means ModeDeathmatch.
+func NewMode(name ModeName) (Mode, error) {
+	switch name {
+	case "", ModeDeathmatch:
+		return newDeathmatch(defaultDeathmatchFragLimit), nil
+	case ModeTeamDeathmatch:
+		return newTeamDeathmatch(defaultTeamScoreLimit), nil
+	case ModeLastManStanding:
+		return newLastManStanding(), nil
+	case ModeBattleRoyale:
+		return newBattleRoyale(), nil
+	default:
+		return nil, fmt.Errorf("unknown game mode %q", name)
+	}
+}
+
+func defaultRoundRules(timeLimit float64) RoundRules {
+	return RoundRules{
+		MinPlayers: defaultRoundMinPlayers,
+		Warmup:     defaultRoundWarmup,
+		TimeLimit:  timeLimit,
+		PostRound:  defaultRoundPostRound,
+	}
+}
+
+// deathmatch counts kills per player until someone reaches the frag limit.
+type deathmatch struct {
+	fragLimit int
+	frags     map[state.EntityID]int
+}
+
+func newDeathmatch(fragLimit int) *deathmatch {
+	return &deathmatch{
+		fragLimit: fragLimit,
+		frags:     make(map[state.EntityID]int),
+	}
+}
+
+func (m *deathmatch) Name() ModeName { return ModeDeathmatch }
+
+func (m *deathmatch) Rules() RoundRules { return defaultRoundRules(defaultDeathmatchTimeLimit) }
+
+func (m *deathmatch) Respawn() bool { return true }
+
+func (m *deathmatch) Teams() int { return 0 }
+
+func (m *deathmatch) OnJoin(id state.EntityID, team state.Team) { m.frags[id] = 0 }
+
+func (m *deathmatch) OnLeave(id state.EntityID) { delete(m.frags, id) }
+
+func (m *deathmatch) OnKill(killer, victim state.EntityID) {
+	if _, playing := m.frags[killer]; playing && killer != victim {
+		m.frags[killer]++
+	}
+}
+
+func (m *deathmatch) OnRoundStart(game *engine.Game) {}
+
+func (m *deathmatch) OnTick(game *engine.Game, dt float64) bool {
+	for _, frags := range m.frags {
+		if frags >= m.fragLimit {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *deathmatch) OnRoundEnd(game *engine.Game) RoundResult {
+	var result RoundResult
+	best, tied := 0, false
+	for id, frags := range m.frags {
+		switch {
+		case frags > best:
+			result.Winner, best, tied = id, frags, false
+		case frags == best && frags > 0:
+			tied = true
+		}
+		m.frags[id] = 0
+	}
+	if tied {
+		return RoundResult{}
+	}
+	return result
+}
+
+// teamDeathmatch counts kills per team for two teams. Killing a teammate costs
+// the team a point.
+type teamDeathmatch struct {
+	scoreLimit int
+	teams      map[state.EntityID]state.Team
+	scores     [teamCount + 1]int
+}
+
+const teamCount = 2
+
+func newTeamDeathmatch(scoreLimit int) *teamDeathmatch {
+	return &teamDeathmatch{
+		scoreLimit: scoreLimit,
+		teams:      make(map[state.EntityID]state.Team),
+	}
+}
+
+func (m *teamDeathmatch) Name() ModeName { return ModeTeamDeathmatch }
+
+func (m *teamDeathmatch) Rules() RoundRules { return defaultRoundRules(defaultTeamTimeLimit) }
+
+func (m *teamDeathmatch) Respawn() bool { return true }
+
+func (m *teamDeathmatch) Teams() int { return teamCount }
+
+func (m *teamDeathmatch) OnJoin(id state.EntityID, team state.Team) { m.teams[id] = team }
+
+func (m *teamDeathmatch) OnLeave(id state.EntityID) { delete(m.teams, id) }
+
+func (m *teamDeathmatch) OnKill(killer, victim state.EntityID) {
+	killerTeam, playing := m.teams[killer]
+	if !playing || killer == victim || killerTeam == state.NoTeam {
+		return
+	}
+	if killerTeam == m.teams[victim] {
+		m.scores[killerTeam]--
+		return
+	}
+	m.scores[killerTeam]++
+}
+
+func (m *teamDeathmatch) OnRoundStart(game *engine.Game) {}
+
+func (m *teamDeathmatch) OnTick(game *engine.Game, dt float64) bool {
+	for team := 1; team <= teamCount; team++ {
+		if m.scores[team] >= m.scoreLimit {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *teamDeathmatch) OnRoundEnd(game *engine.Game) RoundResult {
+	var result RoundResult
+	if m.scores[1] != m.scores[2] {
+		result.WinnerTeam = 1
+		if m.scores[2] > m.scores[1] {
+			result.WinnerTeam = 2
+		}
+	}
+	m.scores = [teamCount + 1]int{}
+	return result
+}
+
+// Team returns the team of a player, state.NoTeam for entities not playing.
+func (m *teamDeathmatch) Team(id state.EntityID) state.Team {
+	return m.teams[id]
+}
+
+// lastManStanding disables respawns, the round ends once at most one player is alive.
+type lastManStanding struct {
+	players map[state.EntityID]struct{}
+}
+
+func newLastManStanding() *lastManStanding {
+	return &lastManStanding{players: make(map[state.EntityID]struct{})}
+}
+
+func (m *lastManStanding) Name() ModeName { return ModeLastManStanding }
+
+func (m *lastManStanding) Rules() RoundRules { return defaultRoundRules(defaultLastManTimeLimit) }
+
+func (m *lastManStanding) Respawn() bool { return false }
+
+func (m *lastManStanding) Teams() int { return 0 }
+
+func (m *lastManStanding) OnJoin(id state.EntityID, team state.Team) { m.players[id] = struct{}{} }
+
+func (m *lastManStanding) OnLeave(id state.EntityID) { delete(m.players, id) }
+
+func (m *lastManStanding) OnKill(killer, victim state.EntityID) {}
+
+func (m *lastManStanding) OnRoundStart(game *engine.Game) {}
+
+func (m *lastManStanding) OnTick(game *engine.Game, dt float64) bool {
+	alive := 0
+	for id := range m.players {
+		if game.IsAlive(id) {
+			alive++
+		}
+	}
+	return alive <= 1
+}
+
+// OnRoundEnd names the only survivor the winner. Rounds running out of time with
+// several players alive are a draw.
+func (m *lastManStanding) OnRoundEnd(game *engine.Game) RoundResult {
+	var result RoundResult
+	for id := range m.players {
+		if !game.IsAlive(id) {
+			continue
+		}
+		if result.Winner != 0 {
+			return RoundResult{}
+		}
+		result.Winner = id
+	}
+	return result
+}
+
+// battleRoyale plays last man standing while the safe zone of the game closes
+// in. The zone starts with the round and is removed once it ends, so warmups
+// are played without it. The room has to create the game with a safe zone.
+type battleRoyale struct {
+	*lastManStanding
+}
+
+func newBattleRoyale() *battleRoyale {
+	return &battleRoyale{lastManStanding: newLastManStanding()}
+}
+
+func (m *battleRoyale) Name() ModeName { return ModeBattleRoyale }
+
+func (m *battleRoyale) Rules() RoundRules { return defaultRoundRules(defaultBattleRoyaleTimeLimit) }
+
+func (m *battleRoyale) OnRoundStart(game *engine.Game) { game.StartZone() }
+
+func (m *battleRoyale) OnRoundEnd(game *engine.Game) RoundResult {
+	game.StopZone()
+	return m.lastManStanding.OnRoundEnd(game)
+}
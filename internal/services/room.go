@@ -2,31 +2,100 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"reflect"
 	"time"
 
 	"survival/internal/engine"
 	"survival/internal/engine/ports"
+	"survival/internal/engine/state"
+	"survival/internal/engine/weapons"
 	"survival/internal/utils"
 )
 
+// UpdateMessage is a response of the room for some of its sessions. The
+// payload is one of the ports payloads, encoded by the codec of each client.
 type UpdateMessage struct {
-	ToSessions []string // an empty slice means broadcast to all
-	Envelope   ports.ResponseEnvelope
+	ToSessions   []string // an empty slice means broadcast to all
+	EnvelopeType ports.ResponseEnvelopeType
+	Payload      any
+}
+
+// RoomConfig holds the settings a room is created with.
+type RoomConfig struct {
+	// MaxPlayers caps the number of human players and bots together, 0 means unlimited.
+	MaxPlayers int
+	// BotCount is the number of players bots keep the room at while humans are missing.
+	BotCount int
+	// Game selects the simulation rules of the room, deathmatch or survival.
+	Game engine.GameConfig
+	// Mode selects the round rules of deathmatch games. Survival games run
+	// their own waves instead of rounds and ignore it.
+	Mode ModeName
+	// SessionGracePeriod is how long the player of a disconnected session
+	// stays in the room to be resumed, DefaultSessionGracePeriod when unset.
+	SessionGracePeriod time.Duration
+}
+
+const (
+	DefaultMaxPlayers         = 8
+	DefaultBotCount           = 3
+	DefaultSessionGracePeriod = time.Minute
+)
+
+// lightMapInterval is the number of ticks between light map changes sent to
+// the clients, 10 a second.
+const lightMapInterval uint64 = ports.TargetTickRate / 10
+
+func DefaultRoomConfig() RoomConfig {
+	return RoomConfig{
+		MaxPlayers: DefaultMaxPlayers,
+		BotCount:   DefaultBotCount,
+		Game:       engine.DefaultGameConfig(),
+		Mode:       ModeDeathmatch,
+
+		SessionGracePeriod: DefaultSessionGracePeriod,
+	}
+}
+
+// inventoryAction is an inventory request of a session, Payload is one of the
+// ports inventory payloads.
+type inventoryAction struct {
+	SessionID string
+	Payload   any
 }
 
 type Room struct {
 	ID         string
 	mapConfig  *engine.MapConfig
+	config     RoomConfig
 	game       *engine.Game
+	rounds     *Rounds // nil in survival games
+	scoreboard *Scoreboard
 	sessions   *SessionRegistry
 	subManager *Manager[UpdateMessage]
 
-	joinClientCh chan Client
-	commands     chan ports.Command
-	outgoing     chan UpdateMessage
+	bots []state.EntityID
+
+	lightMapVersion uint64
+	// lightLevels are the light levels last sent, which the changes sent
+	// next apply to. It is replaced, never changed in place.
+	lightLevels       []uint8
+	scoreboardVersion uint64
+	// inventories holds the inventory payload last sent to each session.
+	inventories map[string]ports.InventoryPayload
+	// snapshots holds the game updates sent to each session.
+	snapshots map[string]*snapshotHistory
+
+	joinClientCh       chan Client
+	leavingSessions    chan string
+	commands           chan ports.Command
+	scoreboardRequests chan string
+	snapshotAcks       chan snapshotAck
+	inventoryActions   chan inventoryAction
+	outgoing           chan UpdateMessage
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -37,24 +106,58 @@ func NewRoom(ctx context.Context, id string) (*Room, error) {
 }
 
 func NewRoomWithMap(ctx context.Context, id string, mapConfig *engine.MapConfig) (*Room, error) {
+	return NewRoomWithConfig(ctx, id, mapConfig, DefaultRoomConfig())
+}
+
+func NewRoomWithConfig(ctx context.Context, id string, mapConfig *engine.MapConfig, config RoomConfig) (*Room, error) {
 	roomCTX, cancel := context.WithCancel(ctx)
 
-	game, err := engine.NewGame(mapConfig)
+	// battle royale rounds are played inside the safe zone
+	if config.Mode == ModeBattleRoyale && config.Game.Mode != engine.GameModeSurvival {
+		config.Game.SafeZone = true
+	}
+
+	game, err := engine.NewGameWithConfig(mapConfig, config.Game)
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("failed to create game: %w", err)
 	}
 
+	var rounds *Rounds
+	if game.Mode() != engine.GameModeSurvival {
+		mode, err := NewMode(config.Mode)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create game mode: %w", err)
+		}
+		rounds = NewRounds(mode, game)
+	}
+
+	lightMap := game.LightMap()
+
 	return &Room{
 		ID:         id,
 		mapConfig:  mapConfig,
+		config:     config,
 		game:       game,
+		rounds:     rounds,
+		scoreboard: NewScoreboard(),
 		sessions:   NewSessionRegistry(),
 		subManager: NewManager[UpdateMessage](utils.NewSequentialIDGenerator(fmt.Sprintf("room%s-sub-", id))),
 
-		joinClientCh: make(chan Client, 100),
-		commands:     make(chan ports.Command, 200),
-		outgoing:     make(chan UpdateMessage, 400),
+		lightMapVersion: lightMap.Version,
+		lightLevels:     lightMap.Levels,
+
+		inventories: make(map[string]ports.InventoryPayload),
+		snapshots:   make(map[string]*snapshotHistory),
+
+		joinClientCh:       make(chan Client, 100),
+		leavingSessions:    make(chan string, 100),
+		commands:           make(chan ports.Command, 200),
+		scoreboardRequests: make(chan string, 100),
+		snapshotAcks:       make(chan snapshotAck, 200),
+		inventoryActions:   make(chan inventoryAction, 100),
+		outgoing:           make(chan UpdateMessage, 400),
 
 		ctx:    roomCTX,
 		cancel: cancel,
@@ -69,6 +172,38 @@ func (r *Room) SendCommand(cmd ports.Command) {
 	}
 }
 
+// RequestScoreboard asks the room to send its scoreboard to a session.
+func (r *Room) RequestScoreboard(sessionID string) error {
+	select {
+	case r.scoreboardRequests <- sessionID:
+		return nil
+	default:
+		return fmt.Errorf("room %s scoreboard request channel full, dropping request from session %s", r.ID, sessionID)
+	}
+}
+
+// AckSnapshot records that a session received the game update numbered
+// sequence, the next updates are sent as deltas against it.
+func (r *Room) AckSnapshot(sessionID string, sequence uint32) error {
+	select {
+	case r.snapshotAcks <- snapshotAck{SessionID: sessionID, Sequence: sequence}:
+		return nil
+	default:
+		return fmt.Errorf("room %s snapshot ack channel full, dropping ack from session %s", r.ID, sessionID)
+	}
+}
+
+// RequestInventoryAction asks the room to equip, drop, pick up or move an item
+// for a session. Rejected actions are answered with an error.
+func (r *Room) RequestInventoryAction(sessionID string, payload any) error {
+	select {
+	case r.inventoryActions <- inventoryAction{SessionID: sessionID, Payload: payload}:
+		return nil
+	default:
+		return fmt.Errorf("room %s inventory channel full, dropping action from session %s", r.ID, sessionID)
+	}
+}
+
 func (r *Room) SubscribeResponse(handler func(msg UpdateMessage)) error {
 	// todo: check room is running
 
@@ -127,16 +262,50 @@ func (r *Room) Run() {
 			if err := r.addPlayer(client); err != nil {
 				log.Printf("Failed to add player to room %s: %v", r.ID, err)
 			}
+		case sessionID := <-r.leavingSessions:
+			r.removePlayer(sessionID)
 		case cmd := <-r.commands:
 			entityID, ok := r.sessions.EntityID(cmd.SessionID)
 			if !ok {
 				log.Printf("Warning: No entity ID found for session %s", cmd.SessionID)
 				continue
 			}
-			r.game.SetPlayerInput(entityID, cmd.Input)
+			// shots are resolved against the other players where the client drew them
+			var viewTick uint64
+			if history, ok := r.snapshots[cmd.SessionID]; ok {
+				viewTick = history.ViewTick(cmd.Input.InterpolationDelay)
+			}
+			r.game.SetPlayerInputAt(entityID, cmd.Input, viewTick)
+		case sessionID := <-r.scoreboardRequests:
+			if _, ok := r.sessions.EntityID(sessionID); ok {
+				r.SendScoreboard([]string{sessionID})
+			}
+		case ack := <-r.snapshotAcks:
+			if history, ok := r.snapshots[ack.SessionID]; ok {
+				history.Ack(ack.Sequence)
+			}
+		case action := <-r.inventoryActions:
+			if err := r.applyInventoryAction(action); err != nil {
+				r.sendError(action.SessionID, ports.ErrorCodeBadRequest, err.Error())
+			}
 		case <-ticker.C:
+			r.syncBots()
 			r.game.Update(ports.DeltaTime)
+			r.scoreboard.Record(ports.DeltaTime, r.game.CombatEvents())
+			if r.rounds != nil {
+				wasLive := r.rounds.Phase() == RoundLive
+				r.rounds.Update(ports.DeltaTime, r.game.CombatEvents())
+				// every round is a new match on the scoreboard
+				if !wasLive && r.rounds.Phase() == RoundLive {
+					r.scoreboard.Reset()
+				}
+			}
+			r.broadcastStaticChanges()
+			r.sendFloorChanges()
 			r.broadcastGameUpdate()
+			r.broadcastLightMapIfChanged()
+			r.broadcastScoreboardIfChanged()
+			r.sendInventoriesIfChanged()
 		case <-r.ctx.Done():
 			return
 		}
@@ -155,7 +324,7 @@ func (r *Room) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// AddPlayer creates a new player or reconnects existing players and registers them to the room.
+// AddPlayer creates a new player or re-attaches a resumed session to its player and registers them to the room.
 func (r *Room) AddPlayer(client Client) error {
 	select {
 	case r.joinClientCh <- client:
@@ -168,73 +337,393 @@ func (r *Room) AddPlayer(client Client) error {
 func (r *Room) addPlayer(client Client) error {
 	log.Printf("Adding player for session %s to room %s", client.SessionID(), r.ID)
 	sessionID := client.SessionID()
-	if _, exist := r.sessions.EntityID(sessionID); exist {
-		return fmt.Errorf("session %s already registered in room %s", sessionID, r.ID)
+	if entityID, exist := r.sessions.EntityID(sessionID); exist {
+		r.resumePlayer(sessionID, entityID)
+		return nil
+	}
+	if r.config.MaxPlayers > 0 && r.PlayerCount() >= r.config.MaxPlayers {
+		return fmt.Errorf("room %s is full", r.ID)
 	}
 
-	// TODO: check reconnection logic exist ?
 	entityID, err := r.game.JoinPlayer()
 	if err != nil {
 		return fmt.Errorf("failed to join player for session %s in room %s: %w", sessionID, r.ID, err)
 	}
 
 	r.sessions.Register(sessionID, entityID)
+	// a new client has no updates to apply deltas to
+	r.snapshots[sessionID] = &snapshotHistory{}
+	if r.rounds != nil {
+		r.rounds.Join(entityID)
+	}
+	r.scoreboard.Join(sessionID, entityID, client.Name(), false)
+	// registered first, so walls destroyed from now on reach the client as deltas
+	r.sendStaticData(sessionID)
+	r.SendLightMap([]string{sessionID})
 
 	log.Printf("Player created and registered - Session: %s, EntityID: %d", sessionID, entityID)
 	log.Printf("Total players in room: %d", r.PlayerCount())
 	return nil
 }
 
-// SendStaticData sends static map data (walls, dimensions) to specific clients
-func (r *Room) SendStaticData(sessionIDs []string) {
-	staticData := r.game.Statics()
+// resumePlayer hands the player of a session over to the connection the
+// session was resumed on. The new connection has no updates to apply deltas
+// to, so it starts over with the static data of the floor of the player, a
+// full game update, its inventory and the light map.
+func (r *Room) resumePlayer(sessionID string, entityID state.EntityID) {
+	r.snapshots[sessionID] = &snapshotHistory{}
+	delete(r.inventories, sessionID)
+	r.sendStaticData(sessionID)
+	r.SendLightMap([]string{sessionID})
+
+	log.Printf("Player resumed - Session: %s, EntityID: %d", sessionID, entityID)
+}
+
+// sendStaticData sends static map data (walls, dimensions) of the floor the
+// player of the session is on. Walls are destroyed by the game updates, so it
+// must run on the room goroutine like them.
+func (r *Room) sendStaticData(sessionID string) {
+	var floor state.Floor
+	if entityID, ok := r.sessions.EntityID(sessionID); ok {
+		floor = r.game.Floor(entityID)
+	}
+	r.sendFloorStaticData(floor, []string{sessionID})
+}
+
+func (r *Room) sendFloorStaticData(floor state.Floor, sessionIDs []string) {
+	staticData := r.game.FloorStatics(floor)
 	mapInfo := r.game.MapInfo()
 
-	log.Printf("[SendStaticData] Sending %d colliders, map: %.0fx%.0f to sessions: %v",
-		len(staticData), mapInfo.Width, mapInfo.Height, sessionIDs)
+	log.Printf("[SendStaticData] Sending %d colliders of floor %d, map: %.0fx%.0f to sessions: %v",
+		len(staticData), floor, mapInfo.Width, mapInfo.Height, sessionIDs)
 
 	colliders := make([]ports.Collider, len(staticData))
 	for i, entity := range staticData {
-		colliders[i] = ports.Collider{
-			ID:            uint64(entity.ID),
-			X:             entity.Collider.Center.X,
-			Y:             entity.Collider.Center.Y,
-			HalfX:         entity.Collider.HalfSize.X,
-			HalfY:         entity.Collider.HalfSize.Y,
-			Radius:        entity.Collider.Radius,
-			ShapeType:     uint8(entity.Collider.ShapeType),
-			Rotation:      0,
-			Height:        entity.VerticalBody.Height,
-			BaseElevation: entity.VerticalBody.BaseElevation,
+		colliders[i] = colliderInfo(entity)
+	}
+
+	var stairs []ports.Stair
+	for _, stair := range r.game.FloorStairs(floor) {
+		to, _ := stair.Destination(floor)
+		stairs = append(stairs, ports.Stair{
+			X:     (stair.Bounds.MinX + stair.Bounds.MaxX) / 2,
+			Y:     (stair.Bounds.MinY + stair.Bounds.MaxY) / 2,
+			HalfX: (stair.Bounds.MaxX - stair.Bounds.MinX) / 2,
+			HalfY: (stair.Bounds.MaxY - stair.Bounds.MinY) / 2,
+			Kind:  stair.Kind.String(),
+			To:    int(to),
+		})
+	}
+
+	r.send(sessionIDs, ports.StaticDataEnvelope, ports.StaticDataPayload{
+		Colliders:  colliders,
+		MapWidth:   mapInfo.Width,
+		MapHeight:  mapInfo.Height,
+		Floor:      int(floor),
+		FloorCount: r.game.FloorCount(),
+		Stairs:     stairs,
+	})
+}
+
+// sendFloorChanges sends the static data of their new floor to the players
+// that moved to another floor during the last update.
+func (r *Room) sendFloorChanges() {
+	for _, change := range r.game.FloorChanges() {
+		if sessionID, ok := r.sessions.SessionID(change.ID); ok {
+			r.sendFloorStaticData(change.To, []string{sessionID})
 		}
 	}
+}
+
+func colliderInfo(entity state.StaticEntity) ports.Collider {
+	return ports.Collider{
+		ID:            uint64(entity.ID),
+		X:             entity.Collider.Center.X,
+		Y:             entity.Collider.Center.Y,
+		HalfX:         entity.Collider.HalfSize.X,
+		HalfY:         entity.Collider.HalfSize.Y,
+		Radius:        entity.Collider.Radius,
+		ShapeType:     uint8(entity.Collider.ShapeType),
+		Rotation:      0,
+		Height:        entity.VerticalBody.Height,
+		BaseElevation: entity.VerticalBody.BaseElevation,
+	}
+}
+
+// broadcastStaticChanges sends the colliders removed or added during the last
+// update to the clients on their floor, so they do not need the full static
+// data again. Players arriving on a floor get its full static data instead.
+func (r *Room) broadcastStaticChanges() {
+	changes := r.game.StaticChanges()
+	if len(changes) == 0 {
+		return
+	}
 
-	payloadBytes, err := json.Marshal(ports.StaticDataPayload{
-		Colliders: colliders,
-		MapWidth:  mapInfo.Width,
-		MapHeight: mapInfo.Height,
+	payloads := make(map[state.Floor]*ports.StaticDataDeltaPayload)
+	for _, change := range changes {
+		payload, exist := payloads[change.Floor]
+		if !exist {
+			payload = &ports.StaticDataDeltaPayload{}
+			payloads[change.Floor] = payload
+		}
+		if change.Removed {
+			payload.Removed = append(payload.Removed, uint64(change.ID))
+			continue
+		}
+		if entity, exist := r.game.Static(change.ID); exist {
+			payload.Added = append(payload.Added, colliderInfo(entity))
+		}
+	}
+
+	sessionsByFloor := make(map[state.Floor][]string)
+	for entityID, sessionID := range r.sessions.All() {
+		floor := r.game.Floor(entityID)
+		sessionsByFloor[floor] = append(sessionsByFloor[floor], sessionID)
+	}
+
+	for floor, payload := range payloads {
+		if sessionIDs := sessionsByFloor[floor]; len(sessionIDs) > 0 {
+			r.send(sessionIDs, ports.StaticDataDeltaEnvelope, *payload)
+		}
+	}
+}
+
+// SendLightMap sends the light levels of the map last sent to the others to
+// specific clients, so that the changes sent to all of them next apply to
+// theirs too.
+func (r *Room) SendLightMap(sessionIDs []string) {
+	lightMap := r.game.LightMap()
+
+	r.send(sessionIDs, ports.LightMapEnvelope, ports.LightMapPayload{
+		CellSize: lightMap.CellSize,
+		Width:    lightMap.Width,
+		Height:   lightMap.Height,
+		Levels:   r.lightLevels,
 	})
-	if err != nil {
-		log.Printf("Failed to marshal static data: %v", err)
+}
+
+// broadcastLightMapIfChanged sends the cells whose light level changed since
+// the last time, every lightMapInterval ticks. Moving flashlights change
+// some cells on almost every tick.
+func (r *Room) broadcastLightMapIfChanged() {
+	if r.game.Tick()%lightMapInterval != 0 {
+		return
+	}
+	version := r.game.LightMapVersion()
+	if version == r.lightMapVersion {
+		return
+	}
+	r.lightMapVersion = version
+
+	levels := r.game.LightMap().Levels
+	var delta ports.LightMapDeltaPayload
+	for index, level := range levels {
+		if level != r.lightLevels[index] {
+			delta.Indexes = append(delta.Indexes, index)
+			delta.Levels = append(delta.Levels, level)
+		}
+	}
+	r.lightLevels = levels
+
+	sessionIDs := r.sessions.AllSessionIDs()
+	if len(sessionIDs) == 0 || len(delta.Indexes) == 0 {
+		return
+	}
+	r.send(sessionIDs, ports.LightMapDeltaEnvelope, delta)
+}
+
+// SendScoreboard sends the scoreboard of the current match to specific clients.
+func (r *Room) SendScoreboard(sessionIDs []string) {
+	entries := r.scoreboard.Entries()
+	payload := ports.ScoreboardPayload{Entries: make([]ports.ScoreboardEntry, len(entries))}
+	for i, entry := range entries {
+		payload.Entries[i] = ports.ScoreboardEntry{
+			PlayerID: uint64(entry.EntityID),
+			Name:     entry.Name,
+			Bot:      entry.Bot,
+			Team:     int(r.game.Team(entry.EntityID)),
+			Kills:    entry.Stats.Kills,
+			Deaths:   entry.Stats.Deaths,
+			Assists:  entry.Stats.Assists,
+			Damage:   entry.Stats.Damage,
+			Accuracy: entry.Stats.Accuracy(),
+		}
+	}
+
+	r.send(sessionIDs, ports.ScoreboardEnvelope, payload)
+}
+
+func (r *Room) broadcastScoreboardIfChanged() {
+	version := r.scoreboard.Version()
+	if version == r.scoreboardVersion {
+		return
+	}
+	r.scoreboardVersion = version
+
+	sessionIDs := r.sessions.AllSessionIDs()
+	if len(sessionIDs) == 0 {
 		return
 	}
+	r.SendScoreboard(sessionIDs)
+}
+
+func (r *Room) applyInventoryAction(action inventoryAction) error {
+	entityID, ok := r.sessions.EntityID(action.SessionID)
+	if !ok {
+		return fmt.Errorf("session %s is not playing in room %s", action.SessionID, r.ID)
+	}
+
+	switch payload := action.Payload.(type) {
+	case *ports.InventoryEquipPayload:
+		return r.game.EquipItem(entityID, payload.ItemID)
+	case *ports.InventoryDropPayload:
+		return r.game.DropItem(entityID, payload.ItemID)
+	case *ports.InventoryPickUpPayload:
+		return r.game.PickUpItem(entityID, state.EntityID(payload.PickupID))
+	case *ports.InventoryMovePayload:
+		return r.game.MoveItem(entityID, payload.ItemID, payload.Slot)
+	default:
+		return fmt.Errorf("invalid inventory payload %T", action.Payload)
+	}
+}
+
+// sendInventoriesIfChanged sends every player its inventory and the items
+// within reach when they differ from what it was sent last.
+func (r *Room) sendInventoriesIfChanged() {
+	for entityID, sessionID := range r.sessions.All() {
+		inventory, exist := r.game.Inventory(entityID)
+		if !exist {
+			continue
+		}
+
+		payload := ports.InventoryPayload{MaxSlots: inventory.MaxSlots}
+		for _, item := range inventory.Items() {
+			payload.Items = append(payload.Items, inventoryItem(item, inventory.Equipped))
+		}
+		for _, ground := range r.game.NearbyPickups(entityID) {
+			payload.Nearby = append(payload.Nearby, ports.GroundItem{
+				PickupID: uint64(ground.ID),
+				Item:     inventoryItem(ground.Item, ""),
+				X:        ground.Position.X,
+				Y:        ground.Position.Y,
+			})
+		}
+
+		if sent, exist := r.inventories[sessionID]; exist && reflect.DeepEqual(payload, sent) {
+			continue
+		}
+		r.inventories[sessionID] = payload
+
+		r.send([]string{sessionID}, ports.InventoryUpdateEnvelope, payload)
+	}
+}
 
-	envelope := ports.ResponseEnvelope{
-		EnvelopeType: ports.StaticDataEnvelope,
-		Payload:      payloadBytes,
+func inventoryItem(item weapons.Item, equipped string) ports.InventoryItem {
+	info := ports.InventoryItem{
+		ID:       item.ID(),
+		Kind:     string(item.Kind),
+		Equipped: equipped != "" && item.ID() == equipped,
+	}
+	switch item.Kind {
+	case weapons.ItemRanged:
+		if magazine := item.Pistol.CurrentMagazine; magazine != nil {
+			info.Ammo, info.Capacity = magazine.CurrentAmmo, magazine.MaxCapacity
+		}
+	case weapons.ItemMagazine:
+		info.Ammo, info.Capacity = item.Magazine.CurrentAmmo, item.Magazine.MaxCapacity
 	}
+	return info
+}
+
+func (r *Room) sendError(sessionID string, code int, message string) {
+	r.send([]string{sessionID}, ports.ErrorResponseEnvelope, ports.ErrorPayload{Code: code, Message: message})
+}
 
+// send queues a response for the given sessions.
+func (r *Room) send(sessionIDs []string, envelopeType ports.ResponseEnvelopeType, payload any) {
 	r.outgoing <- UpdateMessage{
-		ToSessions: sessionIDs,
-		Envelope:   envelope,
+		ToSessions:   sessionIDs,
+		EnvelopeType: envelopeType,
+		Payload:      payload,
 	}
 }
 
-// RemovePlayer removes a player from the game state and the registry.
-func (r *Room) RemovePlayer(sessionID string) {
-	if entityID, ok := r.sessions.EntityID(sessionID); ok {
-		r.sessions.Unregister(sessionID)
-		log.Printf("Player EntityID %d (Session %s) removed from room %s", entityID, sessionID, r.ID)
+// botKey is the scoreboard key of a bot, which has no session.
+func botKey(entityID state.EntityID) string {
+	return fmt.Sprintf("bot-%d", entityID)
+}
+
+// syncBots adds or removes bots so that humans and bots together reach the
+// configured bot count without exceeding the room capacity.
+// Must be called from the room goroutine.
+func (r *Room) syncBots() {
+	humans := r.PlayerCount()
+	want := max(r.config.BotCount-humans, 0)
+	if r.config.MaxPlayers > 0 {
+		want = min(want, max(r.config.MaxPlayers-humans, 0))
+	}
+
+	for len(r.bots) < want {
+		entityID, err := r.game.JoinBot()
+		if err != nil {
+			log.Printf("Failed to add bot to room %s: %v", r.ID, err)
+			return
+		}
+		r.bots = append(r.bots, entityID)
+		if r.rounds != nil {
+			r.rounds.Join(entityID)
+		}
+		r.scoreboard.Join(botKey(entityID), entityID, fmt.Sprintf("Bot %d", entityID), true)
+		log.Printf("Bot EntityID %d added to room %s", entityID, r.ID)
+	}
+
+	for len(r.bots) > want {
+		last := len(r.bots) - 1
+		r.game.RemoveEntity(r.bots[last])
+		if r.rounds != nil {
+			r.rounds.Leave(r.bots[last])
+		}
+		r.scoreboard.Remove(botKey(r.bots[last]))
+		log.Printf("Bot EntityID %d removed from room %s", r.bots[last], r.ID)
+		r.bots = r.bots[:last]
+	}
+}
+
+// BotCount returns the number of bots currently in the room.
+func (r *Room) BotCount() int {
+	return len(r.bots)
+}
+
+// RemovePlayer removes the player of a session for good, destroying its
+// entity and telling the other players it left.
+func (r *Room) RemovePlayer(sessionID string) error {
+	select {
+	case r.leavingSessions <- sessionID:
+		return nil
+	default:
+		return fmt.Errorf("room %s leave channel full, cannot remove session %s", r.ID, sessionID)
+	}
+}
+
+func (r *Room) removePlayer(sessionID string) {
+	entityID, ok := r.sessions.EntityID(sessionID)
+	if !ok {
+		return
+	}
+	name := r.scoreboard.Name(sessionID)
+
+	r.sessions.Unregister(sessionID)
+	r.game.RemoveEntity(entityID)
+	if r.rounds != nil {
+		r.rounds.Leave(entityID)
+	}
+	r.scoreboard.Remove(sessionID)
+	delete(r.inventories, sessionID)
+	delete(r.snapshots, sessionID)
+	log.Printf("Player EntityID %d (Session %s) removed from room %s", entityID, sessionID, r.ID)
+
+	if others := r.sessions.AllSessionIDs(); len(others) > 0 {
+		r.send(others, ports.PlayerLeftEnvelope, ports.PlayerLeftPayload{PlayerID: uint64(entityID), Name: name})
 	}
 }
 
@@ -250,41 +739,128 @@ func (r *Room) broadcastGameUpdate() {
 		if len(snapshot.Views) > 0 {
 			for i, view := range snapshot.Views {
 				viewInfo[i] = ports.PlayerInfo{
-					ID:  uint64(view.ID),
-					X:   view.Position.X,
-					Y:   view.Position.Y,
-					Dir: float64(view.Direction),
+					ID:     uint64(view.ID),
+					X:      view.Position.X,
+					Y:      view.Position.Y,
+					Dir:    float64(view.Direction),
+					Health: int(view.Health),
+					Enemy:  view.Enemy,
+					Team:   int(view.Team),
 				}
 			}
 		}
 
-		bytes, err := json.Marshal(ports.GameUpdatePayload{
+		var sounds []ports.SoundInfo
+		for _, sound := range snapshot.Sounds {
+			sounds = append(sounds, ports.SoundInfo{
+				Kind:     sound.Kind.String(),
+				Dir:      float64(sound.Direction),
+				Loudness: sound.Loudness,
+			})
+		}
+
+		var survivalInfo *ports.SurvivalInfo
+		if survival, ok := r.game.Survival(); ok {
+			survivalInfo = &ports.SurvivalInfo{
+				Phase:            survival.Phase.String(),
+				Wave:             survival.Wave,
+				TotalWaves:       survival.TotalWaves,
+				Countdown:        survival.Countdown,
+				EnemiesRemaining: survival.EnemiesRemaining,
+			}
+		}
+
+		var roundInfo *ports.RoundInfo
+		if r.rounds != nil {
+			roundInfo = r.rounds.Info()
+		}
+
+		var zoneInfo *ports.ZoneInfo
+		if zone, ok := r.game.Zone(); ok && zone.Active {
+			zoneInfo = &ports.ZoneInfo{
+				X:            zone.Center.X,
+				Y:            zone.Center.Y,
+				Radius:       zone.Radius,
+				NextX:        zone.NextCenter.X,
+				NextY:        zone.NextCenter.Y,
+				NextRadius:   zone.NextRadius,
+				TimeToShrink: zone.Timer,
+				Shrinking:    zone.Shrinking,
+				Phase:        zone.Phase,
+				Damage:       zone.Damage,
+			}
+		}
+
+		history, ok := r.snapshots[sessionID]
+		if !ok {
+			history = &snapshotHistory{}
+			r.snapshots[sessionID] = history
+		}
+		envelopeType, payload := history.Next(ports.GameUpdatePayload{
 			Me: ports.PlayerInfo{
-				ID:  uint64(entityID),
-				X:   snapshot.Player.Position.X,
-				Y:   snapshot.Player.Position.Y,
-				Dir: float64(snapshot.Player.Direction),
+				ID:      uint64(entityID),
+				X:       snapshot.Player.Position.X,
+				Y:       snapshot.Player.Position.Y,
+				Dir:     float64(snapshot.Player.Direction),
+				Health:  int(snapshot.Player.Health),
+				Team:    int(snapshot.Player.Team),
+				Stamina: staminaPercent(snapshot.Player.Stamina),
+				Effects: effectInfo(snapshot.Player.Effects),
 			},
+			Movement:  movementInfo(r.game, entityID),
 			Views:     viewInfo,
+			Sounds:    sounds,
+			Survival:  survivalInfo,
+			Round:     roundInfo,
+			Zone:      zoneInfo,
 			Timestamp: time.Now().UnixMilli(),
-		})
-		if err != nil {
-			// TODO: log not find
-			fmt.Println("Failed to marshal game update payload:", err)
-			continue
-		}
+		}, r.game.Tick())
+		r.send([]string{sessionID}, envelopeType, payload)
+	}
+}
 
-		r.outgoing <- UpdateMessage{
-			ToSessions: []string{sessionID},
-			Envelope: ports.ResponseEnvelope{
-				EnvelopeType: ports.GameUpdateEnvelope,
-				Payload:      bytes,
-			},
-		}
+// staminaPercent converts stamina to the rounded percentage shown on the HUD.
+func staminaPercent(stamina state.Stamina) int {
+	if stamina.Max <= 0 {
+		return 0
+	}
+	return int(math.Round(stamina.Current / stamina.Max * 100))
+}
 
+// movementInfo lets the client predict the moves of the player, nil for
+// players that cannot move.
+func movementInfo(game *engine.Game, entityID state.EntityID) *ports.MovementInfo {
+	snapshot, ok := game.Movement(entityID)
+	if !ok {
+		return nil
+	}
+	return &ports.MovementInfo{
+		LastInput:     snapshot.LastInput,
+		VX:            snapshot.Velocity.Linear.X,
+		VY:            snapshot.Velocity.Linear.Y,
+		Speed:         float64(snapshot.Speed),
+		RotationSpeed: float64(snapshot.RotationSpeed),
+		Radius:        snapshot.Radius,
+		Acceleration:  snapshot.Velocity.Acceleration,
+		Friction:      snapshot.Velocity.Friction,
+		MaxSpeed:      snapshot.Velocity.MaxSpeed,
+		CanSprint:     snapshot.CanSprint,
+		Stunned:       snapshot.Stunned,
 	}
 }
 
+func effectInfo(effects state.Effects) []ports.EffectInfo {
+	var info []ports.EffectInfo
+	for _, effect := range effects {
+		info = append(info, ports.EffectInfo{
+			Kind:      effect.Kind.String(),
+			Remaining: effect.Remaining,
+			Stacks:    effect.Stacks,
+		})
+	}
+	return info
+}
+
 func (r *Room) PlayerCount() int {
 	return r.sessions.Count()
 }
@@ -296,6 +872,14 @@ func (r *Room) Name() string {
 	return r.ID
 }
 
+// Mode returns the mode the room plays, survival or the name of its round mode.
+func (r *Room) Mode() string {
+	if r.rounds == nil {
+		return string(r.game.Mode())
+	}
+	return string(r.rounds.Mode().Name())
+}
+
 func (r *Room) MaxPlayers() int {
-	return 0
+	return r.config.MaxPlayers
 }
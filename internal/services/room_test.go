@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"survival/internal/engine"
+	"survival/internal/engine/ports"
+	"survival/internal/engine/vector"
+)
+
+func TestRoom_SyncBotsFillsEmptySlots(t *testing.T) {
+	ctx := context.Background()
+	room, err := NewRoomWithConfig(ctx, "test", engine.DefaultMapConfig(), RoomConfig{MaxPlayers: 4, BotCount: 3})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	room.syncBots()
+	if room.BotCount() != 3 {
+		t.Fatalf("Expected 3 bots in an empty room, got %d", room.BotCount())
+	}
+
+	playerID, err := room.game.JoinPlayer()
+	if err != nil {
+		t.Fatalf("Failed to join player: %v", err)
+	}
+	room.sessions.Register("session-1", playerID)
+
+	room.syncBots()
+	if room.BotCount() != 2 {
+		t.Errorf("Expected a bot to make room for the human, got %d bots", room.BotCount())
+	}
+}
+
+func TestRoom_SyncBotsRespectsMaxPlayers(t *testing.T) {
+	ctx := context.Background()
+	room, err := NewRoomWithConfig(ctx, "test", engine.DefaultMapConfig(), RoomConfig{MaxPlayers: 2, BotCount: 5})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	room.syncBots()
+	if room.BotCount() != 2 {
+		t.Errorf("Expected bots capped at 2, got %d", room.BotCount())
+	}
+}
+
+func TestRoom_InventoryActionsAndUpdates(t *testing.T) {
+	ctx := context.Background()
+	room, err := NewRoomWithConfig(ctx, "test", engine.DefaultMapConfig(), RoomConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	playerID, err := room.game.JoinPlayer()
+	if err != nil {
+		t.Fatalf("Failed to join player: %v", err)
+	}
+	room.sessions.Register("session-1", playerID)
+
+	room.sendInventoriesIfChanged()
+	msg := <-room.outgoing
+	inventory, ok := msg.Payload.(ports.InventoryPayload)
+	if !ok || msg.EnvelopeType != ports.InventoryUpdateEnvelope {
+		t.Fatalf("Expected an inventory update, got %s: %T", msg.EnvelopeType, msg.Payload)
+	}
+	if len(inventory.Items) != 4 || msg.ToSessions[0] != "session-1" {
+		t.Fatalf("Expected the starting loadout sent to its owner, got %+v", inventory)
+	}
+
+	room.sendInventoriesIfChanged()
+	if len(room.outgoing) != 0 {
+		t.Fatal("Expected no update while the inventory is unchanged")
+	}
+
+	if err := room.applyInventoryAction(inventoryAction{SessionID: "session-1", Payload: &ports.InventoryEquipPayload{ItemID: "missing"}}); err == nil {
+		t.Error("Expected equipping a missing item to be rejected")
+	}
+	knife := inventory.Items[0].ID
+	if err := room.applyInventoryAction(inventoryAction{SessionID: "session-1", Payload: &ports.InventoryDropPayload{ItemID: knife}}); err != nil {
+		t.Fatalf("Failed to drop the knife: %v", err)
+	}
+
+	room.sendInventoriesIfChanged()
+	msg = <-room.outgoing
+	if inventory, ok = msg.Payload.(ports.InventoryPayload); !ok {
+		t.Fatalf("Expected an inventory update, got %T", msg.Payload)
+	}
+	if len(inventory.Items) != 3 || len(inventory.Nearby) != 1 || inventory.Nearby[0].Item.ID != knife {
+		t.Errorf("Expected the knife on the ground next to the player, got %+v", inventory)
+	}
+}
+
+func TestRoom_BroadcastsRemovedWalls(t *testing.T) {
+	ctx := context.Background()
+	mapConfig := &engine.MapConfig{
+		Dimensions: vector.Vector2D{X: 100, Y: 100},
+		GridSize:   10,
+		Walls: []engine.WallConfig{
+			{ID: "crate", Center: vector.Vector2D{X: 50, Y: 40}, HalfSize: vector.Vector2D{X: 2, Y: 2}, Destructible: true, Health: 1},
+		},
+		SpawnPoints: []engine.SpawnPoint{{Position: vector.Vector2D{X: 50, Y: 50}}},
+	}
+	room, err := NewRoomWithConfig(ctx, "test", mapConfig, RoomConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	playerID, err := room.game.JoinPlayer()
+	if err != nil {
+		t.Fatalf("Failed to join player: %v", err)
+	}
+	room.sessions.Register("session-1", playerID)
+	wallID := room.game.Statics()[0].ID
+
+	room.game.Update(ports.DeltaTime)
+	room.broadcastStaticChanges()
+	if len(room.outgoing) != 0 {
+		t.Fatal("Expected no delta while the map is unchanged")
+	}
+
+	// players spawn facing the crate
+	room.game.SetPlayerInput(playerID, ports.PlayerInput{Fire: true})
+	room.game.Update(ports.DeltaTime)
+	room.broadcastStaticChanges()
+
+	msg := <-room.outgoing
+	delta, ok := msg.Payload.(ports.StaticDataDeltaPayload)
+	if !ok || msg.EnvelopeType != ports.StaticDataDeltaEnvelope {
+		t.Fatalf("Expected a static data delta, got %s: %T", msg.EnvelopeType, msg.Payload)
+	}
+	if len(delta.Removed) != 1 || delta.Removed[0] != uint64(wallID) || len(delta.Added) != 0 {
+		t.Errorf("Expected the crate removed, got %+v", delta)
+	}
+}
+
+// twoFloorMap is a lobby with stairs up to an office in front of the spawn point.
+func twoFloorMap() *engine.MapConfig {
+	return &engine.MapConfig{
+		Dimensions: vector.Vector2D{X: 100, Y: 100},
+		GridSize:   10,
+		Walls: []engine.WallConfig{
+			{ID: "lobby", Center: vector.Vector2D{X: 20, Y: 20}, HalfSize: vector.Vector2D{X: 2, Y: 2}},
+		},
+		Floors: []engine.FloorConfig{
+			{ID: "office", Walls: []engine.WallConfig{
+				{ID: "desk", Center: vector.Vector2D{X: 80, Y: 80}, HalfSize: vector.Vector2D{X: 2, Y: 2}},
+			}},
+		},
+		Stairs: []engine.StairConfig{
+			{ID: "stairwell", Center: vector.Vector2D{X: 50, Y: 55}, HalfSize: vector.Vector2D{X: 5, Y: 2}, From: 0, To: 1},
+		},
+		SpawnPoints: []engine.SpawnPoint{{Position: vector.Vector2D{X: 50, Y: 50}}},
+	}
+}
+
+func TestRoom_SendsFloorStaticDataOnFloorChange(t *testing.T) {
+	ctx := context.Background()
+	room, err := NewRoomWithConfig(ctx, "test", twoFloorMap(), RoomConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	playerID, err := room.game.JoinPlayer()
+	if err != nil {
+		t.Fatalf("Failed to join player: %v", err)
+	}
+	room.sessions.Register("session-1", playerID)
+
+	room.sendStaticData("session-1")
+	msg := <-room.outgoing
+	static, ok := msg.Payload.(ports.StaticDataPayload)
+	if !ok {
+		t.Fatalf("Expected static data, got %T", msg.Payload)
+	}
+	if static.Floor != 0 || static.FloorCount != 2 || len(static.Colliders) != 1 || len(static.Stairs) != 1 {
+		t.Fatalf("Expected the lobby and the stairs up, got %+v", static)
+	}
+
+	for range 120 {
+		room.game.SetPlayerInput(playerID, ports.PlayerInput{MoveVertical: 1})
+		room.game.Update(ports.DeltaTime)
+		room.sendFloorChanges()
+		if len(room.outgoing) > 0 {
+			break
+		}
+	}
+	if len(room.outgoing) == 0 {
+		t.Fatal("Expected static data once the player took the stairs")
+	}
+
+	msg = <-room.outgoing
+	if static, ok = msg.Payload.(ports.StaticDataPayload); !ok || msg.EnvelopeType != ports.StaticDataEnvelope {
+		t.Fatalf("Expected static data, got %s: %T", msg.EnvelopeType, msg.Payload)
+	}
+	if static.Floor != 1 || len(static.Colliders) != 1 || static.Colliders[0].X != 80 {
+		t.Errorf("Expected the office floor, got %+v", static)
+	}
+	if len(static.Stairs) != 1 || static.Stairs[0].To != 0 || static.Stairs[0].Kind != ports.StairKindStairs {
+		t.Errorf("Expected the stairs leading down, got %+v", static.Stairs)
+	}
+}
+
+// sessionClient is the client of a session, rooms only ask it for its
+// session and name.
+type sessionClient struct {
+	Client
+	sessionID string
+}
+
+func (c sessionClient) SessionID() string { return c.sessionID }
+
+func (c sessionClient) Name() string { return c.sessionID }
+
+// gameUpdateTypes drains the queued responses and returns the types of the
+// game updates among them.
+func gameUpdateTypes(room *Room) []ports.ResponseEnvelopeType {
+	var types []ports.ResponseEnvelopeType
+	for len(room.outgoing) > 0 {
+		msg := <-room.outgoing
+		if msg.EnvelopeType == ports.GameUpdateEnvelope || msg.EnvelopeType == ports.GameUpdateDeltaEnvelope {
+			types = append(types, msg.EnvelopeType)
+		}
+	}
+	return types
+}
+
+func TestRoom_ResumedSessionKeepsPlayerAndGetsFullUpdate(t *testing.T) {
+	ctx := context.Background()
+	room, err := NewRoomWithConfig(ctx, "test", engine.DefaultMapConfig(), RoomConfig{MaxPlayers: 1})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	client := sessionClient{sessionID: "session-1"}
+	if err := room.addPlayer(client); err != nil {
+		t.Fatalf("Failed to add player: %v", err)
+	}
+	playerID, _ := room.sessions.EntityID("session-1")
+
+	room.broadcastGameUpdate()
+	room.snapshots["session-1"].Ack(1)
+	room.broadcastGameUpdate()
+	if types := gameUpdateTypes(room); len(types) != 2 || types[1] != ports.GameUpdateDeltaEnvelope {
+		t.Fatalf("Expected a full update and a delta, got %v", types)
+	}
+
+	// the room is full, but the session already has its player
+	if err := room.addPlayer(client); err != nil {
+		t.Fatalf("Failed to resume session: %v", err)
+	}
+	if resumedID, _ := room.sessions.EntityID("session-1"); resumedID != playerID || room.PlayerCount() != 1 {
+		t.Errorf("Expected player %d resumed, got %d with %d players", playerID, resumedID, room.PlayerCount())
+	}
+
+	room.broadcastGameUpdate()
+	if types := gameUpdateTypes(room); len(types) != 1 || types[0] != ports.GameUpdateEnvelope {
+		t.Errorf("Expected the resumed connection to start with a full update, got %v", types)
+	}
+}
+
+func TestRoom_ResumedSessionGetsStaticDataOfItsFloor(t *testing.T) {
+	ctx := context.Background()
+	room, err := NewRoomWithConfig(ctx, "test", twoFloorMap(), RoomConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	client := sessionClient{sessionID: "session-1"}
+	if err := room.addPlayer(client); err != nil {
+		t.Fatalf("Failed to add player: %v", err)
+	}
+	playerID, _ := room.sessions.EntityID("session-1")
+	for range 120 {
+		room.game.SetPlayerInput(playerID, ports.PlayerInput{MoveVertical: 1})
+		room.game.Update(ports.DeltaTime)
+		if room.game.Floor(playerID) == 1 {
+			break
+		}
+	}
+	if room.game.Floor(playerID) != 1 {
+		t.Fatal("Expected the player to take the stairs up")
+	}
+	for len(room.outgoing) > 0 {
+		<-room.outgoing
+	}
+
+	if err := room.addPlayer(client); err != nil {
+		t.Fatalf("Failed to resume session: %v", err)
+	}
+	msg := <-room.outgoing
+	static, ok := msg.Payload.(ports.StaticDataPayload)
+	if !ok {
+		t.Fatalf("Expected static data first, got %s", msg.EnvelopeType)
+	}
+	if static.Floor != 1 || len(static.Colliders) != 1 || static.Colliders[0].X != 80 {
+		t.Errorf("Expected the office floor, got %+v", static)
+	}
+}
+
+func TestRoom_RemovePlayerDestroysEntityAndTellsOthers(t *testing.T) {
+	ctx := context.Background()
+	room, err := NewRoomWithConfig(ctx, "test", engine.DefaultMapConfig(), RoomConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	for _, sessionID := range []string{"session-1", "session-2"} {
+		if err := room.addPlayer(sessionClient{sessionID: sessionID}); err != nil {
+			t.Fatalf("Failed to add player: %v", err)
+		}
+	}
+	leaverID, _ := room.sessions.EntityID("session-1")
+	for len(room.outgoing) > 0 {
+		<-room.outgoing
+	}
+
+	room.removePlayer("session-1")
+	room.game.Update(ports.DeltaTime)
+
+	if room.game.IsAlive(leaverID) || room.PlayerCount() != 1 {
+		t.Errorf("Expected the entity of the leaver destroyed, alive: %v, players: %d", room.game.IsAlive(leaverID), room.PlayerCount())
+	}
+	msg := <-room.outgoing
+	left, ok := msg.Payload.(ports.PlayerLeftPayload)
+	if !ok || len(msg.ToSessions) != 1 || msg.ToSessions[0] != "session-2" {
+		t.Fatalf("Expected the other player told, got %s to %v", msg.EnvelopeType, msg.ToSessions)
+	}
+	if left.PlayerID != uint64(leaverID) || left.Name != "session-1" {
+		t.Errorf("Expected player %d named session-1 to have left, got %+v", leaverID, left)
+	}
+}
+
+func TestRoom_BroadcastsChangedLightCells(t *testing.T) {
+	ctx := context.Background()
+	mapConfig := engine.DefaultMapConfig()
+	mapConfig.Darkness = 0.8
+	room, err := NewRoomWithConfig(ctx, "test", mapConfig, RoomConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	defer room.Shutdown(ctx)
+
+	if err := room.addPlayer(sessionClient{sessionID: "session-1"}); err != nil {
+		t.Fatalf("Failed to add player: %v", err)
+	}
+	playerID, _ := room.sessions.EntityID("session-1")
+	room.game.Update(ports.DeltaTime)
+	room.lightLevels = room.game.LightMap().Levels
+	for len(room.outgoing) > 0 {
+		<-room.outgoing
+	}
+	sent := slices.Clone(room.lightLevels)
+
+	room.game.SetPlayerInput(playerID, ports.PlayerInput{ToggleFlashlight: true})
+	var deltas []ports.LightMapDeltaPayload
+	for range 2 * lightMapInterval {
+		room.game.Update(ports.DeltaTime)
+		room.broadcastLightMapIfChanged()
+		for len(room.outgoing) > 0 {
+			if delta, ok := (<-room.outgoing).Payload.(ports.LightMapDeltaPayload); ok {
+				deltas = append(deltas, delta)
+			}
+		}
+	}
+
+	// one delta at most every lightMapInterval ticks, with only the lit cells
+	if len(deltas) != 1 {
+		t.Fatalf("Expected one light map delta, got %d", len(deltas))
+	}
+	if n := len(deltas[0].Indexes); n == 0 || n >= len(sent) {
+		t.Errorf("Expected only the cells lit by the flashlight, got %d of %d", n, len(sent))
+	}
+	for i, index := range deltas[0].Indexes {
+		sent[index] = deltas[0].Levels[i]
+	}
+	if !slices.Equal(sent, room.game.LightMap().Levels) {
+		t.Error("Expected the delta to bring the light map up to date")
+	}
+}
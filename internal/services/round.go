@@ -0,0 +1,183 @@
+package services
+
+import (
+	"log"
+
+	"survival/internal/engine"
+	"survival/internal/engine/ports"
+	"survival/internal/engine/state"
+)
+
+type RoundPhase uint8
+
+const (
+	// RoundWarmup is free play while waiting for players and counting down to the round.
+	RoundWarmup RoundPhase = iota
+	// RoundLive is the round being played, kills count.
+	RoundLive
+	// RoundPostRound shows the result before the next round's warmup.
+	RoundPostRound
+)
+
+func (p RoundPhase) String() string {
+	switch p {
+	case RoundLive:
+		return ports.RoundPhaseLive
+	case RoundPostRound:
+		return ports.RoundPhasePostRound
+	default:
+		return ports.RoundPhaseWarmup
+	}
+}
+
+// Rounds drives the round lifecycle of a room: a warmup until enough players
+// joined and its countdown ran out, a live round until the mode decides it or
+// time runs out, and a post-round pause showing the result before the next
+// round's warmup. Everyone respawns when a round goes live. It is not safe for
+// concurrent use and is driven from the room goroutine.
+type Rounds struct {
+	mode    Mode
+	game    *engine.Game
+	players map[state.EntityID]struct{}
+
+	phase   RoundPhase
+	number  int
+	timer   float64
+	waiting bool
+	result  RoundResult
+}
+
+func NewRounds(mode Mode, game *engine.Game) *Rounds {
+	return &Rounds{
+		mode:    mode,
+		game:    game,
+		players: make(map[state.EntityID]struct{}),
+		phase:   RoundWarmup,
+		number:  1,
+		waiting: true,
+	}
+}
+
+func (r *Rounds) Mode() Mode { return r.mode }
+
+func (r *Rounds) Phase() RoundPhase { return r.phase }
+
+// Number is the current round, starting at 1.
+func (r *Rounds) Number() int { return r.number }
+
+// Timer returns the seconds left in the current phase.
+func (r *Rounds) Timer() float64 { return r.timer }
+
+// Result is the outcome of the last round, only meaningful after it ended.
+func (r *Rounds) Result() RoundResult { return r.result }
+
+// Join adds a player to the rounds. In team modes it is put in the team with
+// the fewest members, the lower team on ties.
+func (r *Rounds) Join(id state.EntityID) {
+	team := state.NoTeam
+	if teams := r.mode.Teams(); teams > 0 {
+		members := make([]int, teams+1)
+		for player := range r.players {
+			if t := r.game.Team(player); t != state.NoTeam && int(t) <= teams {
+				members[t]++
+			}
+		}
+
+		team = 1
+		for t := 2; t <= teams; t++ {
+			if members[t] < members[team] {
+				team = state.Team(t)
+			}
+		}
+		r.game.JoinTeam(id, team)
+	}
+
+	r.players[id] = struct{}{}
+	r.mode.OnJoin(id, team)
+}
+
+func (r *Rounds) Leave(id state.EntityID) {
+	if _, ok := r.players[id]; !ok {
+		return
+	}
+	delete(r.players, id)
+	r.mode.OnLeave(id)
+}
+
+// Update advances the phase timers and passes the kills of the last game update to the mode.
+func (r *Rounds) Update(dt float64, events []state.CombatEvent) {
+	rules := r.mode.Rules()
+
+	switch r.phase {
+	case RoundWarmup:
+		if len(r.players) < rules.MinPlayers {
+			r.waiting = true
+			r.timer = 0
+			return
+		}
+		if r.waiting {
+			r.waiting = false
+			r.timer = rules.Warmup
+		}
+		r.timer -= dt
+		if r.timer <= 0 {
+			r.startLive()
+		}
+
+	case RoundLive:
+		for _, event := range events {
+			if event.Kind == state.CombatKill {
+				r.mode.OnKill(event.Attacker, event.Victim)
+			}
+		}
+
+		decided := r.mode.OnTick(r.game, dt)
+		if rules.TimeLimit > 0 {
+			r.timer -= dt
+			decided = decided || r.timer <= 0
+		}
+		if decided {
+			r.endRound()
+		}
+
+	case RoundPostRound:
+		r.timer -= dt
+		if r.timer <= 0 {
+			r.number++
+			r.phase = RoundWarmup
+			r.waiting = true
+			r.timer = 0
+			r.result = RoundResult{}
+			r.game.SetRespawnEnabled(true)
+			log.Printf("Round %d warming up (%s)", r.number, r.mode.Name())
+		}
+	}
+}
+
+func (r *Rounds) startLive() {
+	r.phase = RoundLive
+	r.timer = r.mode.Rules().TimeLimit
+	r.game.RespawnAll()
+	r.game.SetRespawnEnabled(r.mode.Respawn())
+	r.mode.OnRoundStart(r.game)
+	log.Printf("Round %d live (%s)", r.number, r.mode.Name())
+}
+
+func (r *Rounds) endRound() {
+	r.result = r.mode.OnRoundEnd(r.game)
+	r.phase = RoundPostRound
+	r.timer = r.mode.Rules().PostRound
+	log.Printf("Round %d over (%s), result %+v", r.number, r.mode.Name(), r.result)
+}
+
+// Info returns the round progress sent to clients.
+func (r *Rounds) Info() *ports.RoundInfo {
+	return &ports.RoundInfo{
+		Mode:       string(r.mode.Name()),
+		Phase:      r.phase.String(),
+		Round:      r.number,
+		Timer:      max(r.timer, 0),
+		Winner:     uint64(r.result.Winner),
+		WinnerTeam: r.result.WinnerTeam,
+	}
+}
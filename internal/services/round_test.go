@@ -0,0 +1,194 @@
+package services
+
+import (
+	"testing"
+
+	"survival/internal/engine"
+	"survival/internal/engine/state"
+)
+
+func newTestRounds(t *testing.T, name ModeName, players int) (*Rounds, *engine.Game, []state.EntityID) {
+	t.Helper()
+
+	game, err := engine.NewGame(engine.DefaultMapConfig())
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	mode, err := NewMode(name)
+	if err != nil {
+		t.Fatalf("Failed to create mode: %v", err)
+	}
+
+	rounds := NewRounds(mode, game)
+	ids := make([]state.EntityID, players)
+	for i := range ids {
+		if ids[i], err = game.JoinPlayer(); err != nil {
+			t.Fatalf("Failed to join player: %v", err)
+		}
+		rounds.Join(ids[i])
+	}
+	return rounds, game, ids
+}
+
+func kill(killer, victim state.EntityID) state.CombatEvent {
+	return state.CombatEvent{Kind: state.CombatKill, Attacker: killer, Victim: victim}
+}
+
+func TestRounds_WarmupWaitsForPlayers(t *testing.T) {
+	rounds, _, _ := newTestRounds(t, ModeDeathmatch, 1)
+	rules := rounds.Mode().Rules()
+
+	rounds.Update(rules.Warmup*2, nil)
+	if rounds.Phase() != RoundWarmup || rounds.Timer() != 0 {
+		t.Fatalf("Expected to wait for a second player, got %v with %.1fs", rounds.Phase(), rounds.Timer())
+	}
+	if info := rounds.Info(); info.Phase != "warmup" || info.Mode != "deathmatch" {
+		t.Errorf("Expected warmup info for deathmatch, got %+v", info)
+	}
+
+	rounds.Join(state.EntityID(99))
+	rounds.Update(1, nil)
+	if rounds.Phase() != RoundWarmup || rounds.Timer() != rules.Warmup-1 {
+		t.Fatalf("Expected the warmup countdown to run, got %v with %.1fs", rounds.Phase(), rounds.Timer())
+	}
+
+	rounds.Update(rules.Warmup, nil)
+	if rounds.Phase() != RoundLive || rounds.Timer() != rules.TimeLimit {
+		t.Errorf("Expected the round to go live, got %v with %.1fs", rounds.Phase(), rounds.Timer())
+	}
+}
+
+func TestRounds_DeathmatchFragLimitEndsRound(t *testing.T) {
+	rounds, _, ids := newTestRounds(t, ModeDeathmatch, 2)
+	rules := rounds.Mode().Rules()
+	rounds.Update(rules.Warmup, nil)
+
+	// kills during warmup do not count
+	events := make([]state.CombatEvent, defaultDeathmatchFragLimit-1)
+	for i := range events {
+		events[i] = kill(ids[1], ids[0])
+	}
+	rounds.Update(0.1, events)
+	if rounds.Phase() != RoundLive {
+		t.Fatalf("Expected the round to go on below the frag limit")
+	}
+
+	rounds.Update(0.1, []state.CombatEvent{kill(ids[1], ids[0])})
+	if rounds.Phase() != RoundPostRound || rounds.Result().Winner != ids[1] {
+		t.Fatalf("Expected player %d to win at the frag limit, got %v %+v", ids[1], rounds.Phase(), rounds.Result())
+	}
+
+	rounds.Update(rules.PostRound, nil)
+	if rounds.Phase() != RoundWarmup || rounds.Number() != 2 {
+		t.Errorf("Expected the warmup of round 2, got %v round %d", rounds.Phase(), rounds.Number())
+	}
+	if rounds.Result() != (RoundResult{}) {
+		t.Errorf("Expected the result cleared for the next round")
+	}
+}
+
+func TestRounds_TimeLimitEndsInDraw(t *testing.T) {
+	rounds, _, _ := newTestRounds(t, ModeDeathmatch, 2)
+	rules := rounds.Mode().Rules()
+	rounds.Update(rules.Warmup, nil)
+
+	rounds.Update(rules.TimeLimit, nil)
+	if rounds.Phase() != RoundPostRound || rounds.Result() != (RoundResult{}) {
+		t.Errorf("Expected a draw when time runs out without kills, got %v %+v", rounds.Phase(), rounds.Result())
+	}
+}
+
+func TestTeamDeathmatch_BalancesTeamsAndPunishesTeamKills(t *testing.T) {
+	rounds, game, ids := newTestRounds(t, ModeTeamDeathmatch, 4)
+	mode := rounds.Mode().(*teamDeathmatch)
+
+	var members [teamCount + 1]int
+	for _, id := range ids {
+		if game.Team(id) != mode.Team(id) {
+			t.Fatalf("Expected the game and the mode to agree on the team of %d", id)
+		}
+		members[mode.Team(id)]++
+	}
+	if members[1] != 2 || members[2] != 2 {
+		t.Fatalf("Expected two players per team, got %v", members)
+	}
+
+	var ally, enemy state.EntityID
+	for _, id := range ids[1:] {
+		if mode.Team(id) == mode.Team(ids[0]) {
+			ally = id
+		} else {
+			enemy = id
+		}
+	}
+
+	mode.OnKill(ids[0], enemy)
+	mode.OnKill(ids[0], enemy)
+	mode.OnKill(ids[0], ally)
+	if got := mode.scores[mode.Team(ids[0])]; got != 1 {
+		t.Errorf("Expected two kills minus a team kill, got %d", got)
+	}
+	if result := mode.OnRoundEnd(game); result.WinnerTeam != int(mode.Team(ids[0])) {
+		t.Errorf("Expected team %d to win, got %+v", mode.Team(ids[0]), result)
+	}
+}
+
+func TestLastManStanding_NoRespawnAndLastAliveWins(t *testing.T) {
+	rounds, game, ids := newTestRounds(t, ModeLastManStanding, 3)
+	rules := rounds.Mode().Rules()
+	rounds.Update(rules.Warmup, nil)
+	if rounds.Phase() != RoundLive {
+		t.Fatalf("Expected the round to go live")
+	}
+
+	game.RemoveEntity(ids[0])
+	game.Update(0.1)
+	rounds.Update(0.1, nil)
+	if rounds.Phase() != RoundLive {
+		t.Fatalf("Expected the round to go on with two players alive")
+	}
+
+	game.RemoveEntity(ids[1])
+	game.Update(0.1)
+	rounds.Update(0.1, nil)
+	if rounds.Phase() != RoundPostRound || rounds.Result().Winner != ids[2] {
+		t.Errorf("Expected player %d to win as the last one alive, got %v %+v", ids[2], rounds.Phase(), rounds.Result())
+	}
+}
+
+func TestBattleRoyale_ZoneRunsDuringLiveRounds(t *testing.T) {
+	game, err := engine.NewGameWithConfig(engine.DefaultMapConfig(), engine.GameConfig{SafeZone: true})
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	mode, _ := NewMode(ModeBattleRoyale)
+	rounds := NewRounds(mode, game)
+	for range 2 {
+		id, _ := game.JoinPlayer()
+		rounds.Join(id)
+	}
+
+	rules := mode.Rules()
+	if zone, _ := game.Zone(); zone.Active {
+		t.Fatalf("Expected no zone during the warmup")
+	}
+
+	rounds.Update(rules.Warmup, nil)
+	if zone, _ := game.Zone(); rounds.Phase() != RoundLive || !zone.Active || zone.Phase != 1 {
+		t.Fatalf("Expected the zone to start with the round, got %v %+v", rounds.Phase(), zone)
+	}
+
+	rounds.Update(rules.TimeLimit, nil)
+	if zone, _ := game.Zone(); rounds.Phase() != RoundPostRound || zone.Active {
+		t.Errorf("Expected the zone removed after the round, got %v %+v", rounds.Phase(), zone)
+	}
+}
+
+func TestNewMode_RejectsUnknownModes(t *testing.T) {
+	if _, err := NewMode("capture_the_flag"); err == nil {
+		t.Errorf("Expected an error for an unknown mode")
+	}
+	if mode, err := NewMode(""); err != nil || mode.Name() != ModeDeathmatch {
+		t.Errorf("Expected deathmatch by default, got %v %v", mode, err)
+	}
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"cmp"
+	"slices"
+
+	"survival/internal/engine/state"
+)
+
+// assistWindow is how long after damaging a victim a player is credited with
+// an assist when someone else kills it.
+const assistWindow = 10.0
+
+// PlayerStats are the statistics of one player in the current match. Shots
+// and Hits only count fired weapons, Damage counts all damage dealt to others.
+type PlayerStats struct {
+	Kills   int
+	Deaths  int
+	Assists int
+	Damage  int
+	Shots   int
+	Hits    int
+}
+
+// Accuracy is the share of shots that hit, 0 without shots.
+func (s PlayerStats) Accuracy() float64 {
+	if s.Shots == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Shots)
+}
+
+// ScoreboardEntry is a player listed on the scoreboard.
+type ScoreboardEntry struct {
+	Key      string
+	EntityID state.EntityID
+	Name     string
+	Bot      bool
+	Stats    PlayerStats
+}
+
+type scoreboardPlayer struct {
+	entityID  state.EntityID
+	name      string
+	bot       bool
+	connected bool
+	stats     PlayerStats
+}
+
+// Scoreboard tracks the statistics of a room from its combat events. Players
+// are keyed by their session, so a player reconnecting with a new entity keeps
+// its numbers. Version changes whenever the listed statistics do. It is not
+// safe for concurrent use and is driven from the room goroutine.
+type Scoreboard struct {
+	players map[string]*scoreboardPlayer
+	keys    map[state.EntityID]string
+
+	// recent is the time each attacker last damaged a victim, for assists
+	recent  map[state.EntityID]map[state.EntityID]float64
+	clock   float64
+	version uint64
+}
+
+func NewScoreboard() *Scoreboard {
+	return &Scoreboard{
+		players: make(map[string]*scoreboardPlayer),
+		keys:    make(map[state.EntityID]string),
+		recent:  make(map[state.EntityID]map[state.EntityID]float64),
+	}
+}
+
+// Join lists a player under its key, keeping the statistics of a returning one.
+func (s *Scoreboard) Join(key string, entityID state.EntityID, name string, bot bool) {
+	player, exist := s.players[key]
+	if !exist {
+		player = &scoreboardPlayer{}
+		s.players[key] = player
+	}
+	if player.connected {
+		delete(s.keys, player.entityID)
+	}
+
+	player.entityID = entityID
+	player.name = name
+	player.bot = bot
+	player.connected = true
+	s.keys[entityID] = key
+	s.version++
+}
+
+// Leave stops listing a player. Its statistics are kept for when it comes back.
+func (s *Scoreboard) Leave(key string) {
+	player, exist := s.players[key]
+	if !exist || !player.connected {
+		return
+	}
+	player.connected = false
+	delete(s.keys, player.entityID)
+	delete(s.recent, player.entityID)
+	s.version++
+}
+
+// Name returns the name a player is listed under.
+func (s *Scoreboard) Name(key string) string {
+	if player, exist := s.players[key]; exist {
+		return player.name
+	}
+	return ""
+}
+
+// Remove forgets a player and its statistics, used for bots and expired sessions.
+func (s *Scoreboard) Remove(key string) {
+	s.Leave(key)
+	delete(s.players, key)
+}
+
+// Reset clears the statistics of everyone for a new match.
+func (s *Scoreboard) Reset() {
+	for _, player := range s.players {
+		player.stats = PlayerStats{}
+	}
+	clear(s.recent)
+	s.version++
+}
+
+// Record advances the assist clock and adds the combat events of the last
+// game update. Events of entities not on the scoreboard, such as survival
+// enemies, only count for the listed side.
+func (s *Scoreboard) Record(dt float64, events []state.CombatEvent) {
+	s.clock += dt
+
+	for _, event := range events {
+		attacker := s.stats(event.Attacker)
+		selfInflicted := event.Attacker == event.Victim
+
+		switch event.Kind {
+		case state.CombatShot:
+			if attacker != nil {
+				attacker.Shots++
+			}
+
+		case state.CombatHit:
+			if attacker == nil || selfInflicted {
+				continue
+			}
+			if event.Shot {
+				attacker.Hits++
+			}
+			attacker.Damage += event.Damage
+			if s.recent[event.Victim] == nil {
+				s.recent[event.Victim] = make(map[state.EntityID]float64)
+			}
+			s.recent[event.Victim][event.Attacker] = s.clock
+
+		case state.CombatKill:
+			if victim := s.stats(event.Victim); victim != nil {
+				victim.Deaths++
+			}
+			if attacker != nil && !selfInflicted {
+				attacker.Kills++
+			}
+			for assistant, at := range s.recent[event.Victim] {
+				if assistant == event.Attacker || s.clock-at > assistWindow {
+					continue
+				}
+				if stats := s.stats(assistant); stats != nil {
+					stats.Assists++
+				}
+			}
+			delete(s.recent, event.Victim)
+
+		default:
+			continue
+		}
+		s.version++
+	}
+}
+
+func (s *Scoreboard) stats(id state.EntityID) *PlayerStats {
+	key, exist := s.keys[id]
+	if !exist {
+		return nil
+	}
+	return &s.players[key].stats
+}
+
+// Stats returns the statistics kept under a key.
+func (s *Scoreboard) Stats(key string) (PlayerStats, bool) {
+	player, exist := s.players[key]
+	if !exist {
+		return PlayerStats{}, false
+	}
+	return player.stats, true
+}
+
+// Entries returns the listed players ordered by kills, then fewer deaths, then damage.
+func (s *Scoreboard) Entries() []ScoreboardEntry {
+	entries := make([]ScoreboardEntry, 0, len(s.keys))
+	for key, player := range s.players {
+		if !player.connected {
+			continue
+		}
+		entries = append(entries, ScoreboardEntry{
+			Key:      key,
+			EntityID: player.entityID,
+			Name:     player.name,
+			Bot:      player.bot,
+			Stats:    player.stats,
+		})
+	}
+
+	slices.SortFunc(entries, func(a, b ScoreboardEntry) int {
+		return cmp.Or(
+			cmp.Compare(b.Stats.Kills, a.Stats.Kills),
+			cmp.Compare(a.Stats.Deaths, b.Stats.Deaths),
+			cmp.Compare(b.Stats.Damage, a.Stats.Damage),
+			cmp.Compare(a.EntityID, b.EntityID),
+		)
+	})
+	return entries
+}
+
+// Version changes whenever the listed statistics change.
+func (s *Scoreboard) Version() uint64 {
+	return s.version
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+
+	"survival/internal/engine/state"
+)
+
+func shot(attacker state.EntityID) state.CombatEvent {
+	return state.CombatEvent{Kind: state.CombatShot, Attacker: attacker}
+}
+
+func hit(attacker, victim state.EntityID, damage int) state.CombatEvent {
+	return state.CombatEvent{Kind: state.CombatHit, Attacker: attacker, Victim: victim, Damage: damage, Shot: true}
+}
+
+func TestScoreboard_CountsKillsDeathsAssistsAndAccuracy(t *testing.T) {
+	scoreboard := NewScoreboard()
+	scoreboard.Join("alice", 1, "Alice", false)
+	scoreboard.Join("bob", 2, "Bob", false)
+	scoreboard.Join("carol", 3, "Carol", false)
+
+	scoreboard.Record(0.1, []state.CombatEvent{
+		shot(1), hit(1, 3, 40),
+		shot(1),
+		shot(2), hit(2, 3, 60), kill(2, 3),
+		// bleeding adds damage but no hit
+		{Kind: state.CombatHit, Attacker: 1, Victim: 2, Damage: 5},
+	})
+
+	alice, _ := scoreboard.Stats("alice")
+	if alice.Assists != 1 || alice.Damage != 45 || alice.Kills != 0 {
+		t.Errorf("Expected an assist and 45 damage for alice, got %+v", alice)
+	}
+	if alice.Accuracy() != 0.5 {
+		t.Errorf("Expected alice to hit half her shots, got %.2f", alice.Accuracy())
+	}
+	if bob, _ := scoreboard.Stats("bob"); bob.Kills != 1 || bob.Assists != 0 || bob.Accuracy() != 1 {
+		t.Errorf("Expected a kill without assist for bob, got %+v", bob)
+	}
+	if carol, _ := scoreboard.Stats("carol"); carol.Deaths != 1 {
+		t.Errorf("Expected a death for carol, got %+v", carol)
+	}
+
+	entries := scoreboard.Entries()
+	if len(entries) != 3 || entries[0].Key != "bob" {
+		t.Errorf("Expected bob on top of 3 entries, got %+v", entries)
+	}
+}
+
+func TestScoreboard_AssistsExpire(t *testing.T) {
+	scoreboard := NewScoreboard()
+	scoreboard.Join("alice", 1, "Alice", false)
+	scoreboard.Join("bob", 2, "Bob", false)
+
+	scoreboard.Record(0.1, []state.CombatEvent{hit(1, 3, 40)})
+	scoreboard.Record(assistWindow+1, []state.CombatEvent{kill(2, 3)})
+
+	if alice, _ := scoreboard.Stats("alice"); alice.Assists != 0 {
+		t.Errorf("Expected no assist for damage older than the window, got %+v", alice)
+	}
+}
+
+func TestScoreboard_ReconnectKeepsStatsAndResetClears(t *testing.T) {
+	scoreboard := NewScoreboard()
+	scoreboard.Join("alice", 1, "Alice", false)
+	scoreboard.Record(0.1, []state.CombatEvent{kill(1, 9)})
+
+	scoreboard.Leave("alice")
+	if len(scoreboard.Entries()) != 0 {
+		t.Fatalf("Expected a disconnected player not to be listed")
+	}
+
+	// the same session comes back with a new entity
+	scoreboard.Join("alice", 5, "Alice", false)
+	scoreboard.Record(0.1, []state.CombatEvent{kill(5, 9)})
+	if alice, _ := scoreboard.Stats("alice"); alice.Kills != 2 {
+		t.Errorf("Expected the kills of both connections, got %+v", alice)
+	}
+
+	version := scoreboard.Version()
+	scoreboard.Reset()
+	if alice, _ := scoreboard.Stats("alice"); alice != (PlayerStats{}) {
+		t.Errorf("Expected the stats cleared for the next match, got %+v", alice)
+	}
+	if scoreboard.Version() == version {
+		t.Errorf("Expected the version to change on reset")
+	}
+}
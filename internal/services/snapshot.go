@@ -0,0 +1,64 @@
+package services
+
+import (
+	"math"
+
+	"survival/internal/engine/ports"
+)
+
+// snapshotAck is a session acknowledging the game update numbered Sequence.
+type snapshotAck struct {
+	SessionID string
+	Sequence  uint32
+}
+
+// snapshotHistory numbers the game updates sent to a session and keeps the
+// last ones, so that the next update can be sent as a delta against the one
+// the client acked last. The game tick of each update tells what the client
+// saw when it acked it.
+type snapshotHistory struct {
+	sequence uint32
+	acked    uint32 // 0 until the client acks an update
+	sent     [ports.SnapshotHistorySize]ports.GameUpdatePayload
+	ticks    [ports.SnapshotHistorySize]uint64
+}
+
+// Ack records that the client received the update numbered sequence. Acks
+// arriving out of order and for updates never sent are ignored.
+func (h *snapshotHistory) Ack(sequence uint32) {
+	if sequence > h.acked && sequence <= h.sequence {
+		h.acked = sequence
+	}
+}
+
+// ViewTick returns the game tick the client drew the other players at: the
+// tick of the update it acked last, less its interpolation delay in
+// milliseconds. It is 0 before the client acks an update, once the ack left
+// the history or when the delay reaches back before the first tick.
+func (h *snapshotHistory) ViewTick(interpolationDelay uint32) uint64 {
+	if h.acked == 0 || h.sequence-h.acked >= ports.SnapshotHistorySize {
+		return 0
+	}
+	tick := h.ticks[h.acked%ports.SnapshotHistorySize]
+	delay := uint64(math.Round(float64(interpolationDelay) * ports.TargetTickRate / 1000))
+	if delay >= tick {
+		return 0
+	}
+	return tick - delay
+}
+
+// Next numbers the update showing the game as of tick and returns it as a
+// delta against the acked update, or in full when the client has not acked
+// one recent enough, after joining or once updates went missing for a while.
+func (h *snapshotHistory) Next(update ports.GameUpdatePayload, tick uint64) (ports.ResponseEnvelopeType, any) {
+	h.sequence++
+	update.Sequence = h.sequence
+	ports.QuantizeViews(&update)
+	h.sent[h.sequence%ports.SnapshotHistorySize] = update
+	h.ticks[h.sequence%ports.SnapshotHistorySize] = tick
+
+	if h.acked == 0 || h.sequence-h.acked >= ports.SnapshotHistorySize {
+		return ports.GameUpdateEnvelope, update
+	}
+	return ports.GameUpdateDeltaEnvelope, ports.DiffGameUpdate(h.sent[h.acked%ports.SnapshotHistorySize], update)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"survival/internal/engine/ports"
+)
+
+func gameUpdate(views ...ports.PlayerInfo) ports.GameUpdatePayload {
+	return ports.GameUpdatePayload{
+		Me:    ports.PlayerInfo{ID: 1, X: 10.123, Y: 20.456, Health: 100},
+		Views: views,
+	}
+}
+
+func TestSnapshotHistory_DeltasAgainstAckedUpdate(t *testing.T) {
+	var history snapshotHistory
+	alice := ports.PlayerInfo{ID: 2, X: 30.3, Y: 40.4, Dir: 1, Health: 100}
+	bob := ports.PlayerInfo{ID: 3, X: 50, Y: 60, Dir: -math.Pi / 2, Health: 80, Team: 1}
+
+	envelopeType, payload := history.Next(gameUpdate(alice, bob), 0)
+	full, ok := payload.(ports.GameUpdatePayload)
+	if envelopeType != ports.GameUpdateEnvelope || !ok || full.Sequence != 1 {
+		t.Fatalf("Expected the first update in full, got %s %+v", envelopeType, payload)
+	}
+	if full.Views[0].X != 30.296875 || full.Views[1].Dir != 3*math.Pi/2 {
+		t.Errorf("Expected the views quantized, got %+v", full.Views)
+	}
+
+	// not acked yet, the next one is full as well
+	if envelopeType, _ := history.Next(gameUpdate(alice, bob), 0); envelopeType != ports.GameUpdateEnvelope {
+		t.Fatalf("Expected a full update until the client acks, got %s", envelopeType)
+	}
+
+	history.Ack(1)
+	alice.X += 0.5
+	carol := ports.PlayerInfo{ID: 4, X: 5, Y: 5, Health: 100}
+	next := gameUpdate(alice, carol)
+	envelopeType, payload = history.Next(next, 0)
+	delta, ok := payload.(ports.GameUpdateDeltaPayload)
+	if envelopeType != ports.GameUpdateDeltaEnvelope || !ok || delta.Sequence != 3 || delta.Baseline != 1 {
+		t.Fatalf("Expected a delta against update 1, got %s %+v", envelopeType, payload)
+	}
+	if len(delta.Views) != 2 || delta.Views[0].Fields != ports.PlayerFieldPosition || delta.Views[0].DX != 32 ||
+		delta.Views[1].Fields != ports.PlayerFieldAll {
+		t.Errorf("Expected alice's move and carol in full, got %+v", delta.Views)
+	}
+	if !reflect.DeepEqual(delta.Removed, []uint64{3}) {
+		t.Errorf("Expected bob out of view, got %+v", delta.Removed)
+	}
+
+	next.Sequence = 3
+	ports.QuantizeViews(&next)
+	if rebuilt := delta.Apply(full); !reflect.DeepEqual(rebuilt, next) {
+		t.Errorf("Expected the delta to rebuild the update\ngot  %+v\nwant %+v", rebuilt, next)
+	}
+}
+
+func TestSnapshotHistory_FullUpdateOnceAckTooOld(t *testing.T) {
+	var history snapshotHistory
+	history.Next(gameUpdate(), 0)
+	history.Ack(1)
+
+	// acks for updates never sent or older than the last one are ignored
+	history.Ack(5)
+	for range ports.SnapshotHistorySize - 1 {
+		if envelopeType, _ := history.Next(gameUpdate(), 0); envelopeType != ports.GameUpdateDeltaEnvelope {
+			t.Fatalf("Expected deltas while the ack is recent, got %s", envelopeType)
+		}
+	}
+	history.Ack(1)
+
+	envelopeType, payload := history.Next(gameUpdate(), 0)
+	if envelopeType != ports.GameUpdateEnvelope {
+		t.Fatalf("Expected a full update once the acked one left the history, got %s", envelopeType)
+	}
+	history.Ack(payload.(ports.GameUpdatePayload).Sequence)
+	if envelopeType, _ := history.Next(gameUpdate(), 0); envelopeType != ports.GameUpdateDeltaEnvelope {
+		t.Errorf("Expected deltas again after acking the full update, got %s", envelopeType)
+	}
+}
+
+func TestSnapshotHistory_ViewTickOfAckedUpdate(t *testing.T) {
+	var history snapshotHistory
+	history.Next(gameUpdate(), 100)
+	history.Next(gameUpdate(), 101)
+	if tick := history.ViewTick(0); tick != 0 {
+		t.Fatalf("Expected no view tick before an ack, got %d", tick)
+	}
+
+	history.Ack(1)
+	if tick := history.ViewTick(0); tick != 100 {
+		t.Errorf("Expected the tick of the acked update, got %d", tick)
+	}
+	// 100 ms of interpolation are 6 ticks at 60 ticks a second
+	if tick := history.ViewTick(100); tick != 94 {
+		t.Errorf("Expected the tick drawn 100ms behind the acked update, got %d", tick)
+	}
+	// 2 seconds reach back before the game started
+	if tick := history.ViewTick(2000); tick != 0 {
+		t.Errorf("Expected no view tick for a delay longer than the game, got %d", tick)
+	}
+	for range ports.SnapshotHistorySize {
+		history.Next(gameUpdate(), 102)
+	}
+	if tick := history.ViewTick(0); tick != 0 {
+		t.Errorf("Expected no view tick once the ack is too old, got %d", tick)
+	}
+}
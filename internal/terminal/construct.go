@@ -11,6 +11,7 @@ const (
 	ResetCursor = "\033[H"
 	ClearScreen = "\033[2J"
 	ClearToEnd  = "\033[J"
+	Bell        = "\a"
 )
 
 type GameState interface {
@@ -35,4 +36,10 @@ const (
 	InputTurnRight
 	InputAction
 	InputCancel
+	InputToggleFlashlight
+	InputFire
+	InputSprintForward
+	InputToggleScoreboard
+	InputInventory
+	InputDrop
 )
@@ -21,6 +21,10 @@ type LocaleData struct {
 
 	SettingScreenSize string
 	SettingLanguage   string
+	SettingBell       string
+	SettingDelay      string
+	ValueOn           string
+	ValueOff          string
 	SizeSmall         string
 	SizeMedium        string
 	SizeLarge         string
@@ -31,8 +35,43 @@ type LocaleData struct {
 	SPWaitingRoom  string
 	SPJoiningRoom  string
 	SPDisconnected string
+	SPReconnecting string
 	SPError        string
 	SPStatusHint   string
+
+	WaveCountdown string // wave, total waves, seconds
+	WaveActive    string // wave, total waves, enemies remaining
+	WaveWon       string // seconds until reset
+	WaveLost      string // seconds until reset
+
+	RoundWaiting   string
+	RoundWarmup    string // round, seconds
+	RoundLive      string // round, minutes, seconds
+	RoundYouWon    string // seconds until next round
+	RoundPlayerWon string // player id, seconds until next round
+	RoundTeamWon   string // team, seconds until next round
+	RoundDraw      string // seconds until next round
+
+	ZoneShrinkIn  string // phase, seconds
+	ZoneShrinking string // phase
+	ZoneOutside   string // damage per second
+
+	FloorStatus string // floor, floor count
+	FloorStair  string // stair name, floor
+	StairName   string
+	LadderName  string
+
+	PlayerLeft string // player name
+
+	InventoryTitle    string
+	InventorySlots    string // used slots, max slots
+	InventoryNearby   string
+	InventoryEmpty    string
+	InventoryEquipped string
+	InventoryHint     string
+	ItemMelee         string
+	ItemRanged        string
+	ItemMagazine      string
 }
 
 var (
@@ -57,6 +96,10 @@ var (
 
 		SettingScreenSize: "螢幕尺寸",
 		SettingLanguage:   "語言",
+		SettingBell:       "槍聲提示音",
+		SettingDelay:      "插值延遲",
+		ValueOn:           "開",
+		ValueOff:          "關",
 		SizeSmall:         "120 x 32 (小)",
 		SizeMedium:        "160 x 40 (中)",
 		SizeLarge:         "200 x 50 (大)",
@@ -67,8 +110,43 @@ var (
 		SPWaitingRoom:  "等待房間...",
 		SPJoiningRoom:  "加入房間中...",
 		SPDisconnected: "連線中斷",
+		SPReconnecting: "重新連線中...",
 		SPError:        "錯誤",
-		SPStatusHint:   "WASD 移動, Q/E 轉向, ESC 返回",
+		SPStatusHint:   "WASD 移動, Q/E 轉向, 空白鍵 射擊, F 手電筒, Tab 計分板, I 背包, ESC 返回",
+
+		WaveCountdown: "第 %d/%d 波 %d 秒後來襲",
+		WaveActive:    "第 %d/%d 波 剩餘敵人: %d",
+		WaveWon:       "存活成功! %d 秒後重新開始",
+		WaveLost:      "全員陣亡 %d 秒後重新開始",
+
+		RoundWaiting:   "熱身中 等待其他玩家",
+		RoundWarmup:    "第 %d 回合 %d 秒後開始",
+		RoundLive:      "第 %d 回合 %d:%02d",
+		RoundYouWon:    "你贏了! %d 秒後下一回合",
+		RoundPlayerWon: "玩家 %d 獲勝 %d 秒後下一回合",
+		RoundTeamWon:   "隊伍 %d 獲勝 %d 秒後下一回合",
+		RoundDraw:      "平手 %d 秒後下一回合",
+
+		ZoneShrinkIn:  "安全區 %d: %d 秒後縮小",
+		ZoneShrinking: "安全區 %d: 縮小中",
+		ZoneOutside:   "你在安全區外! 每秒 -%d HP",
+
+		FloorStatus: "%d 樓 / 共 %d 層",
+		FloorStair:  "%s 通往 %d 樓",
+		StairName:   "樓梯",
+		LadderName:  "梯子",
+
+		PlayerLeft: "%s 已離開遊戲",
+
+		InventoryTitle:    "背包 (INVENTORY)",
+		InventorySlots:    "空間 %d/%d",
+		InventoryNearby:   "附近物品",
+		InventoryEmpty:    "(無)",
+		InventoryEquipped: "裝備中",
+		InventoryHint:     "方向鍵選擇, Enter 裝備/撿起, X 丟棄, Q/E 排序, Esc 返回",
+		ItemMelee:         "小刀",
+		ItemRanged:        "手槍",
+		ItemMagazine:      "彈匣",
 	}
 
 	LangEN = LocaleData{
@@ -92,6 +170,10 @@ var (
 
 		SettingScreenSize: "Screen Size",
 		SettingLanguage:   "Language",
+		SettingBell:       "Gunshot Bell",
+		SettingDelay:      "Interp. Delay",
+		ValueOn:           "On",
+		ValueOff:          "Off",
 		SizeSmall:         "120 x 32 (Small)",
 		SizeMedium:        "160 x 40 (Medium)",
 		SizeLarge:         "200 x 50 (Large)",
@@ -102,7 +184,42 @@ var (
 		SPWaitingRoom:  "Waiting for room...",
 		SPJoiningRoom:  "Joining room...",
 		SPDisconnected: "Disconnected",
+		SPReconnecting: "Reconnecting...",
 		SPError:        "Error",
-		SPStatusHint:   "WASD move, Q/E turn, ESC back",
+		SPStatusHint:   "WASD move, Q/E turn, Space fire, F flashlight, Tab scores, I inventory, ESC back",
+
+		WaveCountdown: "Wave %d/%d in %ds",
+		WaveActive:    "Wave %d/%d - Enemies left: %d",
+		WaveWon:       "You survived! Restarting in %ds",
+		WaveLost:      "Everyone is dead. Restarting in %ds",
+
+		RoundWaiting:   "Warmup - waiting for players",
+		RoundWarmup:    "Round %d starts in %ds",
+		RoundLive:      "Round %d - %d:%02d",
+		RoundYouWon:    "You won! Next round in %ds",
+		RoundPlayerWon: "Player %d won. Next round in %ds",
+		RoundTeamWon:   "Team %d won. Next round in %ds",
+		RoundDraw:      "Draw. Next round in %ds",
+
+		ZoneShrinkIn:  "Zone %d: shrinks in %ds",
+		ZoneShrinking: "Zone %d: shrinking",
+		ZoneOutside:   "OUTSIDE THE ZONE! -%d HP/s",
+
+		FloorStatus: "Floor %d/%d",
+		FloorStair:  "%s to floor %d",
+		StairName:   "Stairs",
+		LadderName:  "Ladder",
+
+		PlayerLeft: "%s left the game",
+
+		InventoryTitle:    "INVENTORY",
+		InventorySlots:    "Slots %d/%d",
+		InventoryNearby:   "Nearby",
+		InventoryEmpty:    "(none)",
+		InventoryEquipped: "equipped",
+		InventoryHint:     "Arrows select, Enter equip/pick up, X drop, Q/E reorder, Esc back",
+		ItemMelee:         "Knife",
+		ItemRanged:        "Pistol",
+		ItemMagazine:      "Magazine",
 	}
 )
@@ -8,9 +8,10 @@ import (
 )
 
 var AppDefaultConfig = GameConfig{
-	Height: 32,
-	Width:  120,
-	Locale: LangTW,
+	Height:             32,
+	Width:              120,
+	Locale:             LangTW,
+	InterpolationDelay: 100 * time.Millisecond,
 }
 
 func NewGameManager(fd int, logger *slog.Logger, inputChan chan KeyEvent, initialState GameState) *GameManager {
@@ -65,8 +66,10 @@ func parseInput(input KeyEvent) InputEvent {
 		return InputAction
 	case "\033": // Esc
 		return InputCancel
-	case "w", "W":
+	case "w":
 		return InputMoveForward
+	case "W": // Shift+W
+		return InputSprintForward
 	case "s", "S":
 		return InputMoveBackward
 	case "a", "A":
@@ -77,6 +80,16 @@ func parseInput(input KeyEvent) InputEvent {
 		return InputTurnLeft
 	case "e", "E":
 		return InputTurnRight
+	case "f", "F":
+		return InputToggleFlashlight
+	case " ":
+		return InputFire
+	case "\t": // Tab
+		return InputToggleScoreboard
+	case "i", "I":
+		return InputInventory
+	case "x", "X":
+		return InputDrop
 	}
 	return InputNone
 }
@@ -151,7 +164,14 @@ type Command struct {
 }
 
 type GameConfig struct {
-	Height int
-	Width  int
-	Locale LocaleData
+	Height       int
+	Width        int
+	Locale       LocaleData
+	TerminalBell bool
+	// InterpolationDelay is how far in the past other players are drawn, so
+	// they move smoothly between game updates.
+	InterpolationDelay time.Duration
+	// Username and Password log in to the server.
+	Username string
+	Password string
 }
@@ -1,15 +1,22 @@
 package network
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 
 	"survival/internal/engine/ports"
+	"survival/internal/utils"
 )
 
 type ConnectionState int
@@ -19,72 +26,264 @@ const (
 	StateConnecting
 	StateConnected
 	StateError
+	// StateReconnecting is a lost connection being resumed, see Client.reconnect.
+	StateReconnecting
 )
 
+const (
+	// maxReconnectAttempts is how often a lost connection is redialed before
+	// the client gives up, about half a minute.
+	maxReconnectAttempts = 10
+	// reconnectBaseDelay doubles with every attempt up to reconnectMaxDelay.
+	reconnectBaseDelay = 250 * time.Millisecond
+	reconnectMaxDelay  = 5 * time.Second
+)
+
+// connection is an open websocket with the codec and message type of the
+// subprotocol the server picked. It is replaced as a whole on reconnect.
+type connection struct {
+	ws          *websocket.Conn
+	codec       ports.Codec
+	messageType int
+}
+
 type Client struct {
-	conn      *websocket.Conn
+	// conn is the current connection, nil before the first dial
+	conn      atomic.Pointer[connection]
 	state     ConnectionState
 	stateMu   sync.RWMutex
-	clientID  string
 	sessionID string
 	lastError error
 
+	// token is the login token connections present, serverAddr is kept to
+	// reconnect and resumeSession is the session asked for while reconnecting.
+	token         string
+	serverAddr    string
+	resumeSession string
+
+	// subprotocols are offered to the server in order of preference
+	subprotocols []string
+	// writeMu serializes writes, acks are sent from the read loop
+	writeMu sync.Mutex
+
+	// snapshots holds the latest game updates by sequence, the baselines of
+	// the deltas the server sends.
+	snapshots [ports.SnapshotHistorySize]ports.GameUpdatePayload
+	// sentInputs are the last numbered inputs, resent with the next ones
+	sentInputs []ports.PlayerInput
+	// lightMap is the light map with the deltas since applied
+	lightMap ports.LightMapPayload
+
+	// rtt and clock are measured by pinging the server every
+	// ports.PingInterval, the server adds its time to the pongs.
+	rtt   utils.RTTStats
+	clock utils.ClockSync
+
 	gameUpdateChan  chan ports.GameUpdatePayload
 	staticDataChan  chan ports.StaticDataPayload
+	staticDeltaChan chan ports.StaticDataDeltaPayload
+	lightMapChan    chan ports.LightMapPayload
 	roomListChan    chan ports.ListRoomsResponse
+	scoreboardChan  chan ports.ScoreboardPayload
+	inventoryChan   chan ports.InventoryPayload
 	joinSuccessChan chan string
 	errorChan       chan error
+	rejectionChan   chan string
+	reconnectedChan chan bool
+	playerLeftChan  chan ports.PlayerLeftPayload
 
 	closeChan chan struct{}
 	closeOnce sync.Once
 }
 
-func NewClient(clientID string) *Client {
+func NewClient() *Client {
 	return &Client{
-		clientID:        clientID,
 		state:           StateDisconnected,
+		subprotocols:    []string{ports.SubprotocolBinary, ports.SubprotocolJSON},
 		gameUpdateChan:  make(chan ports.GameUpdatePayload, 10),
 		staticDataChan:  make(chan ports.StaticDataPayload, 1),
+		staticDeltaChan: make(chan ports.StaticDataDeltaPayload, 32),
+		lightMapChan:    make(chan ports.LightMapPayload, 1),
 		roomListChan:    make(chan ports.ListRoomsResponse, 1),
+		scoreboardChan:  make(chan ports.ScoreboardPayload, 1),
+		inventoryChan:   make(chan ports.InventoryPayload, 1),
 		joinSuccessChan: make(chan string, 1),
 		errorChan:       make(chan error, 10),
+		rejectionChan:   make(chan string, 10),
+		reconnectedChan: make(chan bool, 1),
+		playerLeftChan:  make(chan ports.PlayerLeftPayload, 10),
 		closeChan:       make(chan struct{}),
 	}
 }
 
-func (c *Client) Connect(serverAddr string, playerName string) error {
+// SetSubprotocols replaces the subprotocols offered on Connect, such as only
+// ports.SubprotocolJSON to read the messages while debugging.
+func (c *Client) SetSubprotocols(subprotocols ...string) {
+	c.subprotocols = subprotocols
+}
+
+// Subprotocol returns the subprotocol the server picked, empty for plain JSON.
+func (c *Client) Subprotocol() string {
+	conn := c.conn.Load()
+	if conn == nil {
+		return ""
+	}
+	return conn.ws.Subprotocol()
+}
+
+// Login logs in to the server for a token to connect with. The server binds
+// the sessions of the client to the user it verified.
+func (c *Client) Login(serverAddr, username, password string) error {
+	body, err := json.Marshal(ports.LoginRequest{Username: username, Password: password})
+	if err != nil {
+		return err
+	}
+
+	u := url.URL{Scheme: "http", Host: serverAddr, Path: "/login"}
+	httpClient := http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Post(u.String(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("login rejected: %s", strings.TrimSpace(string(message)))
+	}
+	var login ports.LoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&login); err != nil {
+		return fmt.Errorf("failed to read login response: %w", err)
+	}
+
+	c.token = login.Token
+	return nil
+}
+
+// Connect connects to the server with the token of Login. Once the server
+// assigned a session, a lost connection is resumed automatically, see
+// ReconnectedChan.
+func (c *Client) Connect(serverAddr string) error {
 	c.stateMu.Lock()
 	c.state = StateConnecting
 	c.stateMu.Unlock()
 
+	c.serverAddr = serverAddr
+	if err := c.dial(); err != nil {
+		c.stateMu.Lock()
+		c.state = StateError
+		c.lastError = err
+		c.stateMu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// dial opens a connection, asking to resume the session when there is one.
+func (c *Client) dial() error {
+	query := url.Values{}
+	if sessionID := c.SessionID(); sessionID != "" {
+		query.Set("session_id", sessionID)
+	}
 	u := url.URL{
 		Scheme:   "ws",
-		Host:     serverAddr,
+		Host:     c.serverAddr,
 		Path:     "/ws",
-		RawQuery: fmt.Sprintf("client_id=%s&name=%s", url.QueryEscape(c.clientID), url.QueryEscape(playerName)),
+		RawQuery: query.Encode(),
 	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = c.subprotocols
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+c.token)
+	ws, resp, err := dialer.Dial(u.String(), header)
 	if err != nil {
-		c.stateMu.Lock()
-		c.state = StateError
-		c.lastError = err
-		c.stateMu.Unlock()
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("connection unauthorized, log in again: %w", err)
+		}
 		return err
 	}
 
-	c.conn = conn
+	conn := &connection{ws: ws, codec: utils.NewJsonCodec(), messageType: websocket.TextMessage}
+	if ws.Subprotocol() == ports.SubprotocolBinary {
+		conn.codec, conn.messageType = utils.NewBinaryCodec(), websocket.BinaryMessage
+	}
+	// the server starts the new connection over with a full update
+	c.snapshots = [ports.SnapshotHistorySize]ports.GameUpdatePayload{}
+	c.setHeartbeatHandlers(ws)
+	if old := c.conn.Swap(conn); old != nil {
+		old.ws.Close()
+	}
+	// a Close racing the dial missed the new connection
+	select {
+	case <-c.closeChan:
+		ws.Close()
+		return fmt.Errorf("client closed")
+	default:
+	}
 	c.stateMu.Lock()
 	c.state = StateConnected
 	c.stateMu.Unlock()
 
-	go c.readLoop()
+	done := make(chan struct{})
+	go c.readLoop(conn, done)
+	go c.pingLoop(ws, done)
 
 	return nil
 }
 
-func (c *Client) readLoop() {
+// setHeartbeatHandlers measures the round trip and the server clock from the
+// pongs, answers the pings of the server and lets the connection fail once
+// the server was silent for ports.IdleTimeout.
+func (c *Client) setHeartbeatHandlers(conn *websocket.Conn) {
+	conn.SetPongHandler(func(data string) error {
+		received := time.Now()
+		if sent, server, ok := utils.ParsePong([]byte(data)); ok {
+			c.rtt.Add(received.Sub(sent))
+			if !server.IsZero() {
+				c.clock.Add(sent, server, received)
+			}
+		}
+		return extendDeadline(conn)
+	})
+	conn.SetPingHandler(func(data string) error {
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		if err != nil && err != websocket.ErrCloseSent {
+			return err
+		}
+		return extendDeadline(conn)
+	})
+	extendDeadline(conn)
+}
+
+func extendDeadline(conn *websocket.Conn) error {
+	return conn.SetReadDeadline(time.Now().Add(ports.IdleTimeout))
+}
+
+// pingLoop pings the server right away and then every ports.PingInterval
+// until the read loop of the connection is done.
+func (c *Client) pingLoop(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(ports.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		// control frames may be written next to other writes, a failed ping
+		// is left to the read deadline
+		conn.WriteControl(websocket.PingMessage, utils.PingPayload(time.Now()), time.Now().Add(time.Second))
+
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		case <-c.closeChan:
+			return
+		}
+	}
+}
+
+func (c *Client) readLoop(conn *connection, done chan<- struct{}) {
 	defer func() {
+		close(done)
 		c.stateMu.Lock()
 		if c.state == StateConnected {
 			c.state = StateDisconnected
@@ -99,11 +298,22 @@ func (c *Client) readLoop() {
 		default:
 		}
 
-		_, message, err := c.conn.ReadMessage()
+		_, message, err := conn.ws.ReadMessage()
 		if err != nil {
+			select {
+			case <-c.closeChan:
+				return
+			default:
+			}
 			c.stateMu.Lock()
-			c.state = StateError
 			c.lastError = err
+			if c.sessionID != "" {
+				c.state = StateReconnecting
+				c.stateMu.Unlock()
+				go c.reconnect()
+				return
+			}
+			c.state = StateError
 			c.stateMu.Unlock()
 			select {
 			case c.errorChan <- err:
@@ -112,50 +322,190 @@ func (c *Client) readLoop() {
 			return
 		}
 
+		extendDeadline(conn.ws)
+
 		var envelope ports.ResponseEnvelope
-		if err := json.Unmarshal(message, &envelope); err != nil {
+		if err := conn.codec.Decode(message, &envelope); err != nil {
 			continue
 		}
 
-		c.handleMessage(envelope)
+		c.handleMessage(conn.codec, envelope)
+	}
+}
+
+// reconnect redials the server with a backoff until the session is resumed,
+// the client is closed or it runs out of attempts.
+func (c *Client) reconnect() {
+	for attempt := range maxReconnectAttempts {
+		select {
+		case <-time.After(reconnectDelay(attempt)):
+		case <-c.closeChan:
+			return
+		}
+
+		c.resumeSession = c.SessionID()
+		err := c.dial()
+		if err == nil {
+			return
+		}
+		c.stateMu.Lock()
+		c.lastError = err
+		c.stateMu.Unlock()
+	}
+
+	c.stateMu.Lock()
+	c.state = StateError
+	err := c.lastError
+	c.stateMu.Unlock()
+	select {
+	case c.errorChan <- fmt.Errorf("failed to reconnect after %d attempts: %w", maxReconnectAttempts, err):
+	default:
+	}
+}
+
+// reconnectDelay is the wait before a reconnect attempt, 0 being the first.
+func reconnectDelay(attempt int) time.Duration {
+	return min(reconnectBaseDelay<<attempt, reconnectMaxDelay)
+}
+
+// applyLightMapDelta updates the light levels of the changed cells. Deltas
+// arriving before the light map they apply to are dropped.
+func (c *Client) applyLightMapDelta(delta ports.LightMapDeltaPayload) {
+	levels := slices.Clone(c.lightMap.Levels)
+	for i, index := range delta.Indexes {
+		if index < 0 || index >= len(levels) || i >= len(delta.Levels) {
+			return
+		}
+		levels[index] = delta.Levels[i]
+	}
+	c.lightMap.Levels = levels
+	c.sendLightMap()
+}
+
+// sendLightMap hands the light map over to the game. Its levels are replaced
+// rather than changed by the next delta.
+func (c *Client) sendLightMap() {
+	select {
+	case c.lightMapChan <- c.lightMap:
+	default:
 	}
 }
 
-func (c *Client) handleMessage(envelope ports.ResponseEnvelope) {
+func (c *Client) handleMessage(codec ports.Codec, envelope ports.ResponseEnvelope) {
 	switch envelope.EnvelopeType {
 	case ports.SystemSetSessionEnvelope:
 		var payload ports.SystemSetSessionPayload
-		if err := json.Unmarshal(envelope.Payload, &payload); err == nil {
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			if c.resumeSession != "" {
+				resumed := payload.SessionID == c.resumeSession
+				c.resumeSession = ""
+				select {
+				case c.reconnectedChan <- resumed:
+				default:
+				}
+			}
+			c.stateMu.Lock()
 			c.sessionID = payload.SessionID
+			c.stateMu.Unlock()
 		}
 
 	case ports.GameUpdateEnvelope:
 		var payload ports.GameUpdatePayload
-		if err := json.Unmarshal(envelope.Payload, &payload); err == nil {
-			select {
-			case c.gameUpdateChan <- payload:
-			default:
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			c.receiveGameUpdate(payload)
+		}
+
+	case ports.GameUpdateDeltaEnvelope:
+		var payload ports.GameUpdateDeltaPayload
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			baseline := c.snapshots[payload.Baseline%ports.SnapshotHistorySize]
+			// without the baseline the delta is dropped, the server falls back
+			// to a full update once the last ack gets too old
+			if baseline.Sequence != payload.Baseline {
+				return
 			}
+			c.receiveGameUpdate(payload.Apply(baseline))
 		}
 
 	case ports.StaticDataEnvelope:
 		var payload ports.StaticDataPayload
-		if err := json.Unmarshal(envelope.Payload, &payload); err == nil {
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			// static data is sent again on every floor change, only the latest one matters
+			select {
+			case <-c.staticDataChan:
+			default:
+			}
+			c.staticDataChan <- payload
+		}
+
+	case ports.StaticDataDeltaEnvelope:
+		var payload ports.StaticDataDeltaPayload
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
 			select {
-			case c.staticDataChan <- payload:
+			case c.staticDeltaChan <- payload:
 			default:
 			}
 		}
 
+	case ports.LightMapEnvelope:
+		var payload ports.LightMapPayload
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			c.lightMap = payload
+			c.sendLightMap()
+		}
+
+	case ports.LightMapDeltaEnvelope:
+		var payload ports.LightMapDeltaPayload
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			c.applyLightMapDelta(payload)
+		}
+
 	case ports.ListRoomsResponseEnvelope:
 		var payload ports.ListRoomsResponse
-		if err := json.Unmarshal(envelope.Payload, &payload); err == nil {
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
 			select {
 			case c.roomListChan <- payload:
 			default:
 			}
 		}
 
+	case ports.ScoreboardEnvelope:
+		var payload ports.ScoreboardPayload
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			// only the latest scoreboard matters
+			select {
+			case <-c.scoreboardChan:
+			default:
+			}
+			select {
+			case c.scoreboardChan <- payload:
+			default:
+			}
+		}
+
+	case ports.InventoryUpdateEnvelope:
+		var payload ports.InventoryPayload
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			// only the latest inventory matters
+			select {
+			case <-c.inventoryChan:
+			default:
+			}
+			select {
+			case c.inventoryChan <- payload:
+			default:
+			}
+		}
+
+	case ports.PlayerLeftEnvelope:
+		var payload ports.PlayerLeftPayload
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			select {
+			case c.playerLeftChan <- payload:
+			default:
+			}
+		}
+
 	case ports.JoinRoomSuccessEnvelope:
 		select {
 		case c.joinSuccessChan <- "success":
@@ -164,7 +514,15 @@ func (c *Client) handleMessage(envelope ports.ResponseEnvelope) {
 
 	case ports.ErrorResponseEnvelope:
 		var payload ports.ErrorPayload
-		if err := json.Unmarshal(envelope.Payload, &payload); err == nil {
+		if err := codec.Decode(envelope.Payload, &payload); err == nil {
+			// a rejected request leaves the connection usable
+			if payload.Code == ports.ErrorCodeBadRequest {
+				select {
+				case c.rejectionChan <- payload.Message:
+				default:
+				}
+				return
+			}
 			select {
 			case c.errorChan <- fmt.Errorf("server error: %s", payload.Message):
 			default:
@@ -173,6 +531,23 @@ func (c *Client) handleMessage(envelope ports.ResponseEnvelope) {
 	}
 }
 
+// receiveGameUpdate keeps the update as a baseline for the next deltas, acks
+// it and passes it on.
+func (c *Client) receiveGameUpdate(payload ports.GameUpdatePayload) {
+	c.snapshots[payload.Sequence%ports.SnapshotHistorySize] = payload
+	if err := c.sendRequest(ports.SnapshotAckEnvelope, ports.SnapshotAckPayload{Sequence: payload.Sequence}); err != nil {
+		select {
+		case c.errorChan <- fmt.Errorf("failed to ack game update: %w", err):
+		default:
+		}
+	}
+
+	select {
+	case c.gameUpdateChan <- payload:
+	default:
+	}
+}
+
 func (c *Client) RequestRoomList() error {
 	return c.sendRequest(ports.ListRoomsEnvelope, ports.ListRoomsPayload{})
 }
@@ -181,9 +556,44 @@ func (c *Client) RequestJoinRoom(roomID string) error {
 	return c.sendRequest(ports.RequestJoinEnvelope, ports.RequestJoinPayload{RoomID: roomID})
 }
 
+func (c *Client) RequestScoreboard() error {
+	return c.sendRequest(ports.ScoreboardRequestEnvelope, ports.ScoreboardRequestPayload{})
+}
+
+func (c *Client) EquipItem(itemID string) error {
+	return c.sendRequest(ports.InventoryEquipEnvelope, ports.InventoryEquipPayload{ItemID: itemID})
+}
+
+func (c *Client) DropItem(itemID string) error {
+	return c.sendRequest(ports.InventoryDropEnvelope, ports.InventoryDropPayload{ItemID: itemID})
+}
+
+func (c *Client) PickUpItem(pickupID uint64) error {
+	return c.sendRequest(ports.InventoryPickUpEnvelope, ports.InventoryPickUpPayload{PickupID: pickupID})
+}
+
+func (c *Client) MoveItem(itemID string, slot int) error {
+	return c.sendRequest(ports.InventoryMoveEnvelope, ports.InventoryMovePayload{ItemID: itemID, Slot: slot})
+}
+
+// SendInput sends the input. Numbered inputs carry the last
+// ports.InputRedundancy numbered inputs along, so a lost packet is made up
+// for by the next one.
 func (c *Client) SendInput(input ports.PlayerInput) error {
-	input.Timestamp = time.Now().UnixMilli()
-	return c.sendRequest(ports.PlayerInputEnvelope, input)
+	input.Timestamp = c.clock.RemoteTime(time.Now()).UnixMilli()
+	if input.Sequence == 0 {
+		return c.sendRequest(ports.PlayerInputEnvelope, input)
+	}
+
+	input.Previous = c.sentInputs
+	err := c.sendRequest(ports.PlayerInputEnvelope, input)
+
+	input.Previous = nil
+	c.sentInputs = append(c.sentInputs, input)
+	if len(c.sentInputs) > ports.InputRedundancy {
+		c.sentInputs = c.sentInputs[len(c.sentInputs)-ports.InputRedundancy:]
+	}
+	return err
 }
 
 func (c *Client) sendRequest(envelopeType ports.RequestEnvelopeType, payload interface{}) error {
@@ -191,11 +601,12 @@ func (c *Client) sendRequest(envelopeType ports.RequestEnvelopeType, payload int
 	state := c.state
 	c.stateMu.RUnlock()
 
-	if state != StateConnected {
+	conn := c.conn.Load()
+	if state != StateConnected || conn == nil {
 		return fmt.Errorf("not connected")
 	}
 
-	payloadBytes, err := json.Marshal(payload)
+	payloadBytes, err := conn.codec.Encode(payload)
 	if err != nil {
 		return err
 	}
@@ -205,12 +616,14 @@ func (c *Client) sendRequest(envelopeType ports.RequestEnvelopeType, payload int
 		Payload:      payloadBytes,
 	}
 
-	data, err := json.Marshal(envelope)
+	data, err := conn.codec.Encode(envelope)
 	if err != nil {
 		return err
 	}
 
-	return c.conn.WriteMessage(websocket.TextMessage, data)
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return conn.ws.WriteMessage(conn.messageType, data)
 }
 
 func (c *Client) GameUpdateChan() <-chan ports.GameUpdatePayload {
@@ -221,10 +634,52 @@ func (c *Client) StaticDataChan() <-chan ports.StaticDataPayload {
 	return c.staticDataChan
 }
 
+// StaticDeltaChan returns the changes to the static data received on join.
+func (c *Client) StaticDeltaChan() <-chan ports.StaticDataDeltaPayload {
+	return c.staticDeltaChan
+}
+
+func (c *Client) LightMapChan() <-chan ports.LightMapPayload {
+	return c.lightMapChan
+}
+
 func (c *Client) RoomListChan() <-chan ports.ListRoomsResponse {
 	return c.roomListChan
 }
 
+func (c *Client) ScoreboardChan() <-chan ports.ScoreboardPayload {
+	return c.scoreboardChan
+}
+
+func (c *Client) InventoryChan() <-chan ports.InventoryPayload {
+	return c.inventoryChan
+}
+
+// RejectionChan returns the messages of requests the server rejected.
+func (c *Client) RejectionChan() <-chan string {
+	return c.rejectionChan
+}
+
+// ReconnectedChan tells whether a lost connection got its session back after
+// reconnecting. A resumed session is put back in its room and gets the join
+// success, static data and a full game update again. Otherwise the server
+// gave the client a new session that has yet to join a room.
+func (c *Client) ReconnectedChan() <-chan bool {
+	return c.reconnectedChan
+}
+
+// PlayerLeftChan returns the players that left the room for good.
+func (c *Client) PlayerLeftChan() <-chan ports.PlayerLeftPayload {
+	return c.playerLeftChan
+}
+
+// SessionID returns the session the server assigned, empty before it did.
+func (c *Client) SessionID() string {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return c.sessionID
+}
+
 func (c *Client) JoinSuccessChan() <-chan string {
 	return c.joinSuccessChan
 }
@@ -233,6 +688,22 @@ func (c *Client) ErrorChan() <-chan error {
 	return c.errorChan
 }
 
+// RTT returns the round trip times to the server measured so far.
+func (c *Client) RTT() ports.RTT {
+	return c.rtt.Stats()
+}
+
+// ClockOffset returns how far the server clock is ahead of the local one.
+func (c *Client) ClockOffset() time.Duration {
+	return c.clock.Offset()
+}
+
+// LocalTime translates a Timestamp field, unix milliseconds of the server
+// clock, to the local clock.
+func (c *Client) LocalTime(timestamp int64) time.Time {
+	return c.clock.LocalTime(time.UnixMilli(timestamp))
+}
+
 func (c *Client) State() ConnectionState {
 	c.stateMu.RLock()
 	defer c.stateMu.RUnlock()
@@ -248,8 +719,8 @@ func (c *Client) LastError() error {
 func (c *Client) Close() {
 	c.closeOnce.Do(func() {
 		close(c.closeChan)
-		if c.conn != nil {
-			c.conn.Close()
+		if conn := c.conn.Load(); conn != nil {
+			conn.ws.Close()
 		}
 	})
 }
@@ -0,0 +1,112 @@
+package network
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"survival/internal/engine/ports"
+)
+
+// testServer accepts websocket connections, assigns every one the session
+// "session-1" and drops the first right after. Requests read on the later
+// connections are passed on.
+func testServer(t *testing.T, requests chan<- ports.RequestEnvelope) *httptest.Server {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		conns int
+	)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		mu.Lock()
+		conns++
+		first := conns == 1
+		mu.Unlock()
+
+		payload, _ := json.Marshal(ports.SystemSetSessionPayload{SessionID: "session-1"})
+		ws.WriteJSON(ports.ResponseEnvelope{EnvelopeType: ports.SystemSetSessionEnvelope, Payload: payload})
+		if first {
+			time.Sleep(50 * time.Millisecond)
+			return
+		}
+
+		for {
+			var envelope ports.RequestEnvelope
+			if err := ws.ReadJSON(&envelope); err != nil {
+				return
+			}
+			select {
+			case requests <- envelope:
+			default:
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_ReconnectsWhileSending(t *testing.T) {
+	requests := make(chan ports.RequestEnvelope, 16)
+	server := testServer(t, requests)
+
+	client := NewClient()
+	client.SetSubprotocols(ports.SubprotocolJSON)
+	defer client.Close()
+	if err := client.Connect(strings.TrimPrefix(server.URL, "http://")); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+
+	// the game keeps sending and asking for the subprotocol while the client reconnects
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			client.SendInput(ports.PlayerInput{})
+			client.Subprotocol()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
+	select {
+	case resumed := <-client.ReconnectedChan():
+		if !resumed {
+			t.Fatalf("Expected the session to be resumed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the client to reconnect, state %v error %v", client.State(), client.LastError())
+	}
+
+	select {
+	case envelope := <-requests:
+		if envelope.EnvelopeType != ports.PlayerInputEnvelope {
+			t.Errorf("Expected inputs on the new connection, got %s", envelope.EnvelopeType)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected inputs on the new connection")
+	}
+}
@@ -0,0 +1,32 @@
+package raycast
+
+import "survival/internal/engine/ports"
+
+// LightGrid samples the light map sent by the server at world coordinates.
+type LightGrid struct {
+	lightMap ports.LightMapPayload
+}
+
+func NewLightGrid(lightMap ports.LightMapPayload) *LightGrid {
+	return &LightGrid{lightMap: lightMap}
+}
+
+// LightAt returns the light level in [0, 1] at the given world coordinate.
+// Positions without light data are treated as fully lit.
+func (lg *LightGrid) LightAt(x, y float64) float64 {
+	if lg == nil || lg.lightMap.CellSize <= 0 || x < 0 || y < 0 {
+		return 1
+	}
+
+	gx := int(x / lg.lightMap.CellSize)
+	gy := int(y / lg.lightMap.CellSize)
+	if gx >= lg.lightMap.Width || gy >= lg.lightMap.Height {
+		return 1
+	}
+
+	index := gy*lg.lightMap.Width + gx
+	if index >= len(lg.lightMap.Levels) {
+		return 1
+	}
+	return float64(lg.lightMap.Levels[index]) / 255
+}
@@ -9,6 +9,10 @@ import (
 const (
 	FOVAngle    = math.Pi / 2
 	MaxDistance = 20.0
+
+	// hitPointBackoff pulls the reported hit point slightly towards the viewer,
+	// so it samples the space in front of the wall rather than its surface.
+	hitPointBackoff = 0.1
 )
 
 type RaycastResult struct {
@@ -17,6 +21,7 @@ type RaycastResult struct {
 	WallHeight    float64
 	BaseElevation float64
 	EntityID      uint64
+	HitX, HitY    float64
 }
 
 func CastRays(playerX, playerY, playerDir, viewHeight float64, colliders []ports.Collider, numRays int) []RaycastResult {
@@ -33,12 +38,18 @@ func CastRays(playerX, playerY, playerDir, viewHeight float64, colliders []ports
 
 		distance, hit, hitCollider := castSingleRay(playerX, playerY, rayDirX, rayDirY, colliders)
 
+		hitDist := math.Max(distance-hitPointBackoff, 0)
+		hitX := playerX + rayDirX*hitDist
+		hitY := playerY + rayDirY*hitDist
+
 		angleDiff := rayAngle - playerDir
 		distance *= math.Cos(angleDiff)
 
 		result := RaycastResult{
 			Distance: distance,
 			Hit:      hit,
+			HitX:     hitX,
+			HitY:     hitY,
 		}
 
 		if hit && hitCollider != nil {
@@ -19,6 +19,16 @@ const (
 	ColorWallNear
 	ColorWallMid
 	ColorWallFar
+	ColorWallDim
+	ColorWallDark
+	ColorSprite
+	ColorSpriteDim
+	ColorEnemy
+	ColorEnemyDim
+	ColorTeam1
+	ColorTeam1Dim
+	ColorTeam2
+	ColorTeam2Dim
 )
 
 var colorTo256 = map[Color]int{
@@ -28,8 +38,34 @@ var colorTo256 = map[Color]int{
 	ColorWallNear: 255,
 	ColorWallMid:  245,
 	ColorWallFar:  240,
+	ColorWallDim:  237,
+	ColorWallDark: 233,
+
+	ColorSprite:    160,
+	ColorSpriteDim: 52,
+	ColorEnemy:     70,
+	ColorEnemyDim:  22,
+	ColorTeam1:     166,
+	ColorTeam1Dim:  94,
+	ColorTeam2:     33,
+	ColorTeam2Dim:  18,
 }
 
+// TeamColor256 returns the 256-color code of a team, 0 for players without a team.
+func TeamColor256(team int) int {
+	switch team {
+	case 1:
+		return colorTo256[ColorTeam1]
+	case 2:
+		return colorTo256[ColorTeam2]
+	default:
+		return 0
+	}
+}
+
+// wallShades orders wall colors from brightest to darkest.
+var wallShades = []Color{ColorWallNear, ColorWallMid, ColorWallFar, ColorWallDim, ColorWallDark}
+
 type ColorPair struct {
 	Fg int
 	Bg int
@@ -46,6 +82,7 @@ type Renderer25D struct {
 	viewHeight    float64
 	projDist      float64
 	horizon       int
+	lights        *LightGrid
 }
 
 func NewRenderer25D(termWidth, termHeight int, viewHeight float64) *Renderer25D {
@@ -117,7 +154,7 @@ func (r *Renderer25D) Render(results []RaycastResult) {
 		yTop := r.horizon - int(((baseElev+wallHeight-r.viewHeight)/zDepth)*r.projDist)
 		yBottom := r.horizon - int(((baseElev-r.viewHeight)/zDepth)*r.projDist)
 
-		wallColor := r.getWallColor(result.Distance)
+		wallColor := r.getWallColor(result.Distance, r.lights.LightAt(result.HitX, result.HitY))
 
 		for col := startCol; col < endCol; col++ {
 			r.drawColumnWithWall(col, yTop, yBottom, wallColor)
@@ -168,14 +205,28 @@ func (r *Renderer25D) drawColumnWithWall(col, yTop, yBottom int, wallColor Color
 	}
 }
 
-func (r *Renderer25D) getWallColor(distance float64) Color {
+// getWallColor picks a shade from the distance band, then darkens it further
+// by the light level at the hit point.
+func (r *Renderer25D) getWallColor(distance, light float64) Color {
+	shade := 0
 	normalizedDist := distance / MaxDistance
-	if normalizedDist < 0.33 {
-		return ColorWallNear
-	} else if normalizedDist < 0.66 {
-		return ColorWallMid
+	if normalizedDist >= 0.66 {
+		shade = 2
+	} else if normalizedDist >= 0.33 {
+		shade = 1
 	}
-	return ColorWallFar
+
+	switch {
+	case light >= 0.66:
+	case light >= 0.33:
+		shade++
+	case light >= 0.1:
+		shade += 2
+	default:
+		shade = len(wallShades) - 1
+	}
+
+	return wallShades[min(shade, len(wallShades)-1)]
 }
 
 func (r *Renderer25D) WriteToBuffer(buf *bytes.Buffer) {
@@ -211,6 +262,11 @@ func (r *Renderer25D) SetViewHeight(height float64) {
 	r.viewHeight = height
 }
 
+// SetLightGrid sets the light map used to shade walls. A nil grid renders everything fully lit.
+func (r *Renderer25D) SetLightGrid(lights *LightGrid) {
+	r.lights = lights
+}
+
 func (r *Renderer25D) mergeToOutput() {
 	for termRow := 0; termRow < r.termHeight; termRow++ {
 		upperRow := termRow * 2
@@ -0,0 +1,101 @@
+package raycast
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	spriteWidth  = 1.0 // world width of a player sprite
+	spriteHeight = 1.9 // world height of a player sprite
+)
+
+// Sprite is another player drawn as a billboard in the 2.5D view.
+// Enemies are drawn in their own color, players in team modes in the color of their team.
+type Sprite struct {
+	X, Y  float64
+	Enemy bool
+	Team  int
+}
+
+// RenderSprites draws sprites over the last rendered frame, hiding the
+// columns where a wall is closer than the sprite. results must be the rays
+// the frame was rendered from.
+func (r *Renderer25D) RenderSprites(playerX, playerY, playerDir float64, sprites []Sprite, results []RaycastResult) {
+	if len(sprites) == 0 || len(results) == 0 {
+		return
+	}
+
+	type projected struct {
+		depth  float64
+		center float64
+		color  Color
+	}
+
+	visible := make([]projected, 0, len(sprites))
+	for _, sprite := range sprites {
+		dx := sprite.X - playerX
+		dy := sprite.Y - playerY
+
+		angle := math.Atan2(dx, -dy) - playerDir
+		angle = math.Atan2(math.Sin(angle), math.Cos(angle))
+
+		depth := math.Hypot(dx, dy) * math.Cos(angle)
+		if depth < 0.1 || depth > MaxDistance || math.Abs(angle) > FOVAngle/2+0.2 {
+			continue
+		}
+
+		visible = append(visible, projected{
+			depth:  depth,
+			center: (angle/FOVAngle + 0.5) * float64(r.logicalWidth),
+			color:  r.getSpriteColor(sprite, r.lights.LightAt(sprite.X, sprite.Y)),
+		})
+	}
+
+	// draw far to near so closer sprites cover farther ones
+	sort.Slice(visible, func(i, j int) bool { return visible[i].depth > visible[j].depth })
+
+	colWidth := float64(r.logicalWidth) / float64(len(results))
+	for _, sprite := range visible {
+		halfWidth := math.Max((spriteWidth/sprite.depth)*r.projDist/2, 0.5)
+		startCol := max(int(sprite.center-halfWidth), 0)
+		endCol := min(int(sprite.center+halfWidth)+1, r.logicalWidth)
+
+		yTop := max(r.horizon-int(((spriteHeight-r.viewHeight)/sprite.depth)*r.projDist), 0)
+		yBottom := min(r.horizon-int((-r.viewHeight/sprite.depth)*r.projDist), r.logicalHeight)
+
+		for col := startCol; col < endCol; col++ {
+			ray := results[min(int(float64(col)/colWidth), len(results)-1)]
+			if ray.Hit && ray.Distance < sprite.depth {
+				continue
+			}
+			for y := yTop; y < yBottom; y++ {
+				r.logicalBuffer[y][col] = sprite.color
+			}
+		}
+	}
+
+	r.mergeToOutput()
+}
+
+func (r *Renderer25D) getSpriteColor(sprite Sprite, light float64) Color {
+	dim := light < 0.33
+	switch {
+	case sprite.Enemy && dim:
+		return ColorEnemyDim
+	case sprite.Enemy:
+		return ColorEnemy
+	case sprite.Team == 1 && dim:
+		return ColorTeam1Dim
+	case sprite.Team == 1:
+		return ColorTeam1
+	case sprite.Team == 2 && dim:
+		return ColorTeam2Dim
+	case sprite.Team == 2:
+		return ColorTeam2
+	case dim:
+		return ColorSpriteDim
+	default:
+		return ColorSprite
+	}
+}
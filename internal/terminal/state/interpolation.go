@@ -0,0 +1,125 @@
+package state
+
+import (
+	"math"
+	"time"
+
+	"survival/internal/engine/ports"
+)
+
+const (
+	// snapshotBufferSize is the number of game updates kept to interpolate
+	// between, half a second of updates.
+	snapshotBufferSize = 32
+	// maxExtrapolation is how far past the latest update players keep moving
+	// when updates are late, before they freeze.
+	maxExtrapolation = 100 * time.Millisecond
+)
+
+// interpolationDelays are the delays the settings cycle through.
+var interpolationDelays = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	150 * time.Millisecond,
+	200 * time.Millisecond,
+}
+
+type remoteSnapshot struct {
+	timestamp int64 // server time, unix milli
+	players   map[uint64]ports.PlayerInfo
+}
+
+// interpolation buffers the other players of the last game updates by server
+// timestamp, so they are drawn moving smoothly between updates rather than
+// jumping whenever one arrives. Players are drawn as they were a delay ago,
+// between the two updates around that time. Once updates are late they are
+// moved on along their last move for a moment.
+type interpolation struct {
+	snapshots []remoteSnapshot // oldest first
+	// localTime translates server timestamps to the local clock, see
+	// network.Client.LocalTime. The timestamps are translated when drawing,
+	// so that all of them move together when the clock estimate changes.
+	localTime func(timestamp int64) time.Time
+}
+
+// add buffers the players in view of an update. Updates older than the
+// latest one are dropped.
+func (b *interpolation) add(update ports.GameUpdatePayload) {
+	if n := len(b.snapshots); n > 0 && update.Timestamp <= b.snapshots[n-1].timestamp {
+		return
+	}
+
+	players := make(map[uint64]ports.PlayerInfo, len(update.Views))
+	for _, view := range update.Views {
+		players[view.ID] = view
+	}
+	b.snapshots = append(b.snapshots, remoteSnapshot{timestamp: update.Timestamp, players: players})
+	if len(b.snapshots) > snapshotBufferSize {
+		b.snapshots = append(b.snapshots[:0], b.snapshots[len(b.snapshots)-snapshotBufferSize:]...)
+	}
+}
+
+// players returns the other players as they were the delay before now.
+func (b *interpolation) players(now time.Time, delay time.Duration) []ports.PlayerInfo {
+	n := len(b.snapshots)
+	if n == 0 {
+		return nil
+	}
+
+	renderTime := milliseconds(now.Add(-delay))
+	latest := b.snapshots[n-1]
+	switch {
+	case renderTime >= b.takenAt(latest):
+		if n == 1 {
+			return playersOf(latest, latest, 0)
+		}
+		// late, extrapolate along the move between the last two updates
+		previous := b.snapshots[n-2]
+		ahead := math.Min(renderTime-b.takenAt(latest), float64(maxExtrapolation.Milliseconds()))
+		return playersOf(previous, latest, 1+ahead/(b.takenAt(latest)-b.takenAt(previous)))
+	case renderTime <= b.takenAt(b.snapshots[0]):
+		return playersOf(b.snapshots[0], b.snapshots[0], 0)
+	}
+
+	i := 1
+	for b.takenAt(b.snapshots[i]) < renderTime {
+		i++
+	}
+	from, to := b.snapshots[i-1], b.snapshots[i]
+	return playersOf(from, to, (renderTime-b.takenAt(from))/(b.takenAt(to)-b.takenAt(from)))
+}
+
+// takenAt returns when the server took the snapshot, on the local clock.
+func (b *interpolation) takenAt(snapshot remoteSnapshot) float64 {
+	return milliseconds(b.localTime(snapshot.timestamp))
+}
+
+// milliseconds returns the unix time in fractional milliseconds.
+func milliseconds(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1000
+}
+
+// playersOf returns the players of the later snapshot moved t of the way
+// from the earlier one, past the later one for t over 1. Players that were
+// not in the earlier snapshot are where the later one has them.
+func playersOf(from, to remoteSnapshot, t float64) []ports.PlayerInfo {
+	players := make([]ports.PlayerInfo, 0, len(to.players))
+	for id, player := range to.players {
+		if previous, exist := from.players[id]; exist {
+			player.X = lerp(previous.X, player.X, t)
+			player.Y = lerp(previous.Y, player.Y, t)
+			player.Dir = lerpAngle(previous.Dir, player.Dir, t)
+		}
+		players = append(players, player)
+	}
+	return players
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// lerpAngle turns from a towards b the shortest way round.
+func lerpAngle(a, b, t float64) float64 {
+	return a + math.Remainder(b-a, 2*math.Pi)*t
+}
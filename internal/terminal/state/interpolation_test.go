@@ -0,0 +1,87 @@
+package state
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"survival/internal/engine/ports"
+)
+
+// addUpdate buffers an update of one player.
+func addUpdate(b *interpolation, timestamp int64, x, dir float64) {
+	b.add(ports.GameUpdatePayload{
+		Views:     []ports.PlayerInfo{{ID: 7, X: x, Dir: dir}},
+		Timestamp: timestamp,
+	})
+}
+
+func TestInterpolation_Players(t *testing.T) {
+	delay := 100 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		updates [][3]float64 // timestamp, x, dir
+		// serverAhead is how far the server clock is ahead of the local one
+		serverAhead time.Duration
+		now         int64
+		wantX       float64
+		wantDir     float64
+	}{
+		{
+			name:    "midpoint between two updates",
+			updates: [][3]float64{{1000, 0, 0}, {1100, 10, 1}},
+			now:     1150,
+			wantX:   5,
+			wantDir: 0.5,
+		},
+		{
+			name:        "server timestamps are translated to the local clock",
+			updates:     [][3]float64{{1000, 0, 0}, {1100, 10, 0}},
+			serverAhead: time.Second,
+			now:         150,
+			wantX:       5,
+		},
+		{
+			name:    "turns the short way across pi",
+			updates: [][3]float64{{1000, 0, 3}, {1100, 0, -3}},
+			now:     1150,
+			wantDir: math.Pi,
+		},
+		{
+			name:    "extrapolates at most 100ms past the latest update",
+			updates: [][3]float64{{1000, 0, 0}, {1100, 10, 0}},
+			now:     2200,
+			wantX:   20,
+		},
+		{
+			name:    "a single update is drawn where it is",
+			updates: [][3]float64{{1000, 4, 2}},
+			now:     1500,
+			wantX:   4,
+			wantDir: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := interpolation{localTime: func(timestamp int64) time.Time {
+				return time.UnixMilli(timestamp).Add(-tt.serverAhead)
+			}}
+			for _, update := range tt.updates {
+				addUpdate(&b, int64(update[0]), update[1], update[2])
+			}
+
+			players := b.players(time.UnixMilli(tt.now), delay)
+			if len(players) != 1 {
+				t.Fatalf("Expected one player, got %d", len(players))
+			}
+			if math.Abs(players[0].X-tt.wantX) > 1e-9 {
+				t.Errorf("Expected x %v, got %v", tt.wantX, players[0].X)
+			}
+			if math.Abs(math.Remainder(players[0].Dir-tt.wantDir, 2*math.Pi)) > 1e-9 {
+				t.Errorf("Expected direction %v, got %v", tt.wantDir, players[0].Dir)
+			}
+		})
+	}
+}
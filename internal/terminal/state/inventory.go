@@ -0,0 +1,227 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"survival/internal/engine/ports"
+	"survival/internal/terminal"
+	"survival/internal/terminal/network"
+)
+
+const inventoryBoxWidth = 60
+
+// InventoryState lists the carried items followed by the items within reach.
+// Every action is a request the server validates, the list only changes once
+// the server sends the updated inventory.
+type InventoryState struct {
+	client *network.Client
+	logger *slog.Logger
+
+	// inventory is shared with the game view, which opens the screen with
+	// the latest one it got
+	inventory     *ports.InventoryPayload
+	selectedIndex int
+	message       string
+}
+
+func NewInventoryState(client *network.Client, logger *slog.Logger, inventory *ports.InventoryPayload) *InventoryState {
+	return &InventoryState{
+		client:    client,
+		logger:    logger,
+		inventory: inventory,
+	}
+}
+
+func (s *InventoryState) Init() {
+	s.selectedIndex = 0
+	s.message = ""
+}
+
+func (s *InventoryState) Update(input terminal.InputEvent, dt time.Duration) terminal.Command {
+	s.processNetworkMessages()
+
+	rowCount := len(s.inventory.Items) + len(s.inventory.Nearby)
+	var err error
+
+	switch input {
+	case terminal.InputMoveBackward:
+		s.selectedIndex--
+		if s.selectedIndex < 0 {
+			s.selectedIndex = max(rowCount-1, 0)
+		}
+
+	case terminal.InputMoveForward:
+		s.selectedIndex++
+		if s.selectedIndex >= rowCount {
+			s.selectedIndex = 0
+		}
+
+	case terminal.InputAction:
+		if item, ok := s.selectedItem(); ok {
+			err = s.client.EquipItem(item.ID)
+		} else if ground, ok := s.selectedGroundItem(); ok {
+			err = s.client.PickUpItem(ground.PickupID)
+		}
+
+	case terminal.InputDrop:
+		if item, ok := s.selectedItem(); ok {
+			err = s.client.DropItem(item.ID)
+		}
+
+	case terminal.InputTurnLeft:
+		err = s.moveSelected(-1)
+
+	case terminal.InputTurnRight:
+		err = s.moveSelected(1)
+
+	case terminal.InputCancel, terminal.InputInventory:
+		return terminal.Command{Type: terminal.CmdPop}
+	}
+
+	if err != nil {
+		s.logger.Error("Failed to send inventory request", "error", err)
+		s.message = err.Error()
+	}
+	return terminal.Command{Type: terminal.CmdNone}
+}
+
+func (s *InventoryState) processNetworkMessages() {
+	for {
+		select {
+		case inventory := <-s.client.InventoryChan():
+			*s.inventory = inventory
+			s.message = ""
+			rowCount := len(inventory.Items) + len(inventory.Nearby)
+			s.selectedIndex = max(min(s.selectedIndex, rowCount-1), 0)
+		case message := <-s.client.RejectionChan():
+			s.message = message
+		default:
+			return
+		}
+	}
+}
+
+func (s *InventoryState) selectedItem() (ports.InventoryItem, bool) {
+	if s.selectedIndex < len(s.inventory.Items) {
+		return s.inventory.Items[s.selectedIndex], true
+	}
+	return ports.InventoryItem{}, false
+}
+
+func (s *InventoryState) selectedGroundItem() (ports.GroundItem, bool) {
+	index := s.selectedIndex - len(s.inventory.Items)
+	if index >= 0 && index < len(s.inventory.Nearby) {
+		return s.inventory.Nearby[index], true
+	}
+	return ports.GroundItem{}, false
+}
+
+// moveSelected moves the selected item by offset within its kind, the
+// selection follows it.
+func (s *InventoryState) moveSelected(offset int) error {
+	item, ok := s.selectedItem()
+	if !ok {
+		return nil
+	}
+
+	slot, count := 0, 0
+	for i, other := range s.inventory.Items {
+		if other.Kind != item.Kind {
+			continue
+		}
+		if i == s.selectedIndex {
+			slot = count
+		}
+		count++
+	}
+	target := slot + offset
+	if target < 0 || target >= count {
+		return nil
+	}
+
+	s.selectedIndex += offset
+	return s.client.MoveItem(item.ID, target)
+}
+
+func (s *InventoryState) Draw(buf *bytes.Buffer, width, height int) {
+	locale := terminal.AppDefaultConfig.Locale
+	inventory := s.inventory
+
+	borderLine := strings.Repeat(locale.BoxBorderH, inventoryBoxWidth-2)
+	borderTop := fmt.Sprintf("╔%s╗", borderLine)
+	borderBot := fmt.Sprintf("╚%s╝", borderLine)
+	emptyRow := DrawBoxRow("", inventoryBoxWidth, locale)
+
+	buf.WriteString(setGreenFont)
+
+	drawCenteredLine(buf, width, borderTop)
+	drawCenteredLine(buf, width, emptyRow)
+	drawCenteredLine(buf, width, DrawBoxRow(locale.InventoryTitle, inventoryBoxWidth, locale))
+	slots := fmt.Sprintf(locale.InventorySlots, len(inventory.Items), inventory.MaxSlots)
+	drawCenteredLine(buf, width, DrawBoxRow(slots, inventoryBoxWidth, locale))
+	drawCenteredLine(buf, width, emptyRow)
+
+	row := 0
+	for _, item := range inventory.Items {
+		value := itemAmmo(item)
+		if item.Equipped {
+			value = strings.TrimSpace(value + " " + locale.InventoryEquipped)
+		}
+		drawCenteredLine(buf, width, FormatRow(s.indicator(row)+itemName(item, locale), value, inventoryBoxWidth, locale))
+		row++
+	}
+	if len(inventory.Items) == 0 {
+		drawCenteredLine(buf, width, FormatRow("  "+locale.InventoryEmpty, "", inventoryBoxWidth, locale))
+	}
+
+	drawCenteredLine(buf, width, emptyRow)
+	drawCenteredLine(buf, width, FormatRow(locale.InventoryNearby, "", inventoryBoxWidth, locale))
+	for _, ground := range inventory.Nearby {
+		drawCenteredLine(buf, width, FormatRow(s.indicator(row)+itemName(ground.Item, locale), itemAmmo(ground.Item), inventoryBoxWidth, locale))
+		row++
+	}
+	if len(inventory.Nearby) == 0 {
+		drawCenteredLine(buf, width, FormatRow("  "+locale.InventoryEmpty, "", inventoryBoxWidth, locale))
+	}
+
+	drawCenteredLine(buf, width, emptyRow)
+	drawCenteredLine(buf, width, borderBot)
+
+	buf.WriteString(resetFontColor)
+	buf.WriteString(setRedFont)
+	drawCenteredLine(buf, width, s.message)
+	buf.WriteString(resetFontColor)
+	drawCenteredLine(buf, width, PadCenter(locale.InventoryHint, inventoryBoxWidth))
+}
+
+func (s *InventoryState) indicator(row int) string {
+	if row == s.selectedIndex {
+		return "► "
+	}
+	return "  "
+}
+
+func itemName(item ports.InventoryItem, locale terminal.LocaleData) string {
+	switch item.Kind {
+	case ports.ItemKindMelee:
+		return locale.ItemMelee
+	case ports.ItemKindRanged:
+		return locale.ItemRanged
+	case ports.ItemKindMagazine:
+		return locale.ItemMagazine
+	default:
+		return item.Kind
+	}
+}
+
+// itemAmmo shows the rounds in a magazine or loaded weapon, empty for the rest.
+func itemAmmo(item ports.InventoryItem) string {
+	if item.Capacity == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", item.Ammo, item.Capacity)
+}
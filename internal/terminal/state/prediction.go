@@ -0,0 +1,137 @@
+package state
+
+import (
+	"time"
+
+	"survival/internal/engine/movement"
+	"survival/internal/engine/ports"
+	"survival/internal/engine/state"
+	"survival/internal/engine/vector"
+)
+
+const (
+	// maxPredictionSteps caps the ticks predicted at once after a stall.
+	maxPredictionSteps = 6
+	// maxPendingInputs drops the oldest inputs the server never reported
+	// applied, two seconds of ticks.
+	maxPendingInputs = 2 * ports.TargetTickRate
+)
+
+type pendingInput struct {
+	sequence uint32
+	input    ports.PlayerInput
+}
+
+// predictor moves the player on every tick with the movement code of the
+// server instead of waiting for the server to move it. The input of each tick
+// is numbered and kept until the server reports it applied. A game update
+// puts the player back where the server has it after the last applied input,
+// and the inputs the server has not applied yet are replayed on top.
+type predictor struct {
+	body     movement.Body
+	info     *ports.MovementInfo // nil until the server sends how the player moves
+	walls    movement.Walls
+	pending  []pendingInput
+	sequence uint32
+	lastStep time.Time
+}
+
+// setColliders collides the predicted moves with the static colliders of the
+// floor the player is on.
+func (p *predictor) setColliders(colliders []ports.Collider) {
+	boxes := make([]movement.Box, 0, len(colliders))
+	for _, collider := range colliders {
+		if collider.ShapeType != uint8(state.ColliderBox) {
+			continue
+		}
+		boxes = append(boxes, movement.Box{
+			Min: vector.Vector2D{X: collider.X - collider.HalfX, Y: collider.Y - collider.HalfY},
+			Max: vector.Vector2D{X: collider.X + collider.HalfX, Y: collider.Y + collider.HalfY},
+		})
+	}
+	p.walls = movement.BoxWalls(boxes)
+}
+
+// ticks returns the number of ticks to predict since the last call.
+func (p *predictor) ticks(now time.Time) int {
+	if p.lastStep.IsZero() {
+		p.lastStep = now
+		return 1
+	}
+	n := int(now.Sub(p.lastStep).Seconds() * ports.TargetTickRate)
+	if n > maxPredictionSteps {
+		p.lastStep = now
+		return maxPredictionSteps
+	}
+	p.lastStep = p.lastStep.Add(time.Duration(n) * time.Second / ports.TargetTickRate)
+	return n
+}
+
+// next numbers the input of a tick, moves the player by it and keeps it
+// until the server applies it.
+func (p *predictor) next(input ports.PlayerInput) ports.PlayerInput {
+	p.sequence++
+	input.Sequence = p.sequence
+	p.pending = append(p.pending, pendingInput{sequence: p.sequence, input: input})
+	if len(p.pending) > maxPendingInputs {
+		p.pending = p.pending[len(p.pending)-maxPendingInputs:]
+	}
+	p.body = p.step(p.body, input)
+	return input
+}
+
+// reconcile corrects the prediction with the player as the server has it.
+// Players the server does not report movement for, such as dead ones, are
+// not predicted until it does again.
+func (p *predictor) reconcile(me ports.PlayerInfo, info *ports.MovementInfo) {
+	p.info = info
+	p.body = movement.Body{Position: vector.Vector2D{X: me.X, Y: me.Y}, Direction: me.Dir}
+	if info == nil {
+		p.pending = p.pending[:0]
+		return
+	}
+	p.body.Velocity = vector.Vector2D{X: info.VX, Y: info.VY}
+
+	applied := 0
+	for applied < len(p.pending) && p.pending[applied].sequence <= info.LastInput {
+		applied++
+	}
+	p.pending = append(p.pending[:0], p.pending[applied:]...)
+	for _, pending := range p.pending {
+		p.body = p.step(p.body, pending.input)
+	}
+}
+
+// step moves the body by the input for one tick the way the server would.
+func (p *predictor) step(body movement.Body, input ports.PlayerInput) movement.Body {
+	info := p.info
+	if info == nil {
+		return body
+	}
+
+	// stunned players neither move nor turn
+	var in movement.Input
+	if !info.Stunned {
+		in = movement.Input{
+			MoveHorizontal: input.MoveHorizontal,
+			MoveVertical:   input.MoveVertical,
+			LookHorizontal: input.LookHorizontal,
+			Relative:       input.MovementType == ports.MovementTypeRelative,
+		}
+	}
+	speed := info.Speed
+	if input.Sprint && info.CanSprint && in.Moving() {
+		speed *= movement.SprintSpeedMultiplier
+	}
+	stats := movement.Stats{
+		Speed:         speed,
+		RotationSpeed: info.RotationSpeed,
+		Radius:        info.Radius,
+		Inertia: movement.Inertia{
+			Acceleration: info.Acceleration,
+			Friction:     info.Friction,
+			MaxSpeed:     info.MaxSpeed,
+		},
+	}
+	return movement.Step(body, in, stats, p.walls, ports.DeltaTime)
+}
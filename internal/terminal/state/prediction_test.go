@@ -0,0 +1,75 @@
+package state
+
+import (
+	"testing"
+
+	"survival/internal/engine/movement"
+	"survival/internal/engine/ports"
+	"survival/internal/engine/vector"
+)
+
+var testMovementInfo = ports.MovementInfo{
+	Speed:         5,
+	RotationSpeed: 2,
+	Radius:        0.5,
+	Acceleration:  40,
+	Friction:      20,
+	MaxSpeed:      10,
+	CanSprint:     true,
+}
+
+func TestPredictor_ReconcileReplaysUnappliedInputs(t *testing.T) {
+	info := testMovementInfo
+	p := &predictor{info: &info}
+	p.setColliders([]ports.Collider{{X: 50, Y: 56, HalfX: 5, HalfY: 1}})
+	p.body = movement.Body{Position: vector.Vector2D{X: 50, Y: 50}}
+
+	inputs := []ports.PlayerInput{
+		{MoveVertical: 1},
+		{MoveVertical: 1, MoveHorizontal: 1},
+		{MoveHorizontal: 1, LookHorizontal: 1},
+		{MoveVertical: 1, LookHorizontal: -1},
+		{MoveVertical: 1, Sprint: true},
+		{MoveHorizontal: -1},
+	}
+	for _, input := range inputs {
+		p.next(input)
+	}
+
+	// the server applied the first three inputs and has the player elsewhere
+	info.LastInput = 3
+	info.VX, info.VY = 0.5, 1
+	server := ports.PlayerInfo{X: 49, Y: 51, Dir: 0.25}
+	p.reconcile(server, &info)
+
+	if len(p.pending) != 3 || p.pending[0].sequence != 4 {
+		t.Fatalf("Expected inputs 4 to 6 pending, got %+v", p.pending)
+	}
+
+	want := movement.Body{Position: vector.Vector2D{X: 49, Y: 51}, Velocity: vector.Vector2D{X: 0.5, Y: 1}, Direction: 0.25}
+	stats := movement.Stats{
+		Speed:         info.Speed,
+		RotationSpeed: info.RotationSpeed,
+		Radius:        info.Radius,
+		Inertia:       movement.Inertia{Acceleration: info.Acceleration, Friction: info.Friction, MaxSpeed: info.MaxSpeed},
+	}
+	walls := movement.BoxWalls([]movement.Box{{Min: vector.Vector2D{X: 45, Y: 55}, Max: vector.Vector2D{X: 55, Y: 57}}})
+	for _, input := range inputs[3:] {
+		in := movement.Input{MoveHorizontal: input.MoveHorizontal, MoveVertical: input.MoveVertical, LookHorizontal: input.LookHorizontal}
+		inputStats := stats
+		if input.Sprint {
+			inputStats.Speed *= movement.SprintSpeedMultiplier
+		}
+		want = movement.Step(want, in, inputStats, walls, ports.DeltaTime)
+	}
+	if p.body != want {
+		t.Errorf("Expected the replayed body %+v, got %+v", want, p.body)
+	}
+
+	// once the server applied every input the player is where it says
+	info.LastInput = 6
+	p.reconcile(server, &info)
+	if len(p.pending) != 0 || p.body.Position != (vector.Vector2D{X: 49, Y: 51}) {
+		t.Errorf("Expected no pending inputs and the server position, got %d pending at %+v", len(p.pending), p.body.Position)
+	}
+}
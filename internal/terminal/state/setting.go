@@ -48,7 +48,7 @@ func (s *SettingState) Init() {
 }
 
 func (s *SettingState) Update(input terminal.InputEvent, dt time.Duration) terminal.Command {
-	settingItemCount := 2
+	settingItemCount := 4
 
 	switch input {
 	case terminal.InputMoveBackward:
@@ -80,6 +80,12 @@ func (s *SettingState) Update(input terminal.InputEvent, dt time.Duration) termi
 			} else {
 				terminal.AppDefaultConfig.Locale = terminal.LangTW
 			}
+
+		case 2:
+			terminal.AppDefaultConfig.TerminalBell = !terminal.AppDefaultConfig.TerminalBell
+
+		case 3:
+			terminal.AppDefaultConfig.InterpolationDelay = nextInterpolationDelay(terminal.AppDefaultConfig.InterpolationDelay)
 		}
 
 	case terminal.InputCancel:
@@ -110,12 +116,19 @@ func (s *SettingState) Draw(buf *bytes.Buffer, width, height int) {
 		langLabel = locale.LangNameEN
 	}
 
+	bellLabel := locale.ValueOff
+	if terminal.AppDefaultConfig.TerminalBell {
+		bellLabel = locale.ValueOn
+	}
+
 	settingItems := []struct {
 		label string
 		value string
 	}{
 		{locale.SettingScreenSize, sizeLabel},
 		{locale.SettingLanguage, langLabel},
+		{locale.SettingBell, bellLabel},
+		{locale.SettingDelay, fmt.Sprintf("%d ms", terminal.AppDefaultConfig.InterpolationDelay.Milliseconds())},
 	}
 
 	borderLine := strings.Repeat(locale.BoxBorderH, boxWidth-2)
@@ -148,3 +161,13 @@ func (s *SettingState) Draw(buf *bytes.Buffer, width, height int) {
 	drawCenteredLine(buf, width, "")
 	drawCenteredLine(buf, width, PadCenter(locale.SettingsHint, boxWidth))
 }
+
+// nextInterpolationDelay cycles through interpolationDelays.
+func nextInterpolationDelay(delay time.Duration) time.Duration {
+	for i, d := range interpolationDelays {
+		if d == delay {
+			return interpolationDelays[(i+1)%len(interpolationDelays)]
+		}
+	}
+	return interpolationDelays[0]
+}
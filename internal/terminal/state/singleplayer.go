@@ -2,10 +2,11 @@ package state
 
 import (
 	"bytes"
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"math"
+	"reflect"
+	"strings"
 	"time"
 
 	"survival/internal/engine/ports"
@@ -28,20 +29,39 @@ const (
 
 const (
 	serverAddr = "localhost:3033"
+
+	soundIndicatorDuration = time.Second
+	noticeDuration         = 3 * time.Second
 )
 
+type heardSound struct {
+	dir      float64
+	alert    bool
+	expireAt time.Time
+}
+
 type SinglePlayerState struct {
 	fd     int
 	logger *slog.Logger
 
-	phase    GamePhase
-	client   *network.Client
-	clientID string
+	phase  GamePhase
+	client *network.Client
 
 	playerX   float64
 	playerY   float64
 	playerDir float64
 	colliders []ports.Collider
+	remotes   interpolation
+	survival  *ports.SurvivalInfo
+	round     *ports.RoundInfo
+	zone      *ports.ZoneInfo
+	mapWidth  float64
+	mapHeight float64
+	playerID  uint64
+
+	floor      int
+	floorCount int
+	stairs     []ports.Stair
 
 	renderer25D  *raycast.Renderer25D
 	uiLayer      *ui.UILayer
@@ -50,25 +70,30 @@ type SinglePlayerState struct {
 
 	currentInput ports.PlayerInput
 	inputChanged bool
+	prediction   predictor
+
+	sounds   []heardSound
+	ringBell bool
+
+	// notice is shown on the status line until noticeUntil
+	notice      string
+	noticeUntil time.Time
+
+	showScoreboard bool
+	inventory      ports.InventoryPayload
 }
 
 func NewSinglePlayerState(fd int, logger *slog.Logger) *SinglePlayerState {
 	return &SinglePlayerState{
-		fd:       fd,
-		logger:   logger,
-		clientID: generateClientID(),
+		fd:     fd,
+		logger: logger,
 	}
 }
 
-func generateClientID() string {
-	b := make([]byte, 8)
-	rand.Read(b)
-	return "term-" + hex.EncodeToString(b)
-}
-
 func (s *SinglePlayerState) Init() {
 	s.phase = PhaseConnecting
-	s.client = network.NewClient(s.clientID)
+	s.client = network.NewClient()
+	s.remotes.localTime = s.client.LocalTime
 	s.viewHeight = state.DefaultPlayerViewHeight
 
 	viewWidth := terminal.AppDefaultConfig.Width
@@ -80,7 +105,15 @@ func (s *SinglePlayerState) Init() {
 }
 
 func (s *SinglePlayerState) connectAndJoin() {
-	err := s.client.Connect(serverAddr, "Player")
+	config := terminal.AppDefaultConfig
+	if err := s.client.Login(serverAddr, config.Username, config.Password); err != nil {
+		s.logger.Error("Failed to log in", "error", err)
+		s.errorMessage = fmt.Sprintf("Login failed: %v", err)
+		s.phase = PhaseError
+		return
+	}
+
+	err := s.client.Connect(serverAddr)
 	if err != nil {
 		s.logger.Error("Failed to connect", "error", err)
 		s.errorMessage = fmt.Sprintf("Connection failed: %v", err)
@@ -113,8 +146,14 @@ func (s *SinglePlayerState) Update(input terminal.InputEvent, dt time.Duration)
 			s.cleanup()
 			return terminal.Command{Type: terminal.CmdPop}
 		}
+		if input == terminal.InputToggleScoreboard {
+			s.toggleScoreboard()
+		}
+		if input == terminal.InputInventory {
+			return s.openInventory()
+		}
+		s.predict(time.Now())
 		s.handleGameInput(input)
-		s.sendInputIfChanged()
 	}
 
 	return terminal.Command{Type: terminal.CmdNone}
@@ -132,6 +171,21 @@ func (s *SinglePlayerState) processNetworkMessages() {
 			s.handleGameUpdate(update)
 		case staticData := <-s.client.StaticDataChan():
 			s.handleStaticData(staticData)
+		case delta := <-s.client.StaticDeltaChan():
+			s.handleStaticDataDelta(delta)
+		case scoreboard := <-s.client.ScoreboardChan():
+			s.handleScoreboard(scoreboard)
+		case inventory := <-s.client.InventoryChan():
+			s.inventory = inventory
+		case message := <-s.client.RejectionChan():
+			s.logger.Warn("Request rejected", "message", message)
+		case lightMap := <-s.client.LightMapChan():
+			s.renderer25D.SetLightGrid(raycast.NewLightGrid(lightMap))
+		case left := <-s.client.PlayerLeftChan():
+			s.notice = fmt.Sprintf(terminal.AppDefaultConfig.Locale.PlayerLeft, left.Name)
+			s.noticeUntil = time.Now().Add(noticeDuration)
+		case resumed := <-s.client.ReconnectedChan():
+			s.handleReconnected(resumed)
 		case err := <-s.client.ErrorChan():
 			s.logger.Error("Network error", "error", err)
 			s.errorMessage = err.Error()
@@ -142,6 +196,22 @@ func (s *SinglePlayerState) processNetworkMessages() {
 	}
 }
 
+// handleReconnected goes on playing a resumed session, the server sends the
+// room again. A session the server no longer knew has to join a room anew.
+func (s *SinglePlayerState) handleReconnected(resumed bool) {
+	s.logger.Info("Reconnected", "resumed", resumed)
+	if resumed {
+		return
+	}
+
+	s.phase = PhaseWaitingForRoom
+	if err := s.client.RequestRoomList(); err != nil {
+		s.logger.Error("Failed to request room list", "error", err)
+		s.errorMessage = fmt.Sprintf("Failed to get rooms: %v", err)
+		s.phase = PhaseError
+	}
+}
+
 func (s *SinglePlayerState) handleRoomList(roomList ports.ListRoomsResponse) {
 	if len(roomList.Rooms) == 0 {
 		s.errorMessage = "No rooms available"
@@ -158,14 +228,134 @@ func (s *SinglePlayerState) handleRoomList(roomList ports.ListRoomsResponse) {
 }
 
 func (s *SinglePlayerState) handleGameUpdate(update ports.GameUpdatePayload) {
-	s.playerX = update.Me.X
-	s.playerY = update.Me.Y
-	s.playerDir = update.Me.Dir
+	s.prediction.reconcile(update.Me, update.Movement)
+	s.playerX, s.playerY = s.prediction.body.Position.X, s.prediction.body.Position.Y
+	s.playerDir = s.prediction.body.Direction
+	s.uiLayer.SetHealth(update.Me.Health)
+	s.uiLayer.SetStamina(update.Me.Stamina)
+	s.uiLayer.SetTeam(update.Me.Team)
+
+	effects := make([]ui.StatusEffect, len(update.Me.Effects))
+	for i, effect := range update.Me.Effects {
+		effects[i] = ui.StatusEffect{
+			Name:      strings.ToUpper(effect.Kind),
+			Remaining: effect.Remaining,
+			Stacks:    effect.Stacks,
+		}
+	}
+	s.uiLayer.SetStatusEffects(effects)
+	s.survival = update.Survival
+	s.round = update.Round
+	s.zone = update.Zone
+	s.playerID = update.Me.ID
+	s.uiLayer.SetMinimap(s.minimap())
+
+	now := time.Now()
+	s.remotes.add(update)
+
+	for _, sound := range update.Sounds {
+		alert := sound.Kind == ports.SoundKindGunshot
+		s.sounds = append(s.sounds, heardSound{
+			dir:      sound.Dir,
+			alert:    alert,
+			expireAt: now.Add(soundIndicatorDuration),
+		})
+		if alert && terminal.AppDefaultConfig.TerminalBell {
+			s.ringBell = true
+		}
+	}
+}
+
+// soundIndicators drops expired sounds and returns the rest relative to the view direction.
+func (s *SinglePlayerState) soundIndicators() []ui.SoundIndicator {
+	now := time.Now()
+	active := s.sounds[:0]
+	for _, sound := range s.sounds {
+		if now.Before(sound.expireAt) {
+			active = append(active, sound)
+		}
+	}
+	s.sounds = active
+
+	indicators := make([]ui.SoundIndicator, len(active))
+	for i, sound := range active {
+		indicators[i] = ui.SoundIndicator{
+			Angle: sound.dir - s.playerDir,
+			Alert: sound.alert,
+		}
+	}
+	return indicators
+}
+
+func (s *SinglePlayerState) handleScoreboard(scoreboard ports.ScoreboardPayload) {
+	rows := make([]ui.ScoreboardRow, len(scoreboard.Entries))
+	for i, entry := range scoreboard.Entries {
+		rows[i] = ui.ScoreboardRow{
+			Name:     entry.Name,
+			Team:     entry.Team,
+			Kills:    entry.Kills,
+			Deaths:   entry.Deaths,
+			Assists:  entry.Assists,
+			Damage:   entry.Damage,
+			Accuracy: entry.Accuracy,
+			Me:       entry.PlayerID == s.playerID,
+		}
+	}
+	s.uiLayer.SetScoreboard(rows)
+}
+
+// toggleScoreboard shows or hides the scoreboard, fetching the latest one
+// when shown. Later changes are pushed by the server.
+func (s *SinglePlayerState) toggleScoreboard() {
+	s.showScoreboard = !s.showScoreboard
+	s.uiLayer.SetScoreboardVisible(s.showScoreboard)
+	if !s.showScoreboard {
+		return
+	}
+	if err := s.client.RequestScoreboard(); err != nil {
+		s.logger.Error("Failed to request scoreboard", "error", err)
+	}
+}
+
+// openInventory stops the player and opens the inventory screen over the game.
+func (s *SinglePlayerState) openInventory() terminal.Command {
+	s.currentInput = ports.PlayerInput{MovementType: ports.MovementTypeRelative}
+	s.inputChanged = true
+	s.sendInput()
+	return terminal.Command{Type: terminal.CmdPush, NextState: NewInventoryState(s.client, s.logger, &s.inventory)}
 }
 
 func (s *SinglePlayerState) handleStaticData(data ports.StaticDataPayload) {
 	s.colliders = data.Colliders
-	s.logger.Info("Received static data", "colliders", len(s.colliders))
+	s.mapWidth = data.MapWidth
+	s.mapHeight = data.MapHeight
+	s.floor = data.Floor
+	s.floorCount = data.FloorCount
+	s.stairs = data.Stairs
+	s.prediction.setColliders(s.colliders)
+	s.logger.Info("Received static data", "colliders", len(s.colliders), "floor", s.floor)
+}
+
+// handleStaticDataDelta drops the removed colliders and adds the new ones,
+// replacing colliders sent before under the same ID.
+func (s *SinglePlayerState) handleStaticDataDelta(delta ports.StaticDataDeltaPayload) {
+	gone := make(map[uint64]bool, len(delta.Removed)+len(delta.Added))
+	for _, id := range delta.Removed {
+		gone[id] = true
+	}
+	for _, collider := range delta.Added {
+		gone[collider.ID] = true
+	}
+
+	colliders := make([]ports.Collider, 0, len(s.colliders)+len(delta.Added))
+	for _, collider := range s.colliders {
+		if !gone[collider.ID] {
+			colliders = append(colliders, collider)
+		}
+	}
+	s.colliders = append(colliders, delta.Added...)
+	s.prediction.setColliders(s.colliders)
+	s.logger.Info("Received static data delta", "removed", len(delta.Removed), "added", len(delta.Added))
 }
 
 func (s *SinglePlayerState) handleGameInput(input terminal.InputEvent) {
@@ -174,6 +364,9 @@ func (s *SinglePlayerState) handleGameInput(input terminal.InputEvent) {
 	switch input {
 	case terminal.InputMoveForward:
 		s.currentInput.MoveVertical = 1
+	case terminal.InputSprintForward:
+		s.currentInput.MoveVertical = 1
+		s.currentInput.Sprint = true
 	case terminal.InputMoveBackward:
 		s.currentInput.MoveVertical = -1
 	case terminal.InputMoveLeft:
@@ -184,27 +377,50 @@ func (s *SinglePlayerState) handleGameInput(input terminal.InputEvent) {
 		s.currentInput.LookHorizontal = -1
 	case terminal.InputTurnRight:
 		s.currentInput.LookHorizontal = 1
+	case terminal.InputToggleFlashlight:
+		s.currentInput.ToggleFlashlight = true
+	case terminal.InputFire:
+		s.currentInput.Fire = true
 	case terminal.InputNone:
 		s.currentInput = ports.PlayerInput{}
 	}
 
 	s.currentInput.MovementType = ports.MovementTypeRelative
 
-	if s.currentInput != prevInput {
+	if !reflect.DeepEqual(s.currentInput, prevInput) {
 		s.inputChanged = true
 	}
 }
 
-func (s *SinglePlayerState) sendInputIfChanged() {
+// predict sends the input of every tick passed since the last call and moves
+// the player by it right away.
+func (s *SinglePlayerState) predict(now time.Time) {
+	for range s.prediction.ticks(now) {
+		s.sendInput()
+	}
+}
+
+// sendInput numbers the current input, predicts the move and sends it. The
+// one-shot actions are only sent with the first input after they changed.
+func (s *SinglePlayerState) sendInput() {
+	input := s.currentInput
 	if !s.inputChanged {
-		return
+		input.Fire = false
+		input.SwitchWeapon = false
+		input.Reload = false
+		input.FastReload = false
+		input.ToggleFlashlight = false
 	}
+	s.inputChanged = false
 
-	err := s.client.SendInput(s.currentInput)
-	if err != nil {
+	input.InterpolationDelay = uint32(terminal.AppDefaultConfig.InterpolationDelay.Milliseconds())
+	input = s.prediction.next(input)
+	s.playerX, s.playerY = s.prediction.body.Position.X, s.prediction.body.Position.Y
+	s.playerDir = s.prediction.body.Direction
+
+	if err := s.client.SendInput(input); err != nil {
 		s.logger.Error("Failed to send input", "error", err)
 	}
-	s.inputChanged = false
 }
 
 func (s *SinglePlayerState) cleanup() {
@@ -277,14 +493,179 @@ func (s *SinglePlayerState) drawGameView(buf *bytes.Buffer, width, height int) {
 	results := raycast.CastRays(playerX, playerY, playerDir, s.viewHeight, colliders, numRays)
 
 	s.renderer25D.Render(results)
+	s.renderer25D.RenderSprites(playerX, playerY, playerDir, s.otherSprites(), results)
 
 	outputBuf := s.renderer25D.GetOutputBuffer()
 	colorBuf := s.renderer25D.GetColorBuffer()
+	s.uiLayer.SetSoundIndicators(s.soundIndicators())
 	s.uiLayer.Overlay(outputBuf, colorBuf)
 
+	if s.ringBell {
+		buf.WriteString(terminal.Bell)
+		s.ringBell = false
+	}
+
 	s.renderer25D.WriteWithOverlay(buf)
 
 	locale := terminal.AppDefaultConfig.Locale
-	statusLine := fmt.Sprintf("X:%.1f Y:%.1f Dir:%.2f | %s", playerX, playerY, playerDir, locale.SPStatusHint)
+	statusLine := fmt.Sprintf("X:%.1f Y:%.1f Dir:%.2f%s | %s", playerX, playerY, playerDir, s.latencyStatus(), locale.SPStatusHint)
+	if waveStatus := s.waveStatus(); waveStatus != "" {
+		statusLine = waveStatus + " | " + statusLine
+	}
+	if roundStatus := s.roundStatus(); roundStatus != "" {
+		statusLine = roundStatus + " | " + statusLine
+	}
+	if zoneStatus := s.zoneStatus(); zoneStatus != "" {
+		statusLine = zoneStatus + " | " + statusLine
+	}
+	if floorStatus := s.floorStatus(); floorStatus != "" {
+		statusLine = floorStatus + " | " + statusLine
+	}
+	if s.notice != "" && time.Now().Before(s.noticeUntil) {
+		statusLine = s.notice + " | " + statusLine
+	}
+	if s.client.State() == network.StateReconnecting {
+		statusLine = locale.SPReconnecting + " | " + statusLine
+	}
 	drawCenteredLine(buf, width, statusLine)
 }
+
+// otherSprites draws the other players as they were the interpolation delay ago.
+func (s *SinglePlayerState) otherSprites() []raycast.Sprite {
+	players := s.remotes.players(time.Now(), terminal.AppDefaultConfig.InterpolationDelay)
+	sprites := make([]raycast.Sprite, len(players))
+	for i, player := range players {
+		sprites[i] = raycast.Sprite{X: player.X, Y: player.Y, Enemy: player.Enemy, Team: player.Team}
+	}
+	return sprites
+}
+
+// floorStatus tells the floor the player is on and where the stair under the
+// player leads, empty on single floor maps. Floors are counted from 1.
+func (s *SinglePlayerState) floorStatus() string {
+	if s.floorCount <= 1 {
+		return ""
+	}
+
+	locale := terminal.AppDefaultConfig.Locale
+	status := fmt.Sprintf(locale.FloorStatus, s.floor+1, s.floorCount)
+	for _, stair := range s.stairs {
+		if math.Abs(s.playerX-stair.X) > stair.HalfX || math.Abs(s.playerY-stair.Y) > stair.HalfY {
+			continue
+		}
+		name := locale.StairName
+		if stair.Kind == ports.StairKindLadder {
+			name = locale.LadderName
+		}
+		status += " " + fmt.Sprintf(locale.FloorStair, name, stair.To+1)
+		break
+	}
+	return status
+}
+
+// waveStatus describes the survival progress, empty outside survival rooms.
+// latencyStatus returns the round trip time to the server and its jitter,
+// empty until the first pong.
+func (s *SinglePlayerState) latencyStatus() string {
+	rtt := s.client.RTT()
+	if rtt.Samples == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" RTT:%dms±%d", rtt.Smoothed.Milliseconds(), rtt.Jitter.Milliseconds())
+}
+
+func (s *SinglePlayerState) waveStatus() string {
+	survival := s.survival
+	if survival == nil {
+		return ""
+	}
+
+	locale := terminal.AppDefaultConfig.Locale
+	seconds := int(math.Ceil(survival.Countdown))
+	switch survival.Phase {
+	case ports.SurvivalPhaseCountdown:
+		return fmt.Sprintf(locale.WaveCountdown, survival.Wave, survival.TotalWaves, seconds)
+	case ports.SurvivalPhaseWave:
+		return fmt.Sprintf(locale.WaveActive, survival.Wave, survival.TotalWaves, survival.EnemiesRemaining)
+	case ports.SurvivalPhaseWon:
+		return fmt.Sprintf(locale.WaveWon, seconds)
+	case ports.SurvivalPhaseLost:
+		return fmt.Sprintf(locale.WaveLost, seconds)
+	default:
+		return ""
+	}
+}
+
+// roundStatus describes the round phase and its timer, empty in rooms without rounds.
+func (s *SinglePlayerState) roundStatus() string {
+	round := s.round
+	if round == nil {
+		return ""
+	}
+
+	locale := terminal.AppDefaultConfig.Locale
+	seconds := int(math.Ceil(round.Timer))
+	switch round.Phase {
+	case ports.RoundPhaseWarmup:
+		if seconds <= 0 {
+			return locale.RoundWaiting
+		}
+		return fmt.Sprintf(locale.RoundWarmup, round.Round, seconds)
+	case ports.RoundPhaseLive:
+		return fmt.Sprintf(locale.RoundLive, round.Round, seconds/60, seconds%60)
+	case ports.RoundPhasePostRound:
+		switch {
+		case round.Winner != 0 && round.Winner == s.playerID:
+			return fmt.Sprintf(locale.RoundYouWon, seconds)
+		case round.Winner != 0:
+			return fmt.Sprintf(locale.RoundPlayerWon, round.Winner, seconds)
+		case round.WinnerTeam != 0:
+			return fmt.Sprintf(locale.RoundTeamWon, round.WinnerTeam, seconds)
+		default:
+			return fmt.Sprintf(locale.RoundDraw, seconds)
+		}
+	default:
+		return ""
+	}
+}
+
+// minimap shows the safe zone around the player, nil without a zone.
+func (s *SinglePlayerState) minimap() *ui.Minimap {
+	zone := s.zone
+	if zone == nil {
+		return nil
+	}
+	return &ui.Minimap{
+		MapWidth:   s.mapWidth,
+		MapHeight:  s.mapHeight,
+		PlayerX:    s.playerX,
+		PlayerY:    s.playerY,
+		ZoneX:      zone.X,
+		ZoneY:      zone.Y,
+		ZoneRadius: zone.Radius,
+		NextX:      zone.NextX,
+		NextY:      zone.NextY,
+		NextRadius: zone.NextRadius,
+	}
+}
+
+// zoneStatus warns about being outside the safe zone or tells when it shrinks
+// next, empty without a zone or once it stopped shrinking.
+func (s *SinglePlayerState) zoneStatus() string {
+	zone := s.zone
+	if zone == nil {
+		return ""
+	}
+
+	locale := terminal.AppDefaultConfig.Locale
+	switch {
+	case math.Hypot(s.playerX-zone.X, s.playerY-zone.Y) > zone.Radius:
+		return fmt.Sprintf(locale.ZoneOutside, zone.Damage)
+	case zone.Shrinking:
+		return fmt.Sprintf(locale.ZoneShrinking, zone.Phase)
+	case zone.TimeToShrink > 0:
+		return fmt.Sprintf(locale.ZoneShrinkIn, zone.Phase, int(math.Ceil(zone.TimeToShrink)))
+	default:
+		return ""
+	}
+}
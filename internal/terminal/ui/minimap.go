@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"math"
+
+	"survival/internal/terminal/raycast"
+)
+
+// Minimap is an overview of the whole map showing the safe zone, the circle
+// it shrinks to next and the player. Positions are in world units.
+type Minimap struct {
+	MapWidth, MapHeight float64
+	PlayerX, PlayerY    float64
+
+	ZoneX, ZoneY, ZoneRadius float64
+	NextX, NextY, NextRadius float64
+}
+
+const (
+	minimapWidth  = 22 // including the border
+	minimapHeight = 11
+	minimapTop    = 1 // below the ammo counter
+
+	minimapRingPoints = 96
+
+	uiColorMinimapNext = 245 // gray
+)
+
+// SetMinimap sets the minimap drawn in the top right corner, nil hides it.
+func (u *UILayer) SetMinimap(minimap *Minimap) {
+	u.minimap = minimap
+}
+
+// drawMinimap draws the map bounds with the zone boundary in red, the next
+// zone in gray and the player, in red while outside the zone.
+func (u *UILayer) drawMinimap(buffer [][]rune, colors [][]raycast.ColorPair) {
+	m := u.minimap
+	if m.MapWidth <= 0 || m.MapHeight <= 0 {
+		return
+	}
+
+	left := u.width - minimapWidth - 1
+	innerWidth, innerHeight := minimapWidth-2, minimapHeight-2
+
+	for y := minimapTop; y < minimapTop+minimapHeight; y++ {
+		u.fillRow(buffer, colors, left, y, minimapWidth)
+	}
+	u.drawMinimapBorder(buffer, colors, left)
+
+	// plot maps a world position to a cell inside the border
+	plot := func(x, y float64, ch rune, fg int) {
+		col := int(x / m.MapWidth * float64(innerWidth))
+		row := int(y / m.MapHeight * float64(innerHeight))
+		if col < 0 || col >= innerWidth || row < 0 || row >= innerHeight {
+			return
+		}
+		u.drawColoredText(buffer, colors, left+1+col, minimapTop+1+row, string(ch), fg)
+	}
+	ring := func(cx, cy, radius float64, ch rune, fg int) {
+		for i := range minimapRingPoints {
+			angle := float64(i) / minimapRingPoints * 2 * math.Pi
+			plot(cx+math.Sin(angle)*radius, cy-math.Cos(angle)*radius, ch, fg)
+		}
+	}
+
+	ring(m.NextX, m.NextY, m.NextRadius, '.', uiColorMinimapNext)
+	ring(m.ZoneX, m.ZoneY, m.ZoneRadius, '*', uiColorAlert)
+
+	playerColor := uiColorFg
+	if math.Hypot(m.PlayerX-m.ZoneX, m.PlayerY-m.ZoneY) > m.ZoneRadius {
+		playerColor = uiColorAlert
+	}
+	plot(m.PlayerX, m.PlayerY, '@', playerColor)
+}
+
+func (u *UILayer) drawMinimapBorder(buffer [][]rune, colors [][]raycast.ColorPair, left int) {
+	right := left + minimapWidth - 1
+	bottom := minimapTop + minimapHeight - 1
+
+	for x := left + 1; x < right; x++ {
+		u.drawText(buffer, colors, x, minimapTop, "─")
+		u.drawText(buffer, colors, x, bottom, "─")
+	}
+	for y := minimapTop + 1; y < bottom; y++ {
+		u.drawText(buffer, colors, left, y, "│")
+		u.drawText(buffer, colors, right, y, "│")
+	}
+	u.drawText(buffer, colors, left, minimapTop, "┌")
+	u.drawText(buffer, colors, right, minimapTop, "┐")
+	u.drawText(buffer, colors, left, bottom, "└")
+	u.drawText(buffer, colors, right, bottom, "┘")
+}
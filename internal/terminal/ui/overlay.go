@@ -3,6 +3,7 @@ package ui
 import (
 	"bytes"
 	"fmt"
+	"math"
 
 	"survival/internal/terminal/raycast"
 )
@@ -14,8 +15,30 @@ type UILayer struct {
 	weaponEnabled    bool
 	hudEnabled       bool
 	health           int
+	stamina          int
+	team             int
 	ammo             int
 	maxAmmo          int
+	sounds           []SoundIndicator
+	effects          []StatusEffect
+
+	scoreboard        []ScoreboardRow
+	scoreboardVisible bool
+	minimap           *Minimap
+}
+
+// SoundIndicator marks a heard sound around the crosshair.
+// Angle is relative to the view direction, clockwise positive.
+type SoundIndicator struct {
+	Angle float64
+	Alert bool
+}
+
+// StatusEffect is an active effect listed below the health.
+type StatusEffect struct {
+	Name      string
+	Remaining float64
+	Stacks    int
 }
 
 func NewUILayer(width, height int) *UILayer {
@@ -26,6 +49,7 @@ func NewUILayer(width, height int) *UILayer {
 		weaponEnabled:    true,
 		hudEnabled:       true,
 		health:           100,
+		stamina:          100,
 		ammo:             12,
 		maxAmmo:          12,
 	}
@@ -47,28 +71,68 @@ func (u *UILayer) SetHealth(health int) {
 	u.health = health
 }
 
+// SetStamina sets the stamina percentage shown next to the health.
+func (u *UILayer) SetStamina(stamina int) {
+	u.stamina = stamina
+}
+
+// SetTeam sets the team shown next to the stamina, 0 hides it.
+func (u *UILayer) SetTeam(team int) {
+	u.team = team
+}
+
 func (u *UILayer) SetAmmo(ammo, maxAmmo int) {
 	u.ammo = ammo
 	u.maxAmmo = maxAmmo
 }
 
+func (u *UILayer) SetStatusEffects(effects []StatusEffect) {
+	u.effects = effects
+}
+
+func (u *UILayer) SetSoundIndicators(sounds []SoundIndicator) {
+	u.sounds = sounds
+}
+
 func (u *UILayer) Overlay(buffer [][]rune, colors [][]raycast.ColorPair) {
 	if u.crosshairEnabled {
 		u.drawCrosshair(buffer, colors)
 	}
 
+	if u.hudEnabled {
+		u.drawSoundIndicators(buffer, colors)
+	}
+
 	if u.hudEnabled {
 		u.drawHUD(buffer, colors)
 	}
 
+	if u.hudEnabled && u.minimap != nil {
+		u.drawMinimap(buffer, colors)
+	}
+
 	if u.weaponEnabled {
 		u.drawWeapon(buffer, colors)
 	}
+
+	if u.scoreboardVisible {
+		u.drawScoreboard(buffer, colors)
+	}
 }
 
 const (
-	uiColorFg = 15 // bright white
-	uiColorBg = 0  // black
+	uiColorFg    = 15  // bright white
+	uiColorBg    = 0   // black
+	uiColorAlert = 196 // red
+	uiColorSound = 250 // light gray
+)
+
+// soundArrows are indexed by eighths of a turn, starting straight ahead and going clockwise.
+var soundArrows = []rune{'↑', '↗', '→', '↘', '↓', '↙', '←', '↖'}
+
+const (
+	soundRingRadiusX = 16
+	soundRingRadiusY = 6
 )
 
 func (u *UILayer) drawCrosshair(buffer [][]rune, colors [][]raycast.ColorPair) {
@@ -83,10 +147,57 @@ func (u *UILayer) drawCrosshair(buffer [][]rune, colors [][]raycast.ColorPair) {
 	}
 }
 
+// drawSoundIndicators places an arrow on a ring around the crosshair pointing
+// towards every heard sound.
+func (u *UILayer) drawSoundIndicators(buffer [][]rune, colors [][]raycast.ColorPair) {
+	centerX := u.width / 2
+	centerY := u.height / 2
+
+	for _, sound := range u.sounds {
+		sector := int(math.Round(sound.Angle/(math.Pi/4))) % len(soundArrows)
+		if sector < 0 {
+			sector += len(soundArrows)
+		}
+
+		x := centerX + int(math.Round(math.Sin(sound.Angle)*soundRingRadiusX))
+		y := centerY - int(math.Round(math.Cos(sound.Angle)*soundRingRadiusY))
+		if y < 0 || y >= len(buffer) || x < 0 || x >= len(buffer[y]) {
+			continue
+		}
+
+		fg := uiColorSound
+		if sound.Alert {
+			fg = uiColorAlert
+		}
+
+		buffer[y][x] = soundArrows[sector]
+		if colors != nil {
+			colors[y][x] = raycast.ColorPair{Fg: fg, Bg: colors[y][x].Bg}
+		}
+	}
+}
+
 func (u *UILayer) drawHUD(buffer [][]rune, colors [][]raycast.ColorPair) {
 	healthStr := fmt.Sprintf("HP:%3d", u.health)
 	u.drawText(buffer, colors, 1, 0, healthStr)
 
+	staminaStr := fmt.Sprintf("ST:%3d", u.stamina)
+	u.drawText(buffer, colors, 2+len(healthStr), 0, staminaStr)
+
+	if u.team > 0 {
+		teamStr := fmt.Sprintf("T%d", u.team)
+		u.drawColoredText(buffer, colors, 3+len(healthStr)+len(staminaStr), 0, teamStr, raycast.TeamColor256(u.team))
+	}
+
+	for i, effect := range u.effects {
+		effectStr := effect.Name
+		if effect.Stacks > 1 {
+			effectStr += fmt.Sprintf(" x%d", effect.Stacks)
+		}
+		effectStr += fmt.Sprintf(" %.0fs", math.Ceil(effect.Remaining))
+		u.drawText(buffer, colors, 1, 1+i, effectStr)
+	}
+
 	ammoStr := fmt.Sprintf("%2d/%2d", u.ammo, u.maxAmmo)
 	u.drawText(buffer, colors, u.width-len(ammoStr)-1, 0, ammoStr)
 }
@@ -124,6 +235,10 @@ func (u *UILayer) drawWeapon(buffer [][]rune, colors [][]raycast.ColorPair) {
 }
 
 func (u *UILayer) drawText(buffer [][]rune, colors [][]raycast.ColorPair, x, y int, text string) {
+	u.drawColoredText(buffer, colors, x, y, text, uiColorFg)
+}
+
+func (u *UILayer) drawColoredText(buffer [][]rune, colors [][]raycast.ColorPair, x, y int, text string, fg int) {
 	if y < 0 || y >= len(buffer) {
 		return
 	}
@@ -132,12 +247,25 @@ func (u *UILayer) drawText(buffer [][]rune, colors [][]raycast.ColorPair, x, y i
 		if px >= 0 && px < len(buffer[y]) {
 			buffer[y][px] = ch
 			if colors != nil {
-				colors[y][px] = raycast.ColorPair{Fg: uiColorFg, Bg: colors[y][px].Bg}
+				colors[y][px] = raycast.ColorPair{Fg: fg, Bg: colors[y][px].Bg}
 			}
 		}
 	}
 }
 
+// fillRow blanks a row of a panel with the UI background.
+func (u *UILayer) fillRow(buffer [][]rune, colors [][]raycast.ColorPair, x, y, width int) {
+	if y < 0 || y >= len(buffer) {
+		return
+	}
+	for px := max(x, 0); px < x+width && px < len(buffer[y]); px++ {
+		buffer[y][px] = ' '
+		if colors != nil {
+			colors[y][px] = raycast.ColorPair{Fg: uiColorFg, Bg: uiColorBg}
+		}
+	}
+}
+
 func (u *UILayer) OverlayToOutput(buf *bytes.Buffer, mainBuffer [][]rune, mainColors [][]raycast.ColorPair) {
 	overlay := make([][]rune, len(mainBuffer))
 	colors := make([][]raycast.ColorPair, len(mainBuffer))
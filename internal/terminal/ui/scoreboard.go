@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+
+	"survival/internal/terminal/raycast"
+)
+
+// ScoreboardRow is a player listed on the scoreboard overlay. Me marks the
+// local player, Accuracy ranges from 0 to 1.
+type ScoreboardRow struct {
+	Name     string
+	Team     int
+	Kills    int
+	Deaths   int
+	Assists  int
+	Damage   int
+	Accuracy float64
+	Me       bool
+}
+
+const (
+	scoreboardNameWidth = 12
+	scoreboardTop       = 2
+)
+
+var scoreboardHeader = fmt.Sprintf("  %-*s %3s %3s %3s %5s %4s", scoreboardNameWidth, "NAME", "K", "D", "A", "DMG", "ACC")
+
+func (u *UILayer) SetScoreboard(rows []ScoreboardRow) {
+	u.scoreboard = rows
+}
+
+// SetScoreboardVisible shows or hides the scoreboard over the view.
+func (u *UILayer) SetScoreboardVisible(visible bool) {
+	u.scoreboardVisible = visible
+}
+
+// drawScoreboard draws the players as a table on a dark panel in the middle
+// of the view, names in the color of their team.
+func (u *UILayer) drawScoreboard(buffer [][]rune, colors [][]raycast.ColorPair) {
+	width := len(scoreboardHeader) + 2
+	x := (u.width - width) / 2
+	bottom := min(scoreboardTop+len(u.scoreboard)+3, u.height)
+
+	for y := scoreboardTop; y < bottom; y++ {
+		u.fillRow(buffer, colors, x, y, width)
+	}
+
+	u.drawText(buffer, colors, x+1, scoreboardTop+1, scoreboardHeader)
+	for i, row := range u.scoreboard {
+		y := scoreboardTop + 2 + i
+		if y >= bottom {
+			break
+		}
+
+		marker := "  "
+		if row.Me {
+			marker = "> "
+		}
+		name := []rune(row.Name)
+		if len(name) > scoreboardNameWidth {
+			name = name[:scoreboardNameWidth]
+		}
+		nameStr := fmt.Sprintf("%-*s", scoreboardNameWidth, string(name))
+		statsStr := fmt.Sprintf(" %3d %3d %3d %5d %3.0f%%", row.Kills, row.Deaths, row.Assists, row.Damage, row.Accuracy*100)
+
+		u.drawText(buffer, colors, x+1, y, marker)
+		nameColor := uiColorFg
+		if row.Team > 0 {
+			nameColor = raycast.TeamColor256(row.Team)
+		}
+		u.drawColoredText(buffer, colors, x+1+len(marker), y, nameStr, nameColor)
+		u.drawText(buffer, colors, x+1+len(marker)+scoreboardNameWidth, y, statsStr)
+	}
+}
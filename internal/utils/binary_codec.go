@@ -0,0 +1,729 @@
+package utils
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"survival/internal/engine/ports"
+)
+
+// ErrBinaryTruncated is returned when a binary message ends before all of its fields.
+var ErrBinaryTruncated = errors.New("binary message truncated")
+
+// requestEnvelopeTypes and responseEnvelopeTypes map the envelope types to
+// their one byte code on the wire, the index in the list. New types must be
+// appended so that the codes of the others stay the same.
+var (
+	requestEnvelopeTypes = []ports.RequestEnvelopeType{
+		ports.PlayerInputEnvelope,
+		ports.ListRoomsEnvelope,
+		ports.RequestJoinEnvelope,
+		ports.ScoreboardRequestEnvelope,
+		ports.InventoryEquipEnvelope,
+		ports.InventoryDropEnvelope,
+		ports.InventoryPickUpEnvelope,
+		ports.InventoryMoveEnvelope,
+		ports.SnapshotAckEnvelope,
+	}
+	responseEnvelopeTypes = []ports.ResponseEnvelopeType{
+		ports.GameUpdateEnvelope,
+		ports.StaticDataEnvelope,
+		ports.StaticDataDeltaEnvelope,
+		ports.SystemNotifyEnvelop,
+		ports.SystemSetSessionEnvelope,
+		ports.ErrInvalidSession,
+		ports.ListRoomsResponseEnvelope,
+		ports.ErrorResponseEnvelope,
+		ports.JoinRoomSuccessEnvelope,
+		ports.LightMapEnvelope,
+		ports.ScoreboardEnvelope,
+		ports.InventoryUpdateEnvelope,
+		ports.GameUpdateDeltaEnvelope,
+		ports.PlayerLeftEnvelope,
+		ports.LightMapDeltaEnvelope,
+	}
+)
+
+// BinaryCodec implements the Codec interface with a compact binary format.
+// Envelopes are a one byte type code followed by the payload. Game updates,
+// static data and player input are written field by field, floats as 32 bit
+// and integers as varints; the other payloads are rarely sent and stay JSON.
+// Game update deltas carry quantized moves as varints.
+type BinaryCodec struct{}
+
+// NewBinaryCodec creates a new instance of BinaryCodec.
+func NewBinaryCodec() *BinaryCodec {
+	return &BinaryCodec{}
+}
+
+// Encode writes the given data structure into a binary byte slice.
+func (c *BinaryCodec) Encode(data interface{}) ([]byte, error) {
+	var w binaryWriter
+	switch v := data.(type) {
+	case ports.RequestEnvelope:
+		code, err := envelopeCode(requestEnvelopeTypes, v.EnvelopeType)
+		if err != nil {
+			return nil, err
+		}
+		w.byte(code)
+		w.buf = append(w.buf, v.Payload...)
+	case ports.ResponseEnvelope:
+		code, err := envelopeCode(responseEnvelopeTypes, v.EnvelopeType)
+		if err != nil {
+			return nil, err
+		}
+		w.byte(code)
+		w.buf = append(w.buf, v.Payload...)
+	case ports.PlayerInput:
+		w.playerInput(v)
+	case ports.GameUpdatePayload:
+		w.gameUpdate(v)
+	case ports.GameUpdateDeltaPayload:
+		w.gameUpdateDelta(v)
+	case ports.SnapshotAckPayload:
+		w.uvarint(uint64(v.Sequence))
+	case ports.StaticDataPayload:
+		w.staticData(v)
+	case ports.StaticDataDeltaPayload:
+		w.staticDataDelta(v)
+	case json.RawMessage:
+		// already encoded payloads are written as they are
+		return v, nil
+	default:
+		return json.Marshal(data)
+	}
+	return w.buf, nil
+}
+
+// Decode reads the given binary byte slice into the provided data structure.
+func (c *BinaryCodec) Decode(data []byte, v interface{}) error {
+	r := binaryReader{buf: data}
+	switch v := v.(type) {
+	case *ports.RequestEnvelope:
+		code := r.byte()
+		if r.err != nil {
+			return r.err
+		}
+		if int(code) >= len(requestEnvelopeTypes) {
+			return fmt.Errorf("unknown request envelope code %d", code)
+		}
+		v.EnvelopeType, v.Payload = requestEnvelopeTypes[code], r.rest()
+	case *ports.ResponseEnvelope:
+		code := r.byte()
+		if r.err != nil {
+			return r.err
+		}
+		if int(code) >= len(responseEnvelopeTypes) {
+			return fmt.Errorf("unknown response envelope code %d", code)
+		}
+		v.EnvelopeType, v.Payload = responseEnvelopeTypes[code], r.rest()
+	case *ports.PlayerInput:
+		*v = r.playerInput()
+	case *ports.GameUpdatePayload:
+		*v = r.gameUpdate()
+	case *ports.GameUpdateDeltaPayload:
+		*v = r.gameUpdateDelta()
+	case *ports.SnapshotAckPayload:
+		v.Sequence = uint32(r.uvarint())
+	case *ports.StaticDataPayload:
+		*v = r.staticData()
+	case *ports.StaticDataDeltaPayload:
+		*v = r.staticDataDelta()
+	default:
+		return json.Unmarshal(data, v)
+	}
+	return r.err
+}
+
+func envelopeCode[T comparable](types []T, envelopeType T) (byte, error) {
+	for i, t := range types {
+		if t == envelopeType {
+			return byte(i), nil
+		}
+	}
+	return 0, fmt.Errorf("no binary code for envelope type %v", envelopeType)
+}
+
+const (
+	inputSprint uint8 = 1 << iota
+	inputSwitchWeapon
+	inputReload
+	inputFastReload
+	inputFire
+	inputToggleFlashlight
+)
+
+const (
+	gameUpdateSurvival uint8 = 1 << iota
+	gameUpdateRound
+	gameUpdateZone
+)
+
+const (
+	movementSet uint8 = 1 << iota
+	movementCanSprint
+	movementStunned
+)
+
+type binaryWriter struct {
+	buf []byte
+}
+
+func (w *binaryWriter) byte(b byte) {
+	w.buf = append(w.buf, b)
+}
+
+func (w *binaryWriter) bool(b bool) {
+	if b {
+		w.byte(1)
+	} else {
+		w.byte(0)
+	}
+}
+
+func (w *binaryWriter) uint16(v uint16) {
+	w.buf = binary.LittleEndian.AppendUint16(w.buf, v)
+}
+
+func (w *binaryWriter) uvarint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *binaryWriter) varint(v int64) {
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+func (w *binaryWriter) float32(v float64) {
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, math.Float32bits(float32(v)))
+}
+
+func (w *binaryWriter) string(s string) {
+	w.uvarint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *binaryWriter) playerInput(input ports.PlayerInput) {
+	w.inputFields(input)
+	w.uvarint(uint64(len(input.Previous)))
+	for _, previous := range input.Previous {
+		w.inputFields(previous)
+	}
+}
+
+// inputFields writes an input without the previous inputs it resends.
+func (w *binaryWriter) inputFields(input ports.PlayerInput) {
+	w.float32(input.MoveVertical)
+	w.float32(input.MoveHorizontal)
+	w.float32(input.LookHorizontal)
+	w.byte(uint8(input.MovementType))
+	w.byte(flag(input.Sprint, inputSprint) | flag(input.SwitchWeapon, inputSwitchWeapon) |
+		flag(input.Reload, inputReload) | flag(input.FastReload, inputFastReload) |
+		flag(input.Fire, inputFire) | flag(input.ToggleFlashlight, inputToggleFlashlight))
+	w.varint(input.Timestamp)
+	w.uvarint(uint64(input.Sequence))
+	w.uvarint(uint64(input.InterpolationDelay))
+}
+
+func (w *binaryWriter) gameUpdate(update ports.GameUpdatePayload) {
+	w.uvarint(uint64(update.Sequence))
+	w.playerInfo(update.Me)
+	w.movement(update.Movement)
+	w.uvarint(uint64(len(update.Views)))
+	for _, view := range update.Views {
+		w.playerInfo(view)
+	}
+	w.updateInfo(update.Sounds, update.Survival, update.Round, update.Zone, update.Timestamp)
+}
+
+func (w *binaryWriter) gameUpdateDelta(delta ports.GameUpdateDeltaPayload) {
+	w.uvarint(uint64(delta.Sequence))
+	w.uvarint(uint64(delta.Baseline))
+	w.playerInfo(delta.Me)
+	w.movement(delta.Movement)
+	w.uvarint(uint64(len(delta.Views)))
+	for _, view := range delta.Views {
+		w.uvarint(view.ID)
+		w.byte(uint8(view.Fields))
+		if view.Fields&ports.PlayerFieldPosition != 0 {
+			w.varint(int64(view.DX))
+			w.varint(int64(view.DY))
+		}
+		if view.Fields&ports.PlayerFieldDir != 0 {
+			w.uint16(view.Dir)
+		}
+		if view.Fields&ports.PlayerFieldHealth != 0 {
+			w.varint(int64(view.Health))
+		}
+		if view.Fields&ports.PlayerFieldEnemy != 0 {
+			w.bool(view.Enemy)
+		}
+		if view.Fields&ports.PlayerFieldTeam != 0 {
+			w.varint(int64(view.Team))
+		}
+	}
+	w.uvarint(uint64(len(delta.Removed)))
+	for _, id := range delta.Removed {
+		w.uvarint(id)
+	}
+	w.updateInfo(delta.Sounds, delta.Survival, delta.Round, delta.Zone, delta.Timestamp)
+}
+
+// updateInfo writes what full and delta game updates both send as is.
+func (w *binaryWriter) updateInfo(sounds []ports.SoundInfo, survival *ports.SurvivalInfo, round *ports.RoundInfo, zone *ports.ZoneInfo, timestamp int64) {
+	w.uvarint(uint64(len(sounds)))
+	for _, sound := range sounds {
+		w.string(sound.Kind)
+		w.float32(sound.Dir)
+		w.float32(sound.Loudness)
+	}
+
+	w.byte(flag(survival != nil, gameUpdateSurvival) | flag(round != nil, gameUpdateRound) |
+		flag(zone != nil, gameUpdateZone))
+	if survival != nil {
+		w.string(survival.Phase)
+		w.varint(int64(survival.Wave))
+		w.varint(int64(survival.TotalWaves))
+		w.float32(survival.Countdown)
+		w.varint(int64(survival.EnemiesRemaining))
+	}
+	if round != nil {
+		w.string(round.Mode)
+		w.string(round.Phase)
+		w.varint(int64(round.Round))
+		w.float32(round.Timer)
+		w.uvarint(round.Winner)
+		w.varint(int64(round.WinnerTeam))
+	}
+	if zone != nil {
+		w.float32(zone.X)
+		w.float32(zone.Y)
+		w.float32(zone.Radius)
+		w.float32(zone.NextX)
+		w.float32(zone.NextY)
+		w.float32(zone.NextRadius)
+		w.float32(zone.TimeToShrink)
+		w.bool(zone.Shrinking)
+		w.varint(int64(zone.Phase))
+		w.varint(int64(zone.Damage))
+	}
+	w.varint(timestamp)
+}
+
+func (w *binaryWriter) playerInfo(info ports.PlayerInfo) {
+	w.uvarint(info.ID)
+	w.float32(info.X)
+	w.float32(info.Y)
+	w.float32(info.Dir)
+	w.varint(int64(info.Health))
+	w.bool(info.Enemy)
+	w.varint(int64(info.Team))
+	w.varint(int64(info.Stamina))
+	w.uvarint(uint64(len(info.Effects)))
+	for _, effect := range info.Effects {
+		w.string(effect.Kind)
+		w.float32(effect.Remaining)
+		w.varint(int64(effect.Stacks))
+	}
+}
+
+func (w *binaryWriter) movement(movement *ports.MovementInfo) {
+	if movement == nil {
+		w.byte(0)
+		return
+	}
+	w.byte(movementSet | flag(movement.CanSprint, movementCanSprint) | flag(movement.Stunned, movementStunned))
+	w.uvarint(uint64(movement.LastInput))
+	w.float32(movement.VX)
+	w.float32(movement.VY)
+	w.float32(movement.Speed)
+	w.float32(movement.RotationSpeed)
+	w.float32(movement.Radius)
+	w.float32(movement.Acceleration)
+	w.float32(movement.Friction)
+	w.float32(movement.MaxSpeed)
+}
+
+func (w *binaryWriter) staticData(static ports.StaticDataPayload) {
+	w.uvarint(uint64(len(static.Colliders)))
+	for _, collider := range static.Colliders {
+		w.collider(collider)
+	}
+	w.float32(static.MapWidth)
+	w.float32(static.MapHeight)
+	w.varint(int64(static.Floor))
+	w.varint(int64(static.FloorCount))
+	w.uvarint(uint64(len(static.Stairs)))
+	for _, stair := range static.Stairs {
+		w.float32(stair.X)
+		w.float32(stair.Y)
+		w.float32(stair.HalfX)
+		w.float32(stair.HalfY)
+		w.string(stair.Kind)
+		w.varint(int64(stair.To))
+	}
+}
+
+func (w *binaryWriter) staticDataDelta(delta ports.StaticDataDeltaPayload) {
+	w.uvarint(uint64(len(delta.Removed)))
+	for _, id := range delta.Removed {
+		w.uvarint(id)
+	}
+	w.uvarint(uint64(len(delta.Added)))
+	for _, collider := range delta.Added {
+		w.collider(collider)
+	}
+}
+
+func (w *binaryWriter) collider(collider ports.Collider) {
+	w.uvarint(collider.ID)
+	w.float32(collider.X)
+	w.float32(collider.Y)
+	w.float32(collider.HalfX)
+	w.float32(collider.HalfY)
+	w.float32(collider.Radius)
+	w.byte(collider.ShapeType)
+	w.float32(collider.Rotation)
+	w.float32(collider.Height)
+	w.float32(collider.BaseElevation)
+}
+
+// flag returns bit when set, 0 otherwise, for packing bools into one byte.
+func flag(set bool, bit uint8) uint8 {
+	if set {
+		return bit
+	}
+	return 0
+}
+
+// binaryReader reads the fields written by binaryWriter. The first error
+// sticks, later reads return zero values.
+type binaryReader struct {
+	buf []byte
+	err error
+}
+
+func (r *binaryReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) < 1 {
+		r.err = ErrBinaryTruncated
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *binaryReader) bool() bool {
+	return r.byte() != 0
+}
+
+func (r *binaryReader) uint16() uint16 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) < 2 {
+		r.err = ErrBinaryTruncated
+		return 0
+	}
+	v := binary.LittleEndian.Uint16(r.buf)
+	r.buf = r.buf[2:]
+	return v
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = ErrBinaryTruncated
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *binaryReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = ErrBinaryTruncated
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *binaryReader) int() int {
+	return int(r.varint())
+}
+
+func (r *binaryReader) float32() float64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) < 4 {
+		r.err = ErrBinaryTruncated
+		return 0
+	}
+	v := math.Float32frombits(binary.LittleEndian.Uint32(r.buf))
+	r.buf = r.buf[4:]
+	return float64(v)
+}
+
+func (r *binaryReader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.buf[:n])
+	r.buf = r.buf[n:]
+	return s
+}
+
+// count reads the length of a list, which cannot be longer than the bytes
+// left since every element takes at least one.
+func (r *binaryReader) count() int {
+	n := r.uvarint()
+	if r.err == nil && n > uint64(len(r.buf)) {
+		r.err = ErrBinaryTruncated
+		return 0
+	}
+	return int(n)
+}
+
+func (r *binaryReader) rest() []byte {
+	rest := r.buf
+	r.buf = nil
+	return rest
+}
+
+func (r *binaryReader) playerInput() ports.PlayerInput {
+	input := r.inputFields()
+	if n := r.count(); n > 0 {
+		input.Previous = make([]ports.PlayerInput, n)
+		for i := range input.Previous {
+			input.Previous[i] = r.inputFields()
+		}
+	}
+	return input
+}
+
+func (r *binaryReader) inputFields() ports.PlayerInput {
+	input := ports.PlayerInput{
+		MoveVertical:   r.float32(),
+		MoveHorizontal: r.float32(),
+		LookHorizontal: r.float32(),
+		MovementType:   ports.MovementType(r.byte()),
+	}
+	packed := r.byte()
+	input.Sprint = packed&inputSprint != 0
+	input.SwitchWeapon = packed&inputSwitchWeapon != 0
+	input.Reload = packed&inputReload != 0
+	input.FastReload = packed&inputFastReload != 0
+	input.Fire = packed&inputFire != 0
+	input.ToggleFlashlight = packed&inputToggleFlashlight != 0
+	input.Timestamp = r.varint()
+	input.Sequence = uint32(r.uvarint())
+	input.InterpolationDelay = uint32(r.uvarint())
+	return input
+}
+
+func (r *binaryReader) gameUpdate() ports.GameUpdatePayload {
+	update := ports.GameUpdatePayload{Sequence: uint32(r.uvarint()), Me: r.playerInfo(), Movement: r.movement()}
+	update.Views = make([]ports.PlayerInfo, r.count())
+	for i := range update.Views {
+		update.Views[i] = r.playerInfo()
+	}
+	update.Sounds, update.Survival, update.Round, update.Zone, update.Timestamp = r.updateInfo()
+	return update
+}
+
+func (r *binaryReader) gameUpdateDelta() ports.GameUpdateDeltaPayload {
+	delta := ports.GameUpdateDeltaPayload{
+		Sequence: uint32(r.uvarint()),
+		Baseline: uint32(r.uvarint()),
+		Me:       r.playerInfo(),
+		Movement: r.movement(),
+	}
+	if n := r.count(); n > 0 {
+		delta.Views = make([]ports.PlayerDelta, n)
+		for i := range delta.Views {
+			view := ports.PlayerDelta{ID: r.uvarint(), Fields: ports.PlayerFields(r.byte())}
+			if view.Fields&ports.PlayerFieldPosition != 0 {
+				view.DX, view.DY = int32(r.varint()), int32(r.varint())
+			}
+			if view.Fields&ports.PlayerFieldDir != 0 {
+				view.Dir = r.uint16()
+			}
+			if view.Fields&ports.PlayerFieldHealth != 0 {
+				view.Health = r.int()
+			}
+			if view.Fields&ports.PlayerFieldEnemy != 0 {
+				view.Enemy = r.bool()
+			}
+			if view.Fields&ports.PlayerFieldTeam != 0 {
+				view.Team = r.int()
+			}
+			delta.Views[i] = view
+		}
+	}
+	if n := r.count(); n > 0 {
+		delta.Removed = make([]uint64, n)
+		for i := range delta.Removed {
+			delta.Removed[i] = r.uvarint()
+		}
+	}
+	delta.Sounds, delta.Survival, delta.Round, delta.Zone, delta.Timestamp = r.updateInfo()
+	return delta
+}
+
+func (r *binaryReader) movement() *ports.MovementInfo {
+	packed := r.byte()
+	if packed&movementSet == 0 {
+		return nil
+	}
+	return &ports.MovementInfo{
+		LastInput:     uint32(r.uvarint()),
+		VX:            r.float32(),
+		VY:            r.float32(),
+		Speed:         r.float32(),
+		RotationSpeed: r.float32(),
+		Radius:        r.float32(),
+		Acceleration:  r.float32(),
+		Friction:      r.float32(),
+		MaxSpeed:      r.float32(),
+		CanSprint:     packed&movementCanSprint != 0,
+		Stunned:       packed&movementStunned != 0,
+	}
+}
+
+func (r *binaryReader) updateInfo() (sounds []ports.SoundInfo, survival *ports.SurvivalInfo, round *ports.RoundInfo, zone *ports.ZoneInfo, timestamp int64) {
+	if n := r.count(); n > 0 {
+		sounds = make([]ports.SoundInfo, n)
+		for i := range sounds {
+			sounds[i] = ports.SoundInfo{Kind: r.string(), Dir: r.float32(), Loudness: r.float32()}
+		}
+	}
+
+	packed := r.byte()
+	if packed&gameUpdateSurvival != 0 {
+		survival = &ports.SurvivalInfo{
+			Phase:            r.string(),
+			Wave:             r.int(),
+			TotalWaves:       r.int(),
+			Countdown:        r.float32(),
+			EnemiesRemaining: r.int(),
+		}
+	}
+	if packed&gameUpdateRound != 0 {
+		round = &ports.RoundInfo{
+			Mode:       r.string(),
+			Phase:      r.string(),
+			Round:      r.int(),
+			Timer:      r.float32(),
+			Winner:     r.uvarint(),
+			WinnerTeam: r.int(),
+		}
+	}
+	if packed&gameUpdateZone != 0 {
+		zone = &ports.ZoneInfo{
+			X:            r.float32(),
+			Y:            r.float32(),
+			Radius:       r.float32(),
+			NextX:        r.float32(),
+			NextY:        r.float32(),
+			NextRadius:   r.float32(),
+			TimeToShrink: r.float32(),
+			Shrinking:    r.bool(),
+			Phase:        r.int(),
+			Damage:       r.int(),
+		}
+	}
+	timestamp = r.varint()
+	return sounds, survival, round, zone, timestamp
+}
+
+func (r *binaryReader) playerInfo() ports.PlayerInfo {
+	info := ports.PlayerInfo{
+		ID:      r.uvarint(),
+		X:       r.float32(),
+		Y:       r.float32(),
+		Dir:     r.float32(),
+		Health:  r.int(),
+		Enemy:   r.bool(),
+		Team:    r.int(),
+		Stamina: r.int(),
+	}
+	if n := r.count(); n > 0 {
+		info.Effects = make([]ports.EffectInfo, n)
+		for i := range info.Effects {
+			info.Effects[i] = ports.EffectInfo{Kind: r.string(), Remaining: r.float32(), Stacks: r.int()}
+		}
+	}
+	return info
+}
+
+func (r *binaryReader) staticData() ports.StaticDataPayload {
+	static := ports.StaticDataPayload{Colliders: make([]ports.Collider, r.count())}
+	for i := range static.Colliders {
+		static.Colliders[i] = r.collider()
+	}
+	static.MapWidth = r.float32()
+	static.MapHeight = r.float32()
+	static.Floor = r.int()
+	static.FloorCount = r.int()
+	if n := r.count(); n > 0 {
+		static.Stairs = make([]ports.Stair, n)
+		for i := range static.Stairs {
+			static.Stairs[i] = ports.Stair{
+				X:     r.float32(),
+				Y:     r.float32(),
+				HalfX: r.float32(),
+				HalfY: r.float32(),
+				Kind:  r.string(),
+				To:    r.int(),
+			}
+		}
+	}
+	return static
+}
+
+func (r *binaryReader) staticDataDelta() ports.StaticDataDeltaPayload {
+	var delta ports.StaticDataDeltaPayload
+	if n := r.count(); n > 0 {
+		delta.Removed = make([]uint64, n)
+		for i := range delta.Removed {
+			delta.Removed[i] = r.uvarint()
+		}
+	}
+	if n := r.count(); n > 0 {
+		delta.Added = make([]ports.Collider, n)
+		for i := range delta.Added {
+			delta.Added[i] = r.collider()
+		}
+	}
+	return delta
+}
+
+func (r *binaryReader) collider() ports.Collider {
+	return ports.Collider{
+		ID:            r.uvarint(),
+		X:             r.float32(),
+		Y:             r.float32(),
+		HalfX:         r.float32(),
+		HalfY:         r.float32(),
+		Radius:        r.float32(),
+		ShapeType:     r.byte(),
+		Rotation:      r.float32(),
+		Height:        r.float32(),
+		BaseElevation: r.float32(),
+	}
+}
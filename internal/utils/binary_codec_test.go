@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"survival/internal/engine/ports"
+)
+
+// binary floats are 32 bit, so the test values are exactly representable
+var testGameUpdate = ports.GameUpdatePayload{
+	Me: ports.PlayerInfo{
+		ID: 7, X: 120.5, Y: 64.25, Dir: 1.5, Health: 80, Team: 2, Stamina: 45,
+		Effects: []ports.EffectInfo{{Kind: "bleed", Remaining: 2.5, Stacks: 3}},
+	},
+	Movement: &ports.MovementInfo{
+		LastInput: 512, VX: -3.5, VY: 12.25, Speed: 5, RotationSpeed: 3, Radius: 0.5,
+		Acceleration: 200, Friction: 60, MaxSpeed: 25, CanSprint: true,
+	},
+	Views: []ports.PlayerInfo{
+		{ID: 8, X: 130, Y: 60, Dir: -0.5, Health: 100, Team: 1},
+		{ID: 300, X: 10, Y: 20, Health: 45, Enemy: true},
+	},
+	Sounds:    []ports.SoundInfo{{Kind: ports.SoundKindGunshot, Dir: 0.25, Loudness: 0.75}},
+	Round:     &ports.RoundInfo{Mode: "team_deathmatch", Phase: ports.RoundPhaseLive, Round: 3, Timer: 42.5, WinnerTeam: 1},
+	Zone:      &ports.ZoneInfo{X: 500, Y: 400, Radius: 300, NextX: 450, NextY: 420, NextRadius: 150, TimeToShrink: 12, Shrinking: true, Phase: 2, Damage: 5},
+	Timestamp: 1760000000123,
+}
+
+func TestBinaryCodec_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		decoded func() any
+	}{
+		{
+			name: "player input",
+			data: ports.PlayerInput{MoveVertical: -1, MoveHorizontal: 0.5, MovementType: ports.MovementTypeRelative, Sprint: true, Fire: true, Timestamp: 1760000000000, Sequence: 300, InterpolationDelay: 100,
+				Previous: []ports.PlayerInput{{MoveVertical: -1, Timestamp: 1759999999984, Sequence: 299}}},
+			decoded: func() any { return &ports.PlayerInput{} },
+		},
+		{
+			name:    "game update",
+			data:    testGameUpdate,
+			decoded: func() any { return &ports.GameUpdatePayload{} },
+		},
+		{
+			name: "static data",
+			data: ports.StaticDataPayload{
+				Colliders:  []ports.Collider{{ID: 12, X: 50, Y: 60, HalfX: 5, HalfY: 1, ShapeType: 1, Height: 3}},
+				MapWidth:   1200,
+				MapHeight:  800,
+				Floor:      1,
+				FloorCount: 2,
+				Stairs:     []ports.Stair{{X: 700, Y: 540, HalfX: 20, HalfY: 10, Kind: ports.StairKindLadder}},
+			},
+			decoded: func() any { return &ports.StaticDataPayload{} },
+		},
+		{
+			name:    "static data delta",
+			data:    ports.StaticDataDeltaPayload{Removed: []uint64{3, 900}},
+			decoded: func() any { return &ports.StaticDataDeltaPayload{} },
+		},
+		{
+			name: "game update delta",
+			data: ports.GameUpdateDeltaPayload{
+				Sequence: 40,
+				Baseline: 38,
+				Me:       testGameUpdate.Me,
+				Movement: &ports.MovementInfo{LastInput: 7, Speed: 2.5, RotationSpeed: 3, Radius: 0.5, Stunned: true},
+				Views: []ports.PlayerDelta{
+					{ID: 8, Fields: ports.PlayerFieldPosition | ports.PlayerFieldDir, DX: -3, DY: 120, Dir: 65535},
+					{ID: 9, Fields: ports.PlayerFieldAll, DX: 6400, DY: 3200, Health: 100, Enemy: true, Team: 2},
+				},
+				Removed:   []uint64{300},
+				Zone:      testGameUpdate.Zone,
+				Timestamp: testGameUpdate.Timestamp,
+			},
+			decoded: func() any { return &ports.GameUpdateDeltaPayload{} },
+		},
+		{
+			name:    "snapshot ack",
+			data:    ports.SnapshotAckPayload{Sequence: 1 << 20},
+			decoded: func() any { return &ports.SnapshotAckPayload{} },
+		},
+		{
+			name:    "other payloads stay JSON",
+			data:    ports.ErrorPayload{Code: ports.ErrorCodeBadRequest, Message: "no such item"},
+			decoded: func() any { return &ports.ErrorPayload{} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewBinaryCodec()
+
+			encoded, err := c.Encode(tt.data)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			decoded := tt.decoded()
+			if err := c.Decode(encoded, decoded); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got := reflect.ValueOf(decoded).Elem().Interface(); !reflect.DeepEqual(got, tt.data) {
+				t.Errorf("Decode() got = %+v, want %+v", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestBinaryCodec_Envelope(t *testing.T) {
+	c := NewBinaryCodec()
+	payload, err := c.Encode(testGameUpdate)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	encoded, err := c.Encode(ports.ResponseEnvelope{EnvelopeType: ports.GameUpdateEnvelope, Payload: payload})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	var envelope ports.ResponseEnvelope
+	if err := c.Decode(encoded, &envelope); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if envelope.EnvelopeType != ports.GameUpdateEnvelope || string(envelope.Payload) != string(payload) {
+		t.Errorf("Decode() got = %s %x, want %s %x", envelope.EnvelopeType, envelope.Payload, ports.GameUpdateEnvelope, payload)
+	}
+
+	jsonPayload, _ := json.Marshal(testGameUpdate)
+	if len(encoded) >= len(jsonPayload)/2 {
+		t.Errorf("Expected the binary game update under half the JSON size, got %d bytes against %d", len(encoded), len(jsonPayload))
+	}
+
+	if _, err := c.Encode(ports.RequestEnvelope{EnvelopeType: "unknown"}); err == nil {
+		t.Error("Expected an envelope type without code to be rejected")
+	}
+	if err := c.Decode([]byte{255}, &ports.RequestEnvelope{}); err == nil {
+		t.Error("Expected an unknown envelope code to be rejected")
+	}
+}
+
+func TestBinaryCodec_DecodeTruncated(t *testing.T) {
+	c := NewBinaryCodec()
+	encoded, err := c.Encode(testGameUpdate)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	for _, n := range []int{0, 1, len(encoded) / 2, len(encoded) - 1} {
+		var update ports.GameUpdatePayload
+		if err := c.Decode(encoded[:n], &update); !errors.Is(err, ErrBinaryTruncated) {
+			t.Errorf("Decode() of %d bytes error = %v, want %v", n, err, ErrBinaryTruncated)
+		}
+	}
+}
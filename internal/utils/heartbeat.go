@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"survival/internal/engine/ports"
+)
+
+const (
+	// clockSamples is the number of latest pings the clock offset is picked from.
+	clockSamples = 8
+	// rttSmoothing and jitterSmoothing are the weights of a new sample in the
+	// smoothed round trip time and jitter, as in RFC 6298 and RFC 3550.
+	rttSmoothing    = 1.0 / 8
+	jitterSmoothing = 1.0 / 16
+)
+
+// PingPayload returns the payload of a ping sent at the time, which the other
+// end echoes back in its pong.
+func PingPayload(sent time.Time) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(sent.UnixNano()))
+}
+
+// PongPayload answers a ping with its payload followed by the time of the
+// answering clock, so the pinging end can tell the offset of that clock.
+func PongPayload(ping []byte, now time.Time) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte(nil), ping...), uint64(now.UnixNano()))
+}
+
+// ParsePong returns the time the ping of a pong was sent at and, when the
+// other end added it, its own time on answering.
+func ParsePong(payload []byte) (sent, remote time.Time, ok bool) {
+	if len(payload) != 8 && len(payload) != 16 {
+		return time.Time{}, time.Time{}, false
+	}
+	sent = time.Unix(0, int64(binary.BigEndian.Uint64(payload)))
+	if len(payload) == 16 {
+		remote = time.Unix(0, int64(binary.BigEndian.Uint64(payload[8:])))
+	}
+	return sent, remote, true
+}
+
+// RTTStats keeps the round trip times measured by the pings of a connection.
+// It is safe for concurrent use.
+type RTTStats struct {
+	mu  sync.RWMutex
+	rtt ports.RTT
+}
+
+// Add adds a round trip time sample.
+func (s *RTTStats) Add(sample time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rtt.Samples == 0 {
+		s.rtt.Smoothed = sample
+	} else {
+		s.rtt.Smoothed += time.Duration(float64(sample-s.rtt.Smoothed) * rttSmoothing)
+		s.rtt.Jitter += time.Duration(float64((sample-s.rtt.Last).Abs()-s.rtt.Jitter) * jitterSmoothing)
+	}
+	s.rtt.Last = sample
+	s.rtt.Samples++
+}
+
+// Stats returns the statistics of the samples so far.
+func (s *RTTStats) Stats() ports.RTT {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rtt
+}
+
+type clockSample struct {
+	rtt    time.Duration
+	offset time.Duration
+}
+
+// ClockSync estimates how far a remote clock is ahead of the local one from
+// the pongs it answered pings with. Like NTP it assumes the answer was given
+// halfway through the round trip and trusts the quickest of the latest round
+// trips most, as the one least delayed on the way. It is safe for concurrent
+// use.
+type ClockSync struct {
+	mu      sync.RWMutex
+	samples []clockSample
+	offset  time.Duration
+}
+
+// Add adds the round trip of a ping sent and answered at local times, and
+// answered at the remote time.
+func (c *ClockSync) Add(sent, remote, received time.Time) {
+	rtt := received.Sub(sent)
+	sample := clockSample{rtt: rtt, offset: remote.Sub(sent.Add(rtt / 2))}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.samples = append(c.samples, sample)
+	if len(c.samples) > clockSamples {
+		c.samples = c.samples[1:]
+	}
+	best := c.samples[0]
+	for _, s := range c.samples[1:] {
+		if s.rtt < best.rtt {
+			best = s
+		}
+	}
+	c.offset = best.offset
+}
+
+// Offset returns the remote clock minus the local one, 0 until the first pong.
+func (c *ClockSync) Offset() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offset
+}
+
+// RemoteTime translates a local time to the remote clock.
+func (c *ClockSync) RemoteTime(local time.Time) time.Time {
+	return local.Add(c.Offset())
+}
+
+// LocalTime translates a remote time to the local clock.
+func (c *ClockSync) LocalTime(remote time.Time) time.Time {
+	return remote.Add(-c.Offset())
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPongPayload_RoundTrip(t *testing.T) {
+	sent := time.Unix(100, 5)
+	server := time.Unix(200, 7)
+
+	gotSent, gotServer, ok := ParsePong(PongPayload(PingPayload(sent), server))
+	if !ok || !gotSent.Equal(sent) || !gotServer.Equal(server) {
+		t.Errorf("ParsePong() = %v, %v, %v, want %v, %v, true", gotSent, gotServer, ok, sent, server)
+	}
+
+	// an echoed ping carries no remote time
+	gotSent, gotServer, ok = ParsePong(PingPayload(sent))
+	if !ok || !gotSent.Equal(sent) || !gotServer.IsZero() {
+		t.Errorf("ParsePong() of an echo = %v, %v, %v, want %v, zero, true", gotSent, gotServer, ok, sent)
+	}
+
+	if _, _, ok := ParsePong([]byte("ping")); ok {
+		t.Error("ParsePong() accepted a foreign payload")
+	}
+}
+
+func TestRTTStats_SmoothsSamples(t *testing.T) {
+	var stats RTTStats
+	stats.Add(80 * time.Millisecond)
+
+	rtt := stats.Stats()
+	if rtt.Smoothed != 80*time.Millisecond || rtt.Jitter != 0 || rtt.Samples != 1 {
+		t.Fatalf("Expected the first sample taken as is, got %+v", rtt)
+	}
+
+	stats.Add(160 * time.Millisecond)
+	rtt = stats.Stats()
+	if rtt.Last != 160*time.Millisecond || rtt.Smoothed != 90*time.Millisecond || rtt.Jitter != 5*time.Millisecond {
+		t.Errorf("Expected RTT 90ms and jitter 5ms after a spike, got %+v", rtt)
+	}
+}
+
+func TestClockSync_TrustsQuickestRoundTrip(t *testing.T) {
+	var clock ClockSync
+	local := time.Unix(1000, 0)
+	ahead := 3 * time.Second
+
+	// answered halfway through a quick round trip
+	clock.Add(local, local.Add(ahead+10*time.Millisecond), local.Add(20*time.Millisecond))
+	// held up on the way back
+	clock.Add(local, local.Add(ahead+10*time.Millisecond), local.Add(500*time.Millisecond))
+
+	if offset := clock.Offset(); offset != ahead {
+		t.Errorf("Offset() = %v, want %v", offset, ahead)
+	}
+	if got := clock.LocalTime(clock.RemoteTime(local)); !got.Equal(local) {
+		t.Errorf("LocalTime(RemoteTime(t)) = %v, want %v", got, local)
+	}
+}